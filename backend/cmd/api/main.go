@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	_ "time/tzdata"
 
 	"restaurantapp/config"
 	_ "restaurantapp/docs"
@@ -10,6 +12,7 @@ import (
 	"restaurantapp/internal/middleware"
 	"restaurantapp/internal/models"
 	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
 
 	"github.com/gin-gonic/gin"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -48,6 +51,35 @@ func main() {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
+	// Start background workers
+	webhookService := services.NewWebhookService(db, cfg)
+	go webhookService.Start(context.Background())
+
+	notificationService := services.NewNotificationService(db, cfg, services.DefaultNotificationChannels(cfg)...)
+	go notificationService.Start(context.Background())
+
+	go services.NewCartJanitor(db, cfg).Start(context.Background())
+
+	payments := services.NewLocalPaymentGateway(cfg)
+
+	go services.NewAcceptanceWatcher(db, cfg, payments).Start(context.Background())
+
+	go services.NewMenuPublisher(db, cfg).Start(context.Background())
+
+	go services.NewStockResetter(db, cfg).Start(context.Background())
+
+	outboxRelay := services.NewOutboxRelay(db, cfg,
+		webhookService,
+		notificationService,
+		services.NewRatingConsumer(),
+		services.NewAnalyticsConsumer(),
+		services.NewPromotionConsumer(),
+		services.NewLoyaltyConsumer(cfg),
+		services.NewInventoryConsumer(),
+		services.NewRefundPayoutConsumer(payments),
+	)
+	go outboxRelay.Start(context.Background())
+
 	// Set Gin mode based on environment
 	if cfg.Server.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -85,6 +117,21 @@ func main() {
 	reviewHandler := handlers.NewReviewHandler(db, cfg)
 	adminHandler := handlers.NewAdminHandler(db, cfg)
 	uploadHandler := handlers.NewUploadHandler(db, cfg)
+	webhookHandler := handlers.NewWebhookHandler(db, cfg, webhookService)
+	notificationHandler := handlers.NewNotificationHandler(db, cfg)
+	cartHandler := handlers.NewCartHandler(db, cfg)
+	promotionHandler := handlers.NewPromotionHandler(db, cfg)
+	walletHandler := handlers.NewWalletHandler(db, cfg)
+	refundHandler := handlers.NewRefundHandler(db, cfg)
+	groupOrderHandler := handlers.NewGroupOrderHandler(db, cfg)
+	kitchenHandler := handlers.NewKitchenHandler(db, cfg)
+	receiptHandler := handlers.NewReceiptHandler(db, cfg)
+	menuImportHandler := handlers.NewMenuImportHandler(db, cfg)
+	menuVersionHandler := handlers.NewMenuVersionHandler(db, cfg)
+	inventoryHandler := handlers.NewInventoryHandler(db, cfg)
+
+	// Retry-safe writes keyed by the Idempotency-Key header
+	idempotent := middleware.IdempotencyMiddleware(db, cfg.Idempotency.KeyTTL)
 
 	// Auth routes
 	auth := api.Group("/auth")
@@ -108,6 +155,10 @@ func main() {
 		users := protected.Group("/users")
 		{
 			users.GET("/me", authHandler.GetProfile)
+			users.GET("/me/notification-preferences", notificationHandler.GetNotificationPreferences)
+			users.PUT("/me/notification-preferences", notificationHandler.UpdateNotificationPreferences)
+			users.GET("/me/notifications", notificationHandler.GetNotifications)
+			users.GET("/me/wallet", walletHandler.GetMyWallet)
 		}
 
 		// Restaurant routes - register directly to avoid trailing slash issues
@@ -120,18 +171,81 @@ func main() {
 		menu.Use(middleware.RequireRole(string(models.RestaurantOwnerRole)))
 		{
 			menu.POST("/categories", menuHandler.CreateCategory)
+			menu.PUT("/categories/order", menuHandler.ReorderCategories)
+			menu.PUT("/categories/:id", menuHandler.UpdateCategory)
+			menu.DELETE("/categories/:id", menuHandler.DeleteCategory)
+			menu.PUT("/categories/:id/items/order", menuHandler.ReorderMenuItems)
+			menu.POST("/categories/:id/pause", menuHandler.PauseCategory)
+			menu.DELETE("/categories/:id/pause", menuHandler.ResumeCategory)
+			menu.PUT("/categories/:id/schedules", menuHandler.SetCategorySchedules)
 			menu.POST("/items", menuHandler.CreateMenuItem)
+			menu.POST("/items/bulk/price", menuHandler.BulkChangePrices)
+			menu.POST("/items/bulk/move", menuHandler.BulkMoveItems)
+			menu.POST("/items/bulk/availability", menuHandler.BulkSetAvailability)
 			menu.PUT("/items/:id", menuHandler.UpdateMenuItem)
 			menu.PATCH("/items/:id/toggle", menuHandler.ToggleItemAvailability)
+			menu.POST("/items/:id/pause", menuHandler.PauseMenuItem)
+			menu.DELETE("/items/:id/pause", menuHandler.ResumeMenuItem)
+			menu.PUT("/items/:id/schedules", menuHandler.SetMenuItemSchedules)
+			menu.PUT("/items/:id/combo", menuHandler.SetCombo)
+			menu.PUT("/items/:id/stock", inventoryHandler.SetMenuItemStock)
+			menu.PUT("/options/:id/stock", inventoryHandler.SetOptionStock)
+			menu.GET("/inventory", inventoryHandler.GetInventory)
+			menu.POST("/inventory/reset", inventoryHandler.ResetInventory)
 			menu.DELETE("/items/:id", menuHandler.DeleteMenuItem)
+			menu.POST("/import", menuImportHandler.ImportMenu)
+			menu.GET("/export", menuImportHandler.ExportMenu)
+			menu.GET("/versions", menuVersionHandler.GetMenuVersions)
+			menu.POST("/versions", menuVersionHandler.CreateMenuVersion)
+			menu.GET("/versions/:id", menuVersionHandler.GetMenuVersion)
+			menu.PUT("/versions/:id", menuVersionHandler.UpdateMenuVersion)
+			menu.DELETE("/versions/:id", menuVersionHandler.DeleteMenuVersion)
+			menu.GET("/versions/:id/diff", menuVersionHandler.DiffMenuVersion)
+			menu.POST("/versions/:id/publish", menuVersionHandler.PublishMenuVersion)
+			menu.DELETE("/versions/:id/publish", menuVersionHandler.UnscheduleMenuVersion)
+			menu.POST("/versions/:id/rollback", menuVersionHandler.RollbackMenuVersion)
+		}
+
+		// Cart routes
+		carts := protected.Group("/carts")
+		{
+			carts.GET("/", cartHandler.GetCarts)
+			carts.POST("/items", cartHandler.AddCartItem)
+			carts.GET("/:id", cartHandler.GetCart)
+			carts.DELETE("/:id", cartHandler.DeleteCart)
+			carts.PUT("/:id/items/:itemId", cartHandler.UpdateCartItem)
+			carts.DELETE("/:id/items/:itemId", cartHandler.RemoveCartItem)
+		}
+
+		// Promo code check before checkout
+		protected.POST("/promotions/validate", promotionHandler.ValidatePromotion)
+
+		// Group order routes
+		groupOrders := protected.Group("/group-orders")
+		{
+			groupOrders.POST("/", groupOrderHandler.CreateGroupOrder)
+			groupOrders.GET("/", groupOrderHandler.GetGroupOrders)
+			groupOrders.POST("/join/:code", groupOrderHandler.JoinGroupOrder)
+			groupOrders.GET("/:id", groupOrderHandler.GetGroupOrder)
+			groupOrders.DELETE("/:id", groupOrderHandler.CancelGroupOrder)
+			groupOrders.POST("/:id/items", groupOrderHandler.AddGroupOrderItem)
+			groupOrders.PUT("/:id/items/:itemId", groupOrderHandler.UpdateGroupOrderItem)
+			groupOrders.DELETE("/:id/items/:itemId", groupOrderHandler.RemoveGroupOrderItem)
+			groupOrders.POST("/:id/lock", groupOrderHandler.LockGroupOrder)
+			groupOrders.POST("/:id/unlock", groupOrderHandler.UnlockGroupOrder)
+			groupOrders.GET("/:id/split", groupOrderHandler.GetGroupOrderSplit)
 		}
 
 		// Order routes
 		orders := protected.Group("/orders")
 		{
-			orders.POST("/", orderHandler.CreateOrder)
+			orders.POST("/", idempotent, orderHandler.CreateOrder)
 			orders.GET("/", orderHandler.GetUserOrders)
 			orders.GET("/:id", orderHandler.GetOrder)
+			orders.POST("/:id/refunds", idempotent, refundHandler.CreateRefund)
+			orders.GET("/:id/refunds", refundHandler.GetOrderRefunds)
+			orders.GET("/:id/invoice", receiptHandler.GetOrderInvoice)
+			orders.POST("/:id/reorder", cartHandler.Reorder)
 		}
 
 		// Restaurant order management routes
@@ -139,7 +253,32 @@ func main() {
 		restaurantOrders.Use(middleware.RequireRole(string(models.RestaurantOwnerRole)))
 		{
 			restaurantOrders.GET("/orders", orderHandler.GetRestaurantOrders)
-			restaurantOrders.PATCH("/orders/:id/status", orderHandler.UpdateOrderStatus)
+			restaurantOrders.PATCH("/orders/:id/status", idempotent, orderHandler.UpdateOrderStatus)
+			restaurantOrders.PATCH("/orders/:id/items/:itemId/prep", kitchenHandler.UpdateItemPrep)
+			restaurantOrders.GET("/orders/:id/invoice", receiptHandler.GetRestaurantOrderInvoice)
+			restaurantOrders.GET("/orders/:id/ticket", receiptHandler.GetKitchenTicket)
+			restaurantOrders.GET("/kitchen", kitchenHandler.GetKitchenQueue)
+
+			restaurantOrders.PUT("/busy", restaurantHandler.SetBusyMode)
+			restaurantOrders.DELETE("/busy", restaurantHandler.EndBusyMode)
+
+			restaurantOrders.GET("/webhooks", webhookHandler.GetWebhooks)
+			restaurantOrders.POST("/webhooks", webhookHandler.CreateWebhook)
+			restaurantOrders.GET("/webhooks/dead-letters", webhookHandler.GetDeadLetters)
+			restaurantOrders.POST("/webhooks/deliveries/:deliveryId/redeliver", webhookHandler.RedeliverWebhook)
+			restaurantOrders.PUT("/webhooks/:id", webhookHandler.UpdateWebhook)
+			restaurantOrders.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)
+			restaurantOrders.GET("/webhooks/:id/deliveries", webhookHandler.GetWebhookDeliveries)
+
+			restaurantOrders.GET("/promotions", promotionHandler.GetRestaurantPromotions)
+			restaurantOrders.POST("/promotions", promotionHandler.CreateRestaurantPromotion)
+			restaurantOrders.PUT("/promotions/:id", promotionHandler.UpdateRestaurantPromotion)
+			restaurantOrders.DELETE("/promotions/:id", promotionHandler.DeleteRestaurantPromotion)
+			restaurantOrders.GET("/promotions/:id/redemptions", promotionHandler.GetRestaurantPromotionRedemptions)
+
+			restaurantOrders.GET("/refunds", refundHandler.GetRestaurantRefunds)
+			restaurantOrders.POST("/refunds/:id/approve", refundHandler.ApproveRestaurantRefund)
+			restaurantOrders.POST("/refunds/:id/deny", refundHandler.DenyRestaurantRefund)
 		}
 
 		// Review routes (protected)
@@ -158,8 +297,20 @@ func main() {
 			admin.GET("/users", adminHandler.GetAllUsers)
 			admin.PATCH("/users/:userId/status", adminHandler.UpdateUserStatus)
 			admin.PATCH("/users/:userId/role", adminHandler.UpdateUserRole)
+			admin.GET("/users/:userId/wallet", walletHandler.GetUserWallet)
+			admin.POST("/users/:userId/wallet/adjustments", walletHandler.AdjustUserWallet)
 			admin.GET("/orders", adminHandler.GetAllOrders)
+			admin.GET("/orders/:id/invoice", receiptHandler.GetAdminOrderInvoice)
 			admin.GET("/restaurants", adminHandler.GetAllRestaurants)
+			admin.GET("/analytics/daily", adminHandler.GetDailyAnalytics)
+			admin.GET("/promotions", promotionHandler.GetPromotions)
+			admin.POST("/promotions", promotionHandler.CreatePromotion)
+			admin.PUT("/promotions/:id", promotionHandler.UpdatePromotion)
+			admin.DELETE("/promotions/:id", promotionHandler.DeletePromotion)
+			admin.GET("/promotions/:id/redemptions", promotionHandler.GetPromotionRedemptions)
+			admin.GET("/refunds", refundHandler.GetRefunds)
+			admin.POST("/refunds/:id/approve", refundHandler.ApproveRefund)
+			admin.POST("/refunds/:id/deny", refundHandler.DenyRefund)
 		}
 
 		// Upload routes (protected)
@@ -175,6 +326,7 @@ func main() {
 	{
 		public.GET("/restaurants", restaurantHandler.GetRestaurants)
 		public.GET("/restaurants/search", restaurantHandler.SearchRestaurants)
+		public.GET("/restaurants/suggestions", restaurantHandler.GetSearchSuggestions)
 		public.GET("/restaurants/:id", restaurantHandler.GetRestaurant)
 		public.GET("/restaurants/:id/menu", menuHandler.GetRestaurantMenu)
 		public.GET("/restaurants/:id/reviews", reviewHandler.GetRestaurantReviews)
@@ -184,7 +336,7 @@ func main() {
 	api.GET("/menu-items/:id", menuHandler.GetMenuItem)
 
 	// Public review routes for creating reviews (requires auth)
-	api.POST("/restaurants/:restaurantId/reviews", middleware.AuthMiddleware(cfg.JWT.SecretKey), reviewHandler.CreateReview)
+	api.POST("/restaurants/:restaurantId/reviews", middleware.AuthMiddleware(cfg.JWT.SecretKey), idempotent, reviewHandler.CreateReview)
 
 	// File serving routes (public)
 	api.GET("/uploads/:category/:subdir/:filename", uploadHandler.ServeUploadedFile)
@@ -3,7 +3,10 @@ package config
 import (
 	"fmt"
 	"log"
+	"math"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -12,6 +15,17 @@ type Config struct {
 	Database DatabaseConfig
 	Server   ServerConfig
 	JWT      JWTConfig
+	Webhook  WebhookConfig
+	Outbox   OutboxConfig
+	Notification NotificationConfig
+	Idempotency  IdempotencyConfig
+	Cart         CartConfig
+	Loyalty      LoyaltyConfig
+	Payment      PaymentConfig
+	Refund       RefundConfig
+	Acceptance   AcceptanceConfig
+	Kitchen      KitchenConfig
+	Menu         MenuConfig
 }
 
 type DatabaseConfig struct {
@@ -34,6 +48,66 @@ type JWTConfig struct {
 	ExpiresIn string
 }
 
+type WebhookConfig struct {
+	MaxAttempts  int
+	PollInterval time.Duration
+	Timeout      time.Duration
+	BatchSize    int
+}
+
+type OutboxConfig struct {
+	PollInterval  time.Duration
+	BatchSize     int
+	LeaseDuration time.Duration
+}
+
+type NotificationConfig struct {
+	MaxAttempts  int
+	PollInterval time.Duration
+	BatchSize    int
+	SendTimeout  time.Duration
+	SinkDir      string
+}
+
+type IdempotencyConfig struct {
+	KeyTTL time.Duration
+}
+
+type CartConfig struct {
+	TTL           time.Duration
+	SweepInterval time.Duration
+}
+
+type LoyaltyConfig struct {
+	PointsPerUnit float64
+	PointValue    float64
+}
+
+type PaymentConfig struct {
+	SinkDir string
+}
+
+type RefundConfig struct {
+	Window time.Duration
+}
+
+type KitchenConfig struct {
+	AtRiskMargin time.Duration
+}
+
+type MenuConfig struct {
+	PublishInterval    time.Duration
+	StockResetInterval time.Duration
+}
+
+type AcceptanceConfig struct {
+	ReminderAfter time.Duration
+	Timeout       time.Duration
+	PauseAfter    int
+	SweepInterval time.Duration
+	BatchSize     int
+}
+
 func Load() *Config {
 	// Load .env file if it exists
 	if err := godotenv.Load(); err != nil {
@@ -58,6 +132,55 @@ func Load() *Config {
 			SecretKey: getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
 			ExpiresIn: getEnv("JWT_EXPIRES_IN", "24h"),
 		},
+		Webhook: WebhookConfig{
+			MaxAttempts:  getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			PollInterval: getEnvDuration("WEBHOOK_POLL_INTERVAL", 5*time.Second),
+			Timeout:      getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+			BatchSize:    getEnvInt("WEBHOOK_BATCH_SIZE", 20),
+		},
+		Outbox: OutboxConfig{
+			PollInterval:  getEnvDuration("OUTBOX_POLL_INTERVAL", 2*time.Second),
+			BatchSize:     getEnvInt("OUTBOX_BATCH_SIZE", 50),
+			LeaseDuration: getEnvDuration("OUTBOX_LEASE_DURATION", time.Minute),
+		},
+		Notification: NotificationConfig{
+			MaxAttempts:  getEnvInt("NOTIFICATION_MAX_ATTEMPTS", 5),
+			PollInterval: getEnvDuration("NOTIFICATION_POLL_INTERVAL", 5*time.Second),
+			BatchSize:    getEnvInt("NOTIFICATION_BATCH_SIZE", 20),
+			SendTimeout:  getEnvDuration("NOTIFICATION_SEND_TIMEOUT", 10*time.Second),
+			SinkDir:      getEnv("NOTIFICATION_SINK_DIR", "./notifications"),
+		},
+		Idempotency: IdempotencyConfig{
+			KeyTTL: getEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		},
+		Cart: CartConfig{
+			TTL:           getEnvDuration("CART_TTL", 72*time.Hour),
+			SweepInterval: getEnvDuration("CART_SWEEP_INTERVAL", time.Hour),
+		},
+		Loyalty: LoyaltyConfig{
+			PointsPerUnit: getEnvFloat("LOYALTY_POINTS_PER_UNIT", 1),
+			PointValue:    getEnvPositiveFloat("LOYALTY_POINT_VALUE", 0.01),
+		},
+		Payment: PaymentConfig{
+			SinkDir: getEnv("PAYMENT_SINK_DIR", "./payments"),
+		},
+		Refund: RefundConfig{
+			Window: getEnvDuration("REFUND_WINDOW", 7*24*time.Hour),
+		},
+		Acceptance: AcceptanceConfig{
+			ReminderAfter: getEnvDuration("ACCEPTANCE_REMINDER_AFTER", 5*time.Minute),
+			Timeout:       getEnvDuration("ACCEPTANCE_TIMEOUT", 15*time.Minute),
+			PauseAfter:    getEnvInt("ACCEPTANCE_PAUSE_AFTER", 3),
+			SweepInterval: getEnvDuration("ACCEPTANCE_SWEEP_INTERVAL", time.Minute),
+			BatchSize:     getEnvInt("ACCEPTANCE_BATCH_SIZE", 20),
+		},
+		Kitchen: KitchenConfig{
+			AtRiskMargin: getEnvDuration("KITCHEN_AT_RISK_MARGIN", 5*time.Minute),
+		},
+		Menu: MenuConfig{
+			PublishInterval:    getEnvDuration("MENU_PUBLISH_INTERVAL", time.Minute),
+			StockResetInterval: getEnvDuration("MENU_STOCK_RESET_INTERVAL", time.Minute),
+		},
 	}
 
 	return config
@@ -75,4 +198,45 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+		log.Printf("Invalid value for %s, using default %d", key, defaultValue)
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+		log.Printf("Invalid value for %s, using default %s", key, defaultValue)
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+		log.Printf("Invalid value for %s, using default %g", key, defaultValue)
+	}
+	return defaultValue
+}
+
+// getEnvPositiveFloat is getEnvFloat for values that are divided by, which
+// must be finite and above zero.
+func getEnvPositiveFloat(key string, defaultValue float64) float64 {
+	value := getEnvFloat(key, defaultValue)
+	if !(value > 0) || math.IsInf(value, 0) {
+		log.Printf("%s must be a positive number, using default %g", key, defaultValue)
+		return defaultValue
+	}
+	return value
+}
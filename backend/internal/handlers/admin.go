@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
 	"time"
 
 	"restaurantapp/config"
@@ -87,16 +86,16 @@ func (h *AdminHandler) GetDashboardStats(c *gin.Context) {
 
 	// Get order counts by status
 	h.db.DB.Model(&models.Order{}).Where("status IN ?", []string{"pending", "confirmed", "preparing"}).Count(&stats.PendingOrders)
-	h.db.DB.Model(&models.Order{}).Where("status = ?", "delivered").Count(&stats.DeliveredOrders)
+	h.db.DB.Model(&models.Order{}).Where("status IN ?", models.CompletedStatuses).Count(&stats.DeliveredOrders)
 	h.db.DB.Model(&models.Order{}).Where("status = ?", "cancelled").Count(&stats.CancelledOrders)
 
-	// Get total revenue (sum of delivered orders)
+	// Get total revenue (sum of delivered and collected orders, net of refunds)
 	var revenue struct {
 		Total float64
 	}
 	h.db.DB.Model(&models.Order{}).
-		Select("COALESCE(SUM(total_amount + delivery_fee + tax + tip), 0) as total").
-		Where("status = ?", "delivered").
+		Select("COALESCE(SUM(total_amount + delivery_fee + tax + tip - discount_amount - refunded_amount), 0) as total").
+		Where("status IN ?", models.CompletedStatuses).
 		Scan(&revenue)
 	stats.TotalRevenue = revenue.Total
 
@@ -115,6 +114,7 @@ func (h *AdminHandler) GetDashboardStats(c *gin.Context) {
 // @Produce json
 // @Security Bearer
 // @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Page cursor: empty for the first page, then the previous page's nextCursor; replaces page"
 // @Param limit query int false "Items per page" default(20)
 // @Param search query string false "Search by email or name"
 // @Param role query string false "Filter by role"
@@ -125,21 +125,15 @@ func (h *AdminHandler) GetDashboardStats(c *gin.Context) {
 // @Failure 500 {object} models.ErrorResponse
 // @Router /admin/users [get]
 func (h *AdminHandler) GetAllUsers(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	search := c.Query("search")
 	roleFilter := c.Query("role")
 	statusFilter := c.Query("status")
 
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 100 {
-		limit = 20
+	page, ok := parsePage(c, newestFirst("users"), 20)
+	if !ok {
+		return
 	}
 
-	offset := (page - 1) * limit
-
 	query := h.db.DB.Model(&models.User{})
 
 	// Apply search filter
@@ -160,17 +154,26 @@ func (h *AdminHandler) GetAllUsers(c *gin.Context) {
 		query = query.Where("is_active = ?", false)
 	}
 
-	var total int64
-	query.Count(&total)
+	total, err := page.countTotal(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to count users",
+		})
+		return
+	}
 
 	var users []models.User
-	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+	if err := page.apply(query).Find(&users).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to fetch users",
 		})
 		return
 	}
+	users, pagination := finishPage(page, users, total, func(user *models.User) (uuid.UUID, []interface{}) {
+		return user.ID, []interface{}{user.CreatedAt}
+	})
 
 	var responses []AdminUserResponse
 	for _, user := range users {
@@ -181,13 +184,8 @@ func (h *AdminHandler) GetAllUsers(c *gin.Context) {
 		"success": true,
 		"message": "Users retrieved successfully",
 		"data": gin.H{
-			"users": responses,
-			"pagination": gin.H{
-				"page":  page,
-				"limit": limit,
-				"total": total,
-				"pages": (total + int64(limit) - 1) / int64(limit),
-			},
+			"users":      responses,
+			"pagination": pagination,
 		},
 	})
 }
@@ -376,6 +374,7 @@ func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
 // @Produce json
 // @Security Bearer
 // @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Page cursor: empty for the first page, then the previous page's nextCursor; replaces page"
 // @Param limit query int false "Items per page" default(20)
 // @Param status query string false "Filter by order status"
 // @Param restaurant query string false "Filter by restaurant name"
@@ -385,27 +384,21 @@ func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
 // @Failure 500 {object} models.ErrorResponse
 // @Router /admin/orders [get]
 func (h *AdminHandler) GetAllOrders(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	statusFilter := c.Query("status")
 	restaurantFilter := c.Query("restaurant")
 
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 100 {
-		limit = 20
+	page, ok := parsePage(c, newestFirst("orders"), 20)
+	if !ok {
+		return
 	}
 
-	offset := (page - 1) * limit
-
 	query := h.db.DB.Model(&models.Order{}).
 		Preload("User").
 		Preload("Restaurant")
 
 	// Apply status filter
 	if statusFilter != "" {
-		query = query.Where("status = ?", statusFilter)
+		query = query.Where("orders.status = ?", statusFilter)
 	}
 
 	// Apply restaurant filter
@@ -414,29 +407,31 @@ func (h *AdminHandler) GetAllOrders(c *gin.Context) {
 			Where("restaurants.name ILIKE ?", "%"+restaurantFilter+"%")
 	}
 
-	var total int64
-	query.Count(&total)
+	total, err := page.countTotal(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to count orders",
+		})
+		return
+	}
 
 	var orders []models.Order
-	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&orders).Error; err != nil {
+	if err := page.apply(query).Find(&orders).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to fetch orders",
 		})
 		return
 	}
+	orders, pagination := finishPage(page, orders, total, orderPageKey)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Orders retrieved successfully",
 		"data": gin.H{
-			"orders": orders,
-			"pagination": gin.H{
-				"page":  page,
-				"limit": limit,
-				"total": total,
-				"pages": (total + int64(limit) - 1) / int64(limit),
-			},
+			"orders":     orders,
+			"pagination": pagination,
 		},
 	})
 }
@@ -449,6 +444,7 @@ func (h *AdminHandler) GetAllOrders(c *gin.Context) {
 // @Produce json
 // @Security Bearer
 // @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Page cursor: empty for the first page, then the previous page's nextCursor; replaces page"
 // @Param limit query int false "Items per page" default(20)
 // @Param search query string false "Search by restaurant name"
 // @Param status query string false "Filter by status (active/inactive)"
@@ -458,20 +454,14 @@ func (h *AdminHandler) GetAllOrders(c *gin.Context) {
 // @Failure 500 {object} models.ErrorResponse
 // @Router /admin/restaurants [get]
 func (h *AdminHandler) GetAllRestaurants(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	search := c.Query("search")
 	statusFilter := c.Query("status")
 
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 100 {
-		limit = 20
+	page, ok := parsePage(c, newestFirst("restaurants"), 20)
+	if !ok {
+		return
 	}
 
-	offset := (page - 1) * limit
-
 	query := h.db.DB.Model(&models.Restaurant{}).Preload("Owner")
 
 	// Apply search filter
@@ -486,33 +476,111 @@ func (h *AdminHandler) GetAllRestaurants(c *gin.Context) {
 		query = query.Where("is_active = ?", false)
 	}
 
-	var total int64
-	query.Count(&total)
+	total, err := page.countTotal(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to count restaurants",
+		})
+		return
+	}
 
 	var restaurants []models.Restaurant
-	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&restaurants).Error; err != nil {
+	if err := page.apply(query).Find(&restaurants).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to fetch restaurants",
 		})
 		return
 	}
+	restaurants, pagination := finishPage(page, restaurants, total, func(r *models.Restaurant) (uuid.UUID, []interface{}) {
+		return r.ID, []interface{}{r.CreatedAt}
+	})
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Restaurants retrieved successfully",
 		"data": gin.H{
 			"restaurants": restaurants,
-			"pagination": gin.H{
-				"page":  page,
-				"limit": limit,
-				"total": total,
-				"pages": (total + int64(limit) - 1) / int64(limit),
-			},
+			"pagination":  pagination,
 		},
 	})
 }
 
+// GetDailyAnalytics godoc
+// @Summary Get daily restaurant analytics
+// @Description Get per-restaurant daily order, revenue, refund and review counts built from domain events
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param from query string false "Start date (YYYY-MM-DD), defaults to 30 days ago"
+// @Param to query string false "End date (YYYY-MM-DD), defaults to today"
+// @Param restaurantId query string false "Filter by restaurant ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/analytics/daily [get]
+func (h *AdminHandler) GetDailyAnalytics(c *gin.Context) {
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -30)
+
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "Invalid from date, expected YYYY-MM-DD",
+			})
+			return
+		}
+		from = parsed
+	}
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "Invalid to date, expected YYYY-MM-DD",
+			})
+			return
+		}
+		to = parsed
+	}
+
+	query := h.db.DB.Model(&models.RestaurantDailyStat{}).
+		Where("date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02"))
+
+	if value := c.Query("restaurantId"); value != "" {
+		restaurantID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "Invalid restaurant ID",
+			})
+			return
+		}
+		query = query.Where("restaurant_id = ?", restaurantID)
+	}
+
+	var stats []models.RestaurantDailyStat
+	if err := query.Order("date DESC, restaurant_id").Find(&stats).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to fetch analytics",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Analytics retrieved successfully",
+		Data:    stats,
+	})
+}
+
 func (h *AdminHandler) toAdminUserResponse(user *models.User) AdminUserResponse {
 	return AdminUserResponse{
 		ID:        user.ID,
@@ -8,6 +8,7 @@ import (
 	"restaurantapp/internal/middleware"
 	"restaurantapp/internal/models"
 	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
 	"restaurantapp/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -53,6 +54,7 @@ type UserResponse struct {
 	LastName  string    `json:"lastName"`
 	Phone     string    `json:"phone"`
 	Role      string    `json:"role"`
+	Allergens []models.Allergen `json:"allergens,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
@@ -281,6 +283,16 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
+	allergens, err := services.UserAllergens(h.db.DB, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch allergen preferences",
+			"error":   err.Error(),
+		})
+		return
+	}
+
 	userResponse := &UserResponse{
 		ID:        user.ID,
 		Email:     user.Email,
@@ -288,6 +300,7 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 		LastName:  user.LastName,
 		Phone:     user.Phone,
 		Role:      string(user.Role),
+		Allergens: allergens,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
 	}
@@ -301,7 +314,7 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 
 // UpdateProfile godoc
 // @Summary Update user profile
-// @Description Update current user profile information
+// @Description Update current user profile information. allergens replaces the allergens the user avoids, which checkout warns about; leave it out to keep them
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -324,9 +337,10 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	}
 
 	var req struct {
-		FirstName string `json:"firstName"`
-		LastName  string `json:"lastName"`
-		Phone     string `json:"phone"`
+		FirstName string    `json:"firstName"`
+		LastName  string    `json:"lastName"`
+		Phone     string    `json:"phone"`
+		Allergens *[]string `json:"allergens"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -358,7 +372,27 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		user.Phone = req.Phone
 	}
 
-	if err := h.db.DB.Save(&user).Error; err != nil {
+	var allergens []models.Allergen
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		var err error
+		if req.Allergens != nil {
+			allergens, err = services.SetUserAllergens(tx, user.ID, *req.Allergens)
+		} else {
+			allergens, err = services.UserAllergens(tx, user.ID)
+		}
+		return err
+	})
+	if err != nil {
+		if labelErr, ok := err.(*services.LabelError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": labelErr.Message,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"message": "Failed to update profile",
@@ -374,6 +408,7 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		LastName:  user.LastName,
 		Phone:     user.Phone,
 		Role:      string(user.Role),
+		Allergens: allergens,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
 	}
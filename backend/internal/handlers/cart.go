@@ -0,0 +1,889 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"time"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/middleware"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CartHandler struct {
+	db  *repository.Database
+	cfg *config.Config
+}
+
+type AddCartItemRequest struct {
+	RestaurantID        uuid.UUID                       `json:"restaurantId" binding:"required"`
+	MenuItemID          uuid.UUID                       `json:"menuItemId" binding:"required"`
+	Quantity            int                             `json:"quantity" binding:"required,min=1,max=99"`
+	Selections          []models.CustomizationSelection `json:"selections" binding:"omitempty,dive"`
+	Components          []models.ComboSelection         `json:"components" binding:"omitempty,dive"`
+	SpecialInstructions string                          `json:"specialInstructions"`
+}
+
+type UpdateCartItemRequest struct {
+	Quantity            *int                             `json:"quantity,omitempty" binding:"omitempty,min=1,max=99"`
+	Selections          *[]models.CustomizationSelection `json:"selections,omitempty"`
+	Components          *[]models.ComboSelection         `json:"components,omitempty"`
+	SpecialInstructions *string                          `json:"specialInstructions,omitempty"`
+}
+
+type CartItemResponse struct {
+	ID                  uuid.UUID                        `json:"id"`
+	MenuItemID          uuid.UUID                        `json:"menuItemId"`
+	Name                string                           `json:"name"`
+	Quantity            int                              `json:"quantity"`
+	Selections          []models.CustomizationSelection  `json:"selections"`
+	Customizations      []services.SelectedCustomization `json:"customizations"`
+	Components          []models.ComboSelection          `json:"components,omitempty"`
+	ComboItems          []models.OrderItemComponent      `json:"comboItems,omitempty"`
+	SpecialInstructions string                           `json:"specialInstructions"`
+	AddedUnitPrice      float64                          `json:"addedUnitPrice"`
+	UnitPrice           float64                          `json:"unitPrice"`
+	LineTotal           float64                          `json:"lineTotal"`
+	IsAvailable         bool                             `json:"isAvailable"`
+	Changed             bool                             `json:"changed"`
+	Issues              []string                         `json:"issues,omitempty"`
+}
+
+type CartResponse struct {
+	ID             uuid.UUID          `json:"id"`
+	RestaurantID   uuid.UUID          `json:"restaurantId"`
+	RestaurantName string             `json:"restaurantName"`
+	Items          []CartItemResponse `json:"items"`
+	Subtotal       float64            `json:"subtotal"`
+	HasChanges     bool               `json:"hasChanges"`
+	CanCheckout    bool               `json:"canCheckout"`
+	// AllergenWarnings lists items containing allergens the customer avoids
+	AllergenWarnings []services.AllergenWarning `json:"allergenWarnings"`
+	ExpiresAt        string                     `json:"expiresAt"`
+	CreatedAt        string                     `json:"createdAt"`
+	UpdatedAt        string                     `json:"updatedAt"`
+}
+
+// ReorderChange describes what happened to one item of the original order
+// when it was copied into the cart.
+type ReorderChange struct {
+	OrderItemID       uuid.UUID `json:"orderItemId"`
+	MenuItemID        uuid.UUID `json:"menuItemId"`
+	Name              string    `json:"name"`
+	Quantity          int       `json:"quantity"`
+	Status            string    `json:"status"` // unchanged, price_changed or removed
+	PreviousUnitPrice float64   `json:"previousUnitPrice"`
+	UnitPrice         float64   `json:"unitPrice,omitempty"`
+	Reason            string    `json:"reason,omitempty"`
+}
+
+type ReorderResponse struct {
+	Cart       CartResponse    `json:"cart"`
+	Changes    []ReorderChange `json:"changes"`
+	HasChanges bool            `json:"hasChanges"`
+}
+
+func NewCartHandler(db *repository.Database, cfg *config.Config) *CartHandler {
+	return &CartHandler{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// GetCarts godoc
+// @Summary List carts
+// @Description List the current user's active carts, revalidated against the current menu
+// @Tags carts
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /carts [get]
+func (h *CartHandler) GetCarts(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	var carts []models.Cart
+	if err := h.db.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("updated_at DESC").
+		Find(&carts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch carts",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	responses := []CartResponse{}
+	for i := range carts {
+		response, err := h.toCartResponse(&carts[i])
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to validate cart",
+				Error:   err.Error(),
+			})
+			return
+		}
+		responses = append(responses, response)
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Carts retrieved successfully",
+		Data:    responses,
+	})
+}
+
+// GetCart godoc
+// @Summary Get a cart
+// @Description Get a cart with every item revalidated against current availability and prices. Items that changed since they were added are flagged.
+// @Tags carts
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Cart ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /carts/{id} [get]
+func (h *CartHandler) GetCart(c *gin.Context) {
+	cart, ok := h.findCart(c)
+	if !ok {
+		return
+	}
+
+	h.respondWithCart(c, http.StatusOK, "Cart retrieved successfully", cart.ID)
+}
+
+// AddCartItem godoc
+// @Summary Add an item to a cart
+// @Description Add a menu item with its customization selections to the user's cart for the restaurant, creating the cart if needed
+// @Tags carts
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param item body AddCartItemRequest true "Item to add"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /carts/items [post]
+func (h *CartHandler) AddCartItem(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	var req AddCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var cart models.Cart
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		priced, err := services.PriceOrderItem(tx, req.RestaurantID, req.MenuItemID, req.Selections, req.Components)
+		if err != nil {
+			return err
+		}
+
+		if err := h.lockCart(tx, userID, req.RestaurantID, &cart); err != nil {
+			return err
+		}
+
+		// Merge with an identical line instead of adding a duplicate
+		var items []models.CartItem
+		if err := tx.Where("cart_id = ? AND menu_item_id = ?", cart.ID, req.MenuItemID).Find(&items).Error; err != nil {
+			return err
+		}
+		for _, item := range items {
+			if item.SpecialInstructions == req.SpecialInstructions && sameSelections(item.Selections, req.Selections) &&
+				sameComponents(item.Components, req.Components) {
+				return tx.Model(&item).Updates(map[string]interface{}{
+					"quantity":   item.Quantity + req.Quantity,
+					"name":       priced.MenuItem.Name,
+					"unit_price": priced.UnitPrice,
+				}).Error
+			}
+		}
+
+		item := models.CartItem{
+			CartID:              cart.ID,
+			MenuItemID:          req.MenuItemID,
+			Quantity:            req.Quantity,
+			Selections:          req.Selections,
+			Components:          req.Components,
+			SpecialInstructions: req.SpecialInstructions,
+			Name:                priced.MenuItem.Name,
+			UnitPrice:           priced.UnitPrice,
+		}
+		return tx.Create(&item).Error
+	})
+	if err != nil {
+		respondWithCartError(c, err, "Failed to add item to cart")
+		return
+	}
+
+	h.respondWithCart(c, http.StatusOK, "Item added to cart", cart.ID)
+}
+
+// UpdateCartItem godoc
+// @Summary Update a cart item
+// @Description Change the quantity, selections or instructions of a cart item. The item is repriced from the current menu.
+// @Tags carts
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Cart ID"
+// @Param itemId path string true "Cart item ID"
+// @Param item body UpdateCartItemRequest true "Fields to update"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /carts/{id}/items/{itemId} [put]
+func (h *CartHandler) UpdateCartItem(c *gin.Context) {
+	var req UpdateCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	cart, ok := h.findCart(c)
+	if !ok {
+		return
+	}
+	item, ok := h.findCartItem(c, cart.ID)
+	if !ok {
+		return
+	}
+
+	if req.Quantity != nil {
+		item.Quantity = *req.Quantity
+	}
+	if req.Selections != nil {
+		item.Selections = *req.Selections
+	}
+	if req.Components != nil {
+		item.Components = *req.Components
+	}
+	if req.SpecialInstructions != nil {
+		item.SpecialInstructions = *req.SpecialInstructions
+	}
+
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		priced, err := services.PriceOrderItem(tx, cart.RestaurantID, item.MenuItemID, item.Selections, item.Components)
+		if err != nil {
+			return err
+		}
+
+		item.Name = priced.MenuItem.Name
+		item.UnitPrice = priced.UnitPrice
+		if err := tx.Save(item).Error; err != nil {
+			return err
+		}
+		return h.touchCart(tx, cart)
+	})
+	if err != nil {
+		respondWithCartError(c, err, "Failed to update cart item")
+		return
+	}
+
+	h.respondWithCart(c, http.StatusOK, "Cart item updated", cart.ID)
+}
+
+// RemoveCartItem godoc
+// @Summary Remove a cart item
+// @Description Remove an item from a cart
+// @Tags carts
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Cart ID"
+// @Param itemId path string true "Cart item ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /carts/{id}/items/{itemId} [delete]
+func (h *CartHandler) RemoveCartItem(c *gin.Context) {
+	cart, ok := h.findCart(c)
+	if !ok {
+		return
+	}
+	item, ok := h.findCartItem(c, cart.ID)
+	if !ok {
+		return
+	}
+
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(item).Error; err != nil {
+			return err
+		}
+		return h.touchCart(tx, cart)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to remove cart item",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	h.respondWithCart(c, http.StatusOK, "Cart item removed", cart.ID)
+}
+
+// DeleteCart godoc
+// @Summary Delete a cart
+// @Description Empty and delete a cart
+// @Tags carts
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Cart ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /carts/{id} [delete]
+func (h *CartHandler) DeleteCart(c *gin.Context) {
+	cart, ok := h.findCart(c)
+	if !ok {
+		return
+	}
+
+	if err := h.db.DB.Delete(cart).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to delete cart",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Cart deleted successfully",
+	})
+}
+
+// Reorder godoc
+// @Summary Reorder a past order
+// @Description Replace the cart for the order's restaurant with the items of a past order, repriced from the current menu. Unavailable items are dropped and every difference is listed in changes.
+// @Tags carts
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Order ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /orders/{id}/reorder [post]
+func (h *CartHandler) Reorder(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid order ID",
+		})
+		return
+	}
+
+	var order models.Order
+	if err := h.db.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).Where("id = ? AND user_id = ?", orderID, userID).First(&order).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Order not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch order",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	var restaurant models.Restaurant
+	if err := h.db.DB.Where("id = ? AND is_active = true", order.RestaurantID).First(&restaurant).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Restaurant is no longer available",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch restaurant",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	changes := []ReorderChange{}
+	var cart models.Cart
+	err = h.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := h.lockCart(tx, userID, order.RestaurantID, &cart); err != nil {
+			return err
+		}
+		if err := tx.Where("cart_id = ?", cart.ID).Delete(&models.CartItem{}).Error; err != nil {
+			return err
+		}
+
+		for _, orderItem := range order.Items {
+			change := ReorderChange{
+				OrderItemID:       orderItem.ID,
+				MenuItemID:        orderItem.MenuItemID,
+				Name:              orderItem.Name,
+				Quantity:          orderItem.Quantity,
+				Status:            "unchanged",
+				PreviousUnitPrice: orderItem.Price,
+			}
+
+			selections := storedSelections(orderItem.CustomizationsData)
+			components := storedComponents(orderItem.Components)
+			priced, err := services.PriceOrderItem(tx, order.RestaurantID, orderItem.MenuItemID, selections, components)
+			switch e := err.(type) {
+			case nil:
+			case *services.SelectionError:
+				change.Status = "removed"
+				change.Reason = e.Message
+				changes = append(changes, change)
+				continue
+			default:
+				if err != services.ErrMenuItemUnavailable {
+					return err
+				}
+				change.Status = "removed"
+				change.Reason = "Item is no longer available"
+				changes = append(changes, change)
+				continue
+			}
+
+			change.Name = priced.MenuItem.Name
+			change.UnitPrice = priced.UnitPrice
+			if math.Abs(priced.UnitPrice-orderItem.Price) >= 0.005 {
+				change.Status = "price_changed"
+				change.Reason = fmt.Sprintf("Price changed from %.2f to %.2f", orderItem.Price, priced.UnitPrice)
+			}
+			changes = append(changes, change)
+
+			item := models.CartItem{
+				CartID:              cart.ID,
+				MenuItemID:          orderItem.MenuItemID,
+				Quantity:            orderItem.Quantity,
+				Selections:          selections,
+				Components:          components,
+				SpecialInstructions: orderItem.SpecialInstructions,
+				Name:                priced.MenuItem.Name,
+				UnitPrice:           priced.UnitPrice,
+			}
+			if err := tx.Create(&item).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondWithCartError(c, err, "Failed to rebuild cart")
+		return
+	}
+
+	if err := h.db.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).First(&cart, "id = ?", cart.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to load cart",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	cartResponse, err := h.toCartResponse(&cart)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to validate cart",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response := ReorderResponse{
+		Cart:    cartResponse,
+		Changes: changes,
+	}
+	for _, change := range changes {
+		if change.Status != "unchanged" {
+			response.HasChanges = true
+		}
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Cart rebuilt from order",
+		Data:    response,
+	})
+}
+
+// lockCart loads the user's cart for the restaurant, creating it if needed,
+// and locks it for the rest of the transaction. An expired cart is emptied and
+// starts over.
+func (h *CartHandler) lockCart(tx *gorm.DB, userID, restaurantID uuid.UUID, cart *models.Cart) error {
+	fresh := models.Cart{
+		UserID:       userID,
+		RestaurantID: restaurantID,
+		ExpiresAt:    time.Now().Add(h.cfg.Cart.TTL),
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&fresh).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND restaurant_id = ?", userID, restaurantID).
+		First(cart).Error; err != nil {
+		return err
+	}
+
+	if cart.IsExpired() {
+		if err := tx.Where("cart_id = ?", cart.ID).Delete(&models.CartItem{}).Error; err != nil {
+			return err
+		}
+	}
+
+	return h.touchCart(tx, cart)
+}
+
+// touchCart pushes the cart's expiry forward after a change.
+func (h *CartHandler) touchCart(tx *gorm.DB, cart *models.Cart) error {
+	cart.ExpiresAt = time.Now().Add(h.cfg.Cart.TTL)
+	return tx.Model(cart).Update("expires_at", cart.ExpiresAt).Error
+}
+
+func (h *CartHandler) findCart(c *gin.Context) (*models.Cart, bool) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return nil, false
+	}
+
+	cartID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid cart ID",
+		})
+		return nil, false
+	}
+
+	var cart models.Cart
+	if err := h.db.DB.Where("id = ? AND user_id = ? AND expires_at > ?", cartID, userID, time.Now()).First(&cart).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Cart not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch cart",
+				Error:   err.Error(),
+			})
+		}
+		return nil, false
+	}
+
+	return &cart, true
+}
+
+func (h *CartHandler) findCartItem(c *gin.Context, cartID uuid.UUID) (*models.CartItem, bool) {
+	itemID, err := uuid.Parse(c.Param("itemId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid cart item ID",
+		})
+		return nil, false
+	}
+
+	var item models.CartItem
+	if err := h.db.DB.Where("id = ? AND cart_id = ?", itemID, cartID).First(&item).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Cart item not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch cart item",
+				Error:   err.Error(),
+			})
+		}
+		return nil, false
+	}
+
+	return &item, true
+}
+
+func (h *CartHandler) respondWithCart(c *gin.Context, status int, message string, cartID uuid.UUID) {
+	var cart models.Cart
+	if err := h.db.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).First(&cart, "id = ?", cartID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to load cart",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.toCartResponse(&cart)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to validate cart",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(status, models.SuccessResponse{
+		Success: true,
+		Message: message,
+		Data:    response,
+	})
+}
+
+// toCartResponse revalidates every item against the current menu. Items that
+// are unavailable or whose price differs from when they were added are flagged
+// and unavailable items are left out of the subtotal.
+func (h *CartHandler) toCartResponse(cart *models.Cart) (CartResponse, error) {
+	response := CartResponse{
+		ID:           cart.ID,
+		RestaurantID: cart.RestaurantID,
+		Items:        []CartItemResponse{},
+		CanCheckout:  len(cart.Items) > 0,
+		ExpiresAt:    cart.ExpiresAt.Format("2006-01-02T15:04:05Z"),
+		CreatedAt:    cart.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:    cart.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	var restaurant models.Restaurant
+	if err := h.db.DB.Where("id = ?", cart.RestaurantID).First(&restaurant).Error; err != nil {
+		return response, err
+	}
+	response.RestaurantName = restaurant.Name
+	if !restaurant.IsActive || !restaurant.IsOpen {
+		response.CanCheckout = false
+	}
+
+	for _, item := range cart.Items {
+		itemResponse := CartItemResponse{
+			ID:                  item.ID,
+			MenuItemID:          item.MenuItemID,
+			Name:                item.Name,
+			Quantity:            item.Quantity,
+			Selections:          item.Selections,
+			Components:          item.Components,
+			SpecialInstructions: item.SpecialInstructions,
+			AddedUnitPrice:      item.UnitPrice,
+		}
+		if err := revalidateItem(h.db.DB, cart.RestaurantID, &itemResponse); err != nil {
+			return response, err
+		}
+
+		if itemResponse.IsAvailable {
+			response.Subtotal += itemResponse.LineTotal
+		} else {
+			response.CanCheckout = false
+		}
+		if itemResponse.Changed {
+			response.HasChanges = true
+		}
+
+		response.Items = append(response.Items, itemResponse)
+	}
+
+	var menuItemIDs []uuid.UUID
+	for _, item := range cart.Items {
+		menuItemIDs = append(menuItemIDs, item.MenuItemID)
+		for _, component := range item.Components {
+			menuItemIDs = append(menuItemIDs, component.MenuItemID)
+		}
+	}
+	warnings, err := services.AllergenWarnings(h.db.DB, cart.UserID, menuItemIDs)
+	if err != nil {
+		return response, err
+	}
+	response.AllergenWarnings = warnings
+
+	return response, nil
+}
+
+// revalidateItem reprices an item against the current menu, flagging it when
+// it is unavailable or its price differs from AddedUnitPrice.
+func revalidateItem(db *gorm.DB, restaurantID uuid.UUID, item *CartItemResponse) error {
+	item.Customizations = []services.SelectedCustomization{}
+	item.UnitPrice = item.AddedUnitPrice
+	item.IsAvailable = true
+
+	priced, err := services.PriceOrderItem(db, restaurantID, item.MenuItemID, item.Selections, item.Components)
+	switch e := err.(type) {
+	case nil:
+		item.Name = priced.MenuItem.Name
+		item.Customizations = priced.Customizations
+		item.ComboItems = priced.OrderComponents()
+		item.UnitPrice = priced.UnitPrice
+		if math.Abs(priced.UnitPrice-item.AddedUnitPrice) >= 0.005 {
+			item.Changed = true
+			item.Issues = append(item.Issues, fmt.Sprintf("Price changed from %.2f to %.2f", item.AddedUnitPrice, priced.UnitPrice))
+		}
+	case *services.SelectionError:
+		item.IsAvailable = false
+		item.Changed = true
+		item.Issues = append(item.Issues, e.Message)
+	default:
+		if err != services.ErrMenuItemUnavailable {
+			return err
+		}
+		item.IsAvailable = false
+		item.Changed = true
+		item.Issues = append(item.Issues, "Item is no longer available")
+	}
+
+	if item.IsAvailable {
+		item.LineTotal = item.UnitPrice * float64(item.Quantity)
+	}
+	return nil
+}
+
+// respondWithCartError maps pricing errors to 400s and everything else to a 500.
+func respondWithCartError(c *gin.Context, err error, message string) {
+	if selectionErr, ok := err.(*services.SelectionError); ok {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: selectionErr.Message,
+		})
+		return
+	}
+	if err == services.ErrMenuItemUnavailable {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Menu item not found or unavailable",
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		Success: false,
+		Message: message,
+		Error:   err.Error(),
+	})
+}
+
+func sameSelections(a, b []models.CustomizationSelection) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func sameComponents(a, b []models.ComboSelection) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// storedSelections recovers the customization selections of an order item
+// from its resolved customizations. Free-form data from orders placed without
+// selections yields none.
+func storedSelections(customizationsData string) []models.CustomizationSelection {
+	var customizations []services.SelectedCustomization
+	if err := json.Unmarshal([]byte(customizationsData), &customizations); err != nil {
+		return nil
+	}
+
+	var selections []models.CustomizationSelection
+	for _, customization := range customizations {
+		if customization.CustomizationID == uuid.Nil {
+			continue
+		}
+		selection := models.CustomizationSelection{CustomizationID: customization.CustomizationID}
+		for _, option := range customization.Options {
+			selection.OptionIDs = append(selection.OptionIDs, option.ID)
+		}
+		selections = append(selections, selection)
+	}
+	return selections
+}
+
+// storedComponents recovers what filled the slots of a combo order item.
+func storedComponents(components []models.OrderItemComponent) []models.ComboSelection {
+	var selections []models.ComboSelection
+	for _, component := range components {
+		selections = append(selections, models.ComboSelection{
+			SlotID:     component.SlotID,
+			MenuItemID: component.MenuItemID,
+			Selections: component.Selections,
+		})
+	}
+	return selections
+}
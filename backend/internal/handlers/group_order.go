@@ -0,0 +1,835 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/middleware"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GroupOrderHandler struct {
+	db  *repository.Database
+	cfg *config.Config
+}
+
+type CreateGroupOrderRequest struct {
+	RestaurantID uuid.UUID `json:"restaurantId" binding:"required"`
+}
+
+type AddGroupOrderItemRequest struct {
+	MenuItemID          uuid.UUID                       `json:"menuItemId" binding:"required"`
+	Quantity            int                             `json:"quantity" binding:"required,min=1,max=99"`
+	Selections          []models.CustomizationSelection `json:"selections" binding:"omitempty,dive"`
+	Components          []models.ComboSelection         `json:"components" binding:"omitempty,dive"`
+	SpecialInstructions string                          `json:"specialInstructions"`
+}
+
+type GroupOrderParticipantResponse struct {
+	UserID   uuid.UUID          `json:"userId"`
+	Name     string             `json:"name"`
+	IsHost   bool               `json:"isHost"`
+	Items    []CartItemResponse `json:"items"`
+	Subtotal float64            `json:"subtotal"`
+}
+
+type GroupOrderResponse struct {
+	ID             uuid.UUID                       `json:"id"`
+	HostID         uuid.UUID                       `json:"hostId"`
+	RestaurantID   uuid.UUID                       `json:"restaurantId"`
+	RestaurantName string                          `json:"restaurantName"`
+	Status         models.GroupOrderStatus         `json:"status"`
+	InviteCode     string                          `json:"inviteCode"`
+	InviteLink     string                          `json:"inviteLink"`
+	OrderID        *uuid.UUID                      `json:"orderId,omitempty"`
+	Participants   []GroupOrderParticipantResponse `json:"participants"`
+	Subtotal       float64                         `json:"subtotal"`
+	HasChanges     bool                            `json:"hasChanges"`
+	CanSubmit      bool                            `json:"canSubmit"`
+	CreatedAt      string                          `json:"createdAt"`
+	UpdatedAt      string                          `json:"updatedAt"`
+}
+
+type GroupOrderSplitResponse struct {
+	GroupOrderID uuid.UUID            `json:"groupOrderId"`
+	OrderID      uuid.UUID            `json:"orderId"`
+	Shares       []services.CostShare `json:"shares"`
+	Total        float64              `json:"total"`
+}
+
+// groupOrderError is a failed group order check with the status to respond with.
+type groupOrderError struct {
+	status  int
+	message string
+}
+
+func (e *groupOrderError) Error() string {
+	return e.message
+}
+
+func NewGroupOrderHandler(db *repository.Database, cfg *config.Config) *GroupOrderHandler {
+	return &GroupOrderHandler{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// CreateGroupOrder godoc
+// @Summary Start a group order
+// @Description Open a shared cart for a restaurant. The current user is the host; others join with the invite link.
+// @Tags group-orders
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param groupOrder body CreateGroupOrderRequest true "Restaurant to order from"
+// @Success 201 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /group-orders [post]
+func (h *GroupOrderHandler) CreateGroupOrder(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	var req CreateGroupOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var restaurant models.Restaurant
+	if err := h.db.DB.Where("id = ? AND is_active = true", req.RestaurantID).First(&restaurant).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Restaurant not found or inactive",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch restaurant",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	inviteCode, err := services.NewInviteCode()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to create invite code",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	groupOrder := models.GroupOrder{
+		HostID:       userID,
+		RestaurantID: restaurant.ID,
+		InviteCode:   inviteCode,
+		Status:       models.GroupOrderOpen,
+	}
+	err = h.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&groupOrder).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.GroupOrderParticipant{
+			GroupOrderID: groupOrder.ID,
+			UserID:       userID,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to create group order",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	h.respondWithGroupOrder(c, http.StatusCreated, "Group order created successfully", groupOrder.ID)
+}
+
+// GetGroupOrders godoc
+// @Summary List group orders
+// @Description List the open and locked group orders the current user hosts or has joined
+// @Tags group-orders
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /group-orders [get]
+func (h *GroupOrderHandler) GetGroupOrders(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	var groupOrders []models.GroupOrder
+	if err := h.db.DB.
+		Joins("JOIN group_order_participants ON group_order_participants.group_order_id = group_orders.id").
+		Where("group_order_participants.user_id = ? AND group_orders.status IN ?", userID, []models.GroupOrderStatus{models.GroupOrderOpen, models.GroupOrderLocked}).
+		Order("group_orders.updated_at DESC").
+		Find(&groupOrders).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch group orders",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	responses := []GroupOrderResponse{}
+	for i := range groupOrders {
+		response, err := h.toGroupOrderResponse(&groupOrders[i])
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to load group order",
+				Error:   err.Error(),
+			})
+			return
+		}
+		responses = append(responses, response)
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Group orders retrieved successfully",
+		Data:    responses,
+	})
+}
+
+// GetGroupOrder godoc
+// @Summary Get a group order
+// @Description Get a group order with each participant's items, revalidated against the current menu
+// @Tags group-orders
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Group order ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /group-orders/{id} [get]
+func (h *GroupOrderHandler) GetGroupOrder(c *gin.Context) {
+	userID, groupOrderID, ok := h.requestParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.checkParticipant(h.db.DB, groupOrderID, userID); err != nil {
+		respondWithGroupOrderError(c, err, "Failed to fetch group order")
+		return
+	}
+
+	h.respondWithGroupOrder(c, http.StatusOK, "Group order retrieved successfully", groupOrderID)
+}
+
+// JoinGroupOrder godoc
+// @Summary Join a group order
+// @Description Join an open group order with the code from its invite link
+// @Tags group-orders
+// @Produce json
+// @Security Bearer
+// @Param code path string true "Invite code"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /group-orders/join/{code} [post]
+func (h *GroupOrderHandler) JoinGroupOrder(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	var groupOrder models.GroupOrder
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("invite_code = ?", strings.ToLower(strings.TrimSpace(c.Param("code")))).
+			First(&groupOrder).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return &groupOrderError{status: http.StatusNotFound, message: "Group order not found"}
+			}
+			return err
+		}
+		if groupOrder.Status != models.GroupOrderOpen {
+			return &groupOrderError{status: http.StatusBadRequest, message: "Group order is no longer open"}
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.GroupOrderParticipant{
+			GroupOrderID: groupOrder.ID,
+			UserID:       userID,
+		}).Error
+	})
+	if err != nil {
+		respondWithGroupOrderError(c, err, "Failed to join group order")
+		return
+	}
+
+	h.respondWithGroupOrder(c, http.StatusOK, "Joined group order", groupOrder.ID)
+}
+
+// AddGroupOrderItem godoc
+// @Summary Add an item to a group order
+// @Description Add one of your items to an open group order
+// @Tags group-orders
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Group order ID"
+// @Param item body AddGroupOrderItemRequest true "Item to add"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /group-orders/{id}/items [post]
+func (h *GroupOrderHandler) AddGroupOrderItem(c *gin.Context) {
+	userID, groupOrderID, ok := h.requestParams(c)
+	if !ok {
+		return
+	}
+
+	var req AddGroupOrderItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	item := models.GroupOrderItem{
+		AddedByID:           userID,
+		MenuItemID:          req.MenuItemID,
+		Quantity:            req.Quantity,
+		Selections:          req.Selections,
+		Components:          req.Components,
+		SpecialInstructions: req.SpecialInstructions,
+	}
+
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		groupOrder, err := h.lockOpenGroupOrder(tx, groupOrderID, userID)
+		if err != nil {
+			return err
+		}
+
+		priced, err := services.PriceOrderItem(tx, groupOrder.RestaurantID, item.MenuItemID, item.Selections, item.Components)
+		if err != nil {
+			return err
+		}
+
+		item.GroupOrderID = groupOrder.ID
+		item.Name = priced.MenuItem.Name
+		item.UnitPrice = priced.UnitPrice
+		return tx.Create(&item).Error
+	})
+	if err != nil {
+		respondWithGroupOrderError(c, err, "Failed to add item to group order")
+		return
+	}
+
+	h.respondWithGroupOrder(c, http.StatusOK, "Item added to group order", groupOrderID)
+}
+
+// UpdateGroupOrderItem godoc
+// @Summary Update a group order item
+// @Description Change the quantity, selections or instructions of one of your items in an open group order
+// @Tags group-orders
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Group order ID"
+// @Param itemId path string true "Group order item ID"
+// @Param item body UpdateCartItemRequest true "Fields to update"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /group-orders/{id}/items/{itemId} [put]
+func (h *GroupOrderHandler) UpdateGroupOrderItem(c *gin.Context) {
+	userID, groupOrderID, ok := h.requestParams(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		groupOrder, err := h.lockOpenGroupOrder(tx, groupOrderID, userID)
+		if err != nil {
+			return err
+		}
+		item, err := h.findOwnItem(tx, c, groupOrder, userID, false)
+		if err != nil {
+			return err
+		}
+
+		if req.Quantity != nil {
+			item.Quantity = *req.Quantity
+		}
+		if req.Selections != nil {
+			item.Selections = *req.Selections
+		}
+		if req.Components != nil {
+			item.Components = *req.Components
+		}
+		if req.SpecialInstructions != nil {
+			item.SpecialInstructions = *req.SpecialInstructions
+		}
+
+		priced, err := services.PriceOrderItem(tx, groupOrder.RestaurantID, item.MenuItemID, item.Selections, item.Components)
+		if err != nil {
+			return err
+		}
+
+		item.Name = priced.MenuItem.Name
+		item.UnitPrice = priced.UnitPrice
+		return tx.Save(item).Error
+	})
+	if err != nil {
+		respondWithGroupOrderError(c, err, "Failed to update group order item")
+		return
+	}
+
+	h.respondWithGroupOrder(c, http.StatusOK, "Group order item updated", groupOrderID)
+}
+
+// RemoveGroupOrderItem godoc
+// @Summary Remove a group order item
+// @Description Remove one of your items from an open group order. The host can remove anyone's items.
+// @Tags group-orders
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Group order ID"
+// @Param itemId path string true "Group order item ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /group-orders/{id}/items/{itemId} [delete]
+func (h *GroupOrderHandler) RemoveGroupOrderItem(c *gin.Context) {
+	userID, groupOrderID, ok := h.requestParams(c)
+	if !ok {
+		return
+	}
+
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		groupOrder, err := h.lockOpenGroupOrder(tx, groupOrderID, userID)
+		if err != nil {
+			return err
+		}
+		item, err := h.findOwnItem(tx, c, groupOrder, userID, true)
+		if err != nil {
+			return err
+		}
+		return tx.Delete(item).Error
+	})
+	if err != nil {
+		respondWithGroupOrderError(c, err, "Failed to remove group order item")
+		return
+	}
+
+	h.respondWithGroupOrder(c, http.StatusOK, "Group order item removed", groupOrderID)
+}
+
+// LockGroupOrder godoc
+// @Summary Lock a group order
+// @Description Stop participants from joining or changing items so the host can submit it (host only)
+// @Tags group-orders
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Group order ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /group-orders/{id}/lock [post]
+func (h *GroupOrderHandler) LockGroupOrder(c *gin.Context) {
+	h.setStatus(c, models.GroupOrderOpen, models.GroupOrderLocked, "Group order locked")
+}
+
+// UnlockGroupOrder godoc
+// @Summary Unlock a group order
+// @Description Reopen a locked group order for changes (host only)
+// @Tags group-orders
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Group order ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /group-orders/{id}/unlock [post]
+func (h *GroupOrderHandler) UnlockGroupOrder(c *gin.Context) {
+	h.setStatus(c, models.GroupOrderLocked, models.GroupOrderOpen, "Group order unlocked")
+}
+
+// CancelGroupOrder godoc
+// @Summary Cancel a group order
+// @Description Cancel a group order that hasn't been submitted (host only)
+// @Tags group-orders
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Group order ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /group-orders/{id} [delete]
+func (h *GroupOrderHandler) CancelGroupOrder(c *gin.Context) {
+	h.setStatus(c, "", models.GroupOrderCancelled, "Group order cancelled")
+}
+
+// GetGroupOrderSplit godoc
+// @Summary Get the cost split of a group order
+// @Description Get what each participant owes for a submitted group order: their items plus a proportional share of discount, delivery fee, tax and tip
+// @Tags group-orders
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Group order ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /group-orders/{id}/split [get]
+func (h *GroupOrderHandler) GetGroupOrderSplit(c *gin.Context) {
+	userID, groupOrderID, ok := h.requestParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.checkParticipant(h.db.DB, groupOrderID, userID); err != nil {
+		respondWithGroupOrderError(c, err, "Failed to fetch group order")
+		return
+	}
+
+	var groupOrder models.GroupOrder
+	if err := h.db.DB.Preload("Participants.User").Where("id = ?", groupOrderID).First(&groupOrder).Error; err != nil {
+		respondWithGroupOrderError(c, err, "Failed to fetch group order")
+		return
+	}
+	if groupOrder.OrderID == nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "The split is available once the group order is submitted",
+		})
+		return
+	}
+
+	var order models.Order
+	if err := h.db.DB.Preload("Items").Where("id = ?", *groupOrder.OrderID).First(&order).Error; err != nil {
+		respondWithGroupOrderError(c, err, "Failed to fetch order")
+		return
+	}
+
+	names := make(map[uuid.UUID]string, len(groupOrder.Participants))
+	for _, participant := range groupOrder.Participants {
+		names[participant.UserID] = strings.TrimSpace(participant.User.FirstName + " " + participant.User.LastName)
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Group order split retrieved successfully",
+		Data: GroupOrderSplitResponse{
+			GroupOrderID: groupOrder.ID,
+			OrderID:      order.ID,
+			Shares:       services.SplitOrderCost(&order, groupOrder.HostID, names),
+			Total:        order.GrandTotal(),
+		},
+	})
+}
+
+// setStatus moves a group order from one status to another on behalf of its
+// host. An empty from allows any status that hasn't been submitted.
+func (h *GroupOrderHandler) setStatus(c *gin.Context, from, to models.GroupOrderStatus, message string) {
+	userID, groupOrderID, ok := h.requestParams(c)
+	if !ok {
+		return
+	}
+
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		var groupOrder models.GroupOrder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", groupOrderID).First(&groupOrder).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return &groupOrderError{status: http.StatusNotFound, message: "Group order not found"}
+			}
+			return err
+		}
+		if groupOrder.HostID != userID {
+			return &groupOrderError{status: http.StatusForbidden, message: "Only the host can do this"}
+		}
+
+		switch {
+		case from != "" && groupOrder.Status != from:
+			return &groupOrderError{status: http.StatusBadRequest, message: "Group order is " + string(groupOrder.Status)}
+		case from == "" && (groupOrder.Status == models.GroupOrderSubmitted || groupOrder.Status == models.GroupOrderCancelled):
+			return &groupOrderError{status: http.StatusBadRequest, message: "Group order is already " + string(groupOrder.Status)}
+		}
+
+		return tx.Model(&groupOrder).Update("status", to).Error
+	})
+	if err != nil {
+		respondWithGroupOrderError(c, err, "Failed to update group order")
+		return
+	}
+
+	h.respondWithGroupOrder(c, http.StatusOK, message, groupOrderID)
+}
+
+func (h *GroupOrderHandler) requestParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	groupOrderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid group order ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, groupOrderID, true
+}
+
+// checkParticipant fails unless the user has joined the group order.
+func (h *GroupOrderHandler) checkParticipant(tx *gorm.DB, groupOrderID, userID uuid.UUID) error {
+	var count int64
+	if err := tx.Model(&models.GroupOrderParticipant{}).
+		Where("group_order_id = ? AND user_id = ?", groupOrderID, userID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return &groupOrderError{status: http.StatusNotFound, message: "Group order not found"}
+	}
+	return nil
+}
+
+// lockOpenGroupOrder locks a group order the user participates in and checks
+// it still accepts changes.
+func (h *GroupOrderHandler) lockOpenGroupOrder(tx *gorm.DB, groupOrderID, userID uuid.UUID) (*models.GroupOrder, error) {
+	if err := h.checkParticipant(tx, groupOrderID, userID); err != nil {
+		return nil, err
+	}
+
+	var groupOrder models.GroupOrder
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", groupOrderID).First(&groupOrder).Error; err != nil {
+		return nil, err
+	}
+	if groupOrder.Status != models.GroupOrderOpen {
+		return nil, &groupOrderError{status: http.StatusBadRequest, message: "Group order is " + string(groupOrder.Status)}
+	}
+
+	return &groupOrder, nil
+}
+
+// findOwnItem loads the item in the path. Participants may only touch their own
+// items; hostMayEdit lets the host act on anyone's.
+func (h *GroupOrderHandler) findOwnItem(tx *gorm.DB, c *gin.Context, groupOrder *models.GroupOrder, userID uuid.UUID, hostMayEdit bool) (*models.GroupOrderItem, error) {
+	itemID, err := uuid.Parse(c.Param("itemId"))
+	if err != nil {
+		return nil, &groupOrderError{status: http.StatusBadRequest, message: "Invalid group order item ID"}
+	}
+
+	var item models.GroupOrderItem
+	if err := tx.Where("id = ? AND group_order_id = ?", itemID, groupOrder.ID).First(&item).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &groupOrderError{status: http.StatusNotFound, message: "Group order item not found"}
+		}
+		return nil, err
+	}
+	if item.AddedByID != userID && !(hostMayEdit && groupOrder.HostID == userID) {
+		return nil, &groupOrderError{status: http.StatusForbidden, message: "You can only change your own items"}
+	}
+
+	return &item, nil
+}
+
+func (h *GroupOrderHandler) respondWithGroupOrder(c *gin.Context, status int, message string, groupOrderID uuid.UUID) {
+	var groupOrder models.GroupOrder
+	if err := h.db.DB.Where("id = ?", groupOrderID).First(&groupOrder).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to load group order",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.toGroupOrderResponse(&groupOrder)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to validate group order",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(status, models.SuccessResponse{
+		Success: true,
+		Message: message,
+		Data:    response,
+	})
+}
+
+// toGroupOrderResponse groups items by participant and revalidates them
+// against the current menu the same way carts are.
+func (h *GroupOrderHandler) toGroupOrderResponse(groupOrder *models.GroupOrder) (GroupOrderResponse, error) {
+	response := GroupOrderResponse{
+		ID:           groupOrder.ID,
+		HostID:       groupOrder.HostID,
+		RestaurantID: groupOrder.RestaurantID,
+		Status:       groupOrder.Status,
+		InviteCode:   groupOrder.InviteCode,
+		InviteLink:   "/api/group-orders/join/" + groupOrder.InviteCode,
+		OrderID:      groupOrder.OrderID,
+		Participants: []GroupOrderParticipantResponse{},
+		CreatedAt:    groupOrder.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:    groupOrder.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	var restaurant models.Restaurant
+	if err := h.db.DB.Where("id = ?", groupOrder.RestaurantID).First(&restaurant).Error; err != nil {
+		return response, err
+	}
+	response.RestaurantName = restaurant.Name
+
+	var participants []models.GroupOrderParticipant
+	if err := h.db.DB.Preload("User").Where("group_order_id = ?", groupOrder.ID).Order("created_at ASC").Find(&participants).Error; err != nil {
+		return response, err
+	}
+	var items []models.GroupOrderItem
+	if err := h.db.DB.Where("group_order_id = ?", groupOrder.ID).Order("created_at ASC").Find(&items).Error; err != nil {
+		return response, err
+	}
+
+	indexes := make(map[uuid.UUID]int, len(participants))
+	for _, participant := range participants {
+		indexes[participant.UserID] = len(response.Participants)
+		response.Participants = append(response.Participants, GroupOrderParticipantResponse{
+			UserID: participant.UserID,
+			Name:   strings.TrimSpace(participant.User.FirstName + " " + participant.User.LastName),
+			IsHost: participant.UserID == groupOrder.HostID,
+			Items:  []CartItemResponse{},
+		})
+	}
+
+	allAvailable := true
+	for _, item := range items {
+		index, ok := indexes[item.AddedByID]
+		if !ok {
+			continue
+		}
+
+		itemResponse := CartItemResponse{
+			ID:                  item.ID,
+			MenuItemID:          item.MenuItemID,
+			Name:                item.Name,
+			Quantity:            item.Quantity,
+			Selections:          item.Selections,
+			Components:          item.Components,
+			SpecialInstructions: item.SpecialInstructions,
+			AddedUnitPrice:      item.UnitPrice,
+		}
+		if groupOrder.Status == models.GroupOrderOpen || groupOrder.Status == models.GroupOrderLocked {
+			if err := revalidateItem(h.db.DB, groupOrder.RestaurantID, &itemResponse); err != nil {
+				return response, err
+			}
+		} else {
+			itemResponse.UnitPrice = item.UnitPrice
+			itemResponse.IsAvailable = true
+			itemResponse.LineTotal = item.UnitPrice * float64(item.Quantity)
+		}
+
+		if itemResponse.IsAvailable {
+			response.Participants[index].Subtotal += itemResponse.LineTotal
+			response.Subtotal += itemResponse.LineTotal
+		} else {
+			allAvailable = false
+		}
+		if itemResponse.Changed {
+			response.HasChanges = true
+		}
+		response.Participants[index].Items = append(response.Participants[index].Items, itemResponse)
+	}
+
+	response.CanSubmit = groupOrder.Status == models.GroupOrderLocked && len(items) > 0 && allAvailable &&
+		restaurant.IsActive && restaurant.IsOpen
+
+	return response, nil
+}
+
+// respondWithGroupOrderError maps group order and pricing errors to 4xx and
+// everything else to a 500.
+func respondWithGroupOrderError(c *gin.Context, err error, message string) {
+	if groupErr, ok := err.(*groupOrderError); ok {
+		c.JSON(groupErr.status, models.ErrorResponse{
+			Success: false,
+			Message: groupErr.message,
+		})
+		return
+	}
+
+	respondWithCartError(c, err, message)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"restaurantapp/internal/middleware"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// currentOwnerRestaurant loads the restaurant owned by the authenticated user,
+// writing the error response itself when it can't.
+func currentOwnerRestaurant(c *gin.Context, db *repository.Database) (*models.Restaurant, bool) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return nil, false
+	}
+
+	var restaurant models.Restaurant
+	if err := db.DB.Where("owner_id = ?", userID).First(&restaurant).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Restaurant not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch restaurant",
+				Error:   err.Error(),
+			})
+		}
+		return nil, false
+	}
+
+	return &restaurant, true
+}
+
+// queryList reads a multi-value query parameter given either repeated
+// (?cuisine=thai&cuisine=indian) or comma-separated (?cuisine=thai,indian).
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, param := range c.QueryArray(key) {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
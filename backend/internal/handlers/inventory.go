@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InventoryHandler struct {
+	db  *repository.Database
+	cfg *config.Config
+}
+
+// StockRequest sets the stock of a menu item or option. Leaving out
+// stockCount stops tracking it; dailyStock is what it goes back to at the
+// restaurant's stockResetTime.
+type StockRequest struct {
+	StockCount        *int `json:"stockCount" binding:"omitempty,min=0"`
+	DailyStock        *int `json:"dailyStock" binding:"omitempty,min=1"`
+	LowStockThreshold int  `json:"lowStockThreshold" binding:"min=0"`
+}
+
+func NewInventoryHandler(db *repository.Database, cfg *config.Config) *InventoryHandler {
+	return &InventoryHandler{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// GetInventory godoc
+// @Summary Get inventory
+// @Description List the stock of the restaurant's tracked menu items and options
+// @Tags menu
+// @Produce json
+// @Security Bearer
+// @Param lowOnly query bool false "Only what is at or below its low-stock threshold"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/inventory [get]
+func (h *InventoryHandler) GetInventory(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	entries, err := services.ListInventory(h.db.DB, restaurant.ID, c.Query("lowOnly") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch inventory",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Inventory retrieved successfully",
+		Data:    entries,
+	})
+}
+
+// ResetInventory godoc
+// @Summary Reset daily stock
+// @Description Put every item and option with a daily stock back to it now, as the daily reset does
+// @Tags menu
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/inventory/reset [post]
+func (h *InventoryHandler) ResetInventory(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		return services.ResetDailyStock(tx, restaurant.ID, time.Now())
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to reset stock",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	entries, err := services.ListInventory(h.db.DB, restaurant.ID, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch inventory",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Daily stock reset",
+		Data:    entries,
+	})
+}
+
+// SetMenuItemStock godoc
+// @Summary Set menu item stock
+// @Description Track the stock of a menu item. Orders take from it, cancellations give it back, and at zero the item becomes unavailable until it's restocked. Leave out stockCount to stop tracking
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Menu Item ID"
+// @Param stock body StockRequest true "Stock"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/items/{id}/stock [put]
+func (h *InventoryHandler) SetMenuItemStock(c *gin.Context) {
+	h.setStock(c, "Invalid menu item ID", "Menu item not found", services.SetMenuItemStock)
+}
+
+// SetOptionStock godoc
+// @Summary Set customization option stock
+// @Description Track the stock of a customization option, like a limited topping. It works as menu item stock does, and a sold out option can't be selected
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Customization Option ID"
+// @Param stock body StockRequest true "Stock"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/options/{id}/stock [put]
+func (h *InventoryHandler) SetOptionStock(c *gin.Context) {
+	h.setStock(c, "Invalid option ID", "Option not found", services.SetOptionStock)
+}
+
+func (h *InventoryHandler) setStock(c *gin.Context, invalidID, notFound string, set func(*gorm.DB, uuid.UUID, uuid.UUID, services.StockLevel) (*services.InventoryEntry, error)) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: invalidID,
+		})
+		return
+	}
+
+	var req StockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var entry *services.InventoryEntry
+	err = h.db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		entry, err = set(tx, restaurant.ID, id, services.StockLevel{
+			StockCount:        req.StockCount,
+			DailyStock:        req.DailyStock,
+			LowStockThreshold: req.LowStockThreshold,
+		})
+		return err
+	})
+	if err != nil {
+		if stockErr, ok := err.(*services.StockError); ok {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: stockErr.Message,
+			})
+		} else if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: notFound,
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to update stock",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Stock updated",
+		Data:    entry,
+	})
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type KitchenHandler struct {
+	db  *repository.Database
+	cfg *config.Config
+}
+
+type UpdateItemPrepRequest struct {
+	Status models.ItemPrepStatus `json:"status" binding:"required"`
+}
+
+func NewKitchenHandler(db *repository.Database, cfg *config.Config) *KitchenHandler {
+	return &KitchenHandler{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// GetKitchenQueue godoc
+// @Summary Get kitchen display queue
+// @Description Active orders grouped by stage (pending, confirmed, preparing, ready_for_pickup), soonest promised first, with per-item prep state, counts of each item still to cook across open tickets, and tickets at risk of missing their estimated time
+// @Tags kitchen
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/kitchen [get]
+func (h *KitchenHandler) GetKitchenQueue(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	var orders []models.Order
+	if err := h.db.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).Preload("Items.MenuItem").
+		Where("restaurant_id = ? AND status IN ?", restaurant.ID, services.KitchenStages).
+		Find(&orders).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch orders",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Kitchen queue retrieved successfully",
+		Data:    services.BuildKitchenQueue(restaurant, orders, h.cfg.Kitchen.AtRiskMargin, time.Now()),
+	})
+}
+
+// UpdateItemPrep godoc
+// @Summary Update item prep state
+// @Description Mark an item of an accepted order as queued, started or done
+// @Tags kitchen
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Order ID"
+// @Param itemId path string true "Order item ID"
+// @Param prep body UpdateItemPrepRequest true "Prep state"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/orders/{id}/items/{itemId}/prep [patch]
+func (h *KitchenHandler) UpdateItemPrep(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid order ID",
+		})
+		return
+	}
+	itemID, err := uuid.Parse(c.Param("itemId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid order item ID",
+		})
+		return
+	}
+
+	var req UpdateItemPrepRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if !models.IsValidItemPrepStatus(req.Status) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Prep status must be queued, started or done",
+		})
+		return
+	}
+
+	var order models.Order
+	if err := h.db.DB.Select("id", "status").Where("id = ? AND restaurant_id = ?", orderID, restaurant.ID).First(&order).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Order not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch order",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+	if order.Status != models.ConfirmedStatus && order.Status != models.PreparingStatus {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Only accepted orders that aren't ready yet can be prepared",
+		})
+		return
+	}
+
+	var item models.OrderItem
+	if err := h.db.DB.Where("id = ? AND order_id = ?", itemID, order.ID).First(&item).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Order item not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch order item",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	// Timestamps record when work on the item began and ended; moving an item
+	// back clears the ones that no longer apply
+	now := time.Now()
+	updates := map[string]interface{}{"prep_status": req.Status}
+	switch req.Status {
+	case models.ItemPrepQueued:
+		updates["prep_started_at"] = nil
+		updates["prep_done_at"] = nil
+	case models.ItemPrepStarted:
+		if item.PrepStartedAt == nil {
+			updates["prep_started_at"] = now
+		}
+		updates["prep_done_at"] = nil
+	case models.ItemPrepDone:
+		if item.PrepStartedAt == nil {
+			updates["prep_started_at"] = now
+		}
+		if item.PrepDoneAt == nil {
+			updates["prep_done_at"] = now
+		}
+	}
+
+	if err := h.db.DB.Model(&item).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to update order item",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Order item prep state updated",
+		Data:    services.NewKitchenTicketItem(&item),
+	})
+}
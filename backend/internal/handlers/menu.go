@@ -1,12 +1,17 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"restaurantapp/config"
 	"restaurantapp/internal/middleware"
 	"restaurantapp/internal/models"
 	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -22,35 +27,119 @@ type CreateCategoryRequest struct {
 	Name        string `json:"name" binding:"required"`
 	Description string `json:"description"`
 	Order       int    `json:"order"`
+	Schedules   []models.MenuSchedule `json:"schedules"`
 }
 
 type CreateMenuItemRequest struct {
 	CategoryID      string   `json:"categoryId" binding:"required"`
+	SKU             string   `json:"sku" binding:"max=64"`
 	Name            string   `json:"name" binding:"required"`
 	Description     string   `json:"description"`
 	Price           float64  `json:"price" binding:"required"`
 	Image           string   `json:"image"`
 	PreparationTime int      `json:"preparationTime"`
-	Allergens       string   `json:"allergens"`
+	Allergens       []string `json:"allergens"`
+	DietaryTags     []string `json:"dietaryTags"`
+	SpiceLevel      int      `json:"spiceLevel"`
 	Calories        *int     `json:"calories,omitempty"`
 	Protein         *float64 `json:"protein,omitempty"`
 	Carbs           *float64 `json:"carbs,omitempty"`
 	Fat             *float64 `json:"fat,omitempty"`
 	Fiber           *float64 `json:"fiber,omitempty"`
 	Sodium          *float64 `json:"sodium,omitempty"`
+	// Schedules are when the item is served; on update, leaving them out
+	// keeps the current ones
+	Schedules       []models.MenuSchedule `json:"schedules"`
+}
+
+// UpdateCategoryRequest changes a category; leaving out isActive or schedules
+// keeps them as they are. Categories are ordered with the reorder endpoint.
+type UpdateCategoryRequest struct {
+	Name        string                `json:"name" binding:"required"`
+	Description string                `json:"description"`
+	IsActive    *bool                 `json:"isActive"`
+	Schedules   []models.MenuSchedule `json:"schedules"`
+}
+
+// ReorderRequest lists every category of the restaurant, or every item of a
+// category, in their new order.
+type ReorderRequest struct {
+	IDs []uuid.UUID `json:"ids" binding:"required"`
+}
+
+// BulkPriceRequest changes the prices of menu items by a percentage, like 10
+// for a 10% rise or -15 for a 15% cut.
+type BulkPriceRequest struct {
+	ItemIDs []uuid.UUID `json:"itemIds" binding:"required,min=1,max=500"`
+	Percent float64     `json:"percent" binding:"required,gt=-100,max=1000"`
+}
+
+// BulkMoveRequest moves menu items to the end of another category.
+type BulkMoveRequest struct {
+	ItemIDs    []uuid.UUID `json:"itemIds" binding:"required,min=1,max=500"`
+	CategoryID uuid.UUID   `json:"categoryId" binding:"required"`
+}
+
+// BulkAvailabilityRequest switches menu items on or off.
+type BulkAvailabilityRequest struct {
+	ItemIDs     []uuid.UUID `json:"itemIds" binding:"required,min=1,max=500"`
+	IsAvailable *bool       `json:"isAvailable" binding:"required"`
+}
+
+// ScheduleRequest replaces the schedules of a category or item; an empty
+// list serves it at all times.
+type ScheduleRequest struct {
+	Schedules []models.MenuSchedule `json:"schedules"`
+}
+
+// PauseRequest takes a category or item off the menu for a while.
+type PauseRequest struct {
+	Minutes int `json:"minutes" binding:"required,min=1,max=1440"`
+}
+
+// ComboRequest replaces the slots of a combo; an empty list makes it a plain
+// menu item again.
+type ComboRequest struct {
+	Slots []ComboSlotRequest `json:"slots" binding:"omitempty,dive"`
+}
+
+type ComboSlotRequest struct {
+	Name     string               `json:"name" binding:"required,max=100"`
+	Optional bool                 `json:"optional"`
+	Options  []ComboOptionRequest `json:"options" binding:"required,min=1,dive"`
+}
+
+// ComboOptionRequest is a menu item that can fill a slot; PriceDelta is what
+// choosing it adds to the combo's price.
+type ComboOptionRequest struct {
+	MenuItemID uuid.UUID `json:"menuItemId" binding:"required"`
+	PriceDelta float64   `json:"priceDelta" binding:"min=0"`
 }
 
 type MenuItemResponse struct {
 	ID              uuid.UUID `json:"id"`
 	RestaurantID    uuid.UUID `json:"restaurantId"`
 	CategoryID      uuid.UUID `json:"categoryId"`
+	SKU             *string   `json:"sku,omitempty"`
 	Name            string    `json:"name"`
 	Description     string    `json:"description"`
 	Price           float64   `json:"price"`
 	Image           string    `json:"image"`
+	Position        int       `json:"position"`
 	IsAvailable     bool      `json:"isAvailable"`
+	IsPaused        bool      `json:"isPaused"`
+	PausedUntil     *time.Time `json:"pausedUntil,omitempty"`
+	Schedules       []models.MenuSchedule `json:"schedules,omitempty"`
+	OffSchedule     bool      `json:"offSchedule"`
+	// StockCount is what's left of a tracked item
+	StockCount      *int      `json:"stockCount,omitempty"`
+	SoldOutAt       *time.Time `json:"soldOutAt,omitempty"`
+	IsCombo         bool      `json:"isCombo"`
+	ComboSlots      []ComboSlotResponse `json:"comboSlots,omitempty"`
 	PreparationTime int       `json:"preparationTime"`
-	Allergens       string    `json:"allergens"`
+	Allergens       []models.Allergen   `json:"allergens"`
+	DietaryTags     []models.DietaryTag `json:"dietaryTags"`
+	SpiceLevel      int       `json:"spiceLevel"`
 	Calories        *int      `json:"calories,omitempty"`
 	Protein         *float64  `json:"protein,omitempty"`
 	Carbs           *float64  `json:"carbs,omitempty"`
@@ -59,6 +148,20 @@ type MenuItemResponse struct {
 	Sodium          *float64  `json:"sodium,omitempty"`
 }
 
+type ComboSlotResponse struct {
+	ID       uuid.UUID             `json:"id"`
+	Name     string                `json:"name"`
+	Optional bool                  `json:"optional"`
+	Options  []ComboOptionResponse `json:"options"`
+}
+
+type ComboOptionResponse struct {
+	MenuItemID  uuid.UUID `json:"menuItemId"`
+	Name        string    `json:"name"`
+	PriceDelta  float64   `json:"priceDelta"`
+	IsAvailable bool      `json:"isAvailable"`
+}
+
 type CategoryResponse struct {
 	ID           uuid.UUID          `json:"id"`
 	RestaurantID uuid.UUID          `json:"restaurantId"`
@@ -66,6 +169,10 @@ type CategoryResponse struct {
 	Description  string             `json:"description"`
 	Order        int                `json:"order"`
 	IsActive     bool               `json:"isActive"`
+	IsPaused     bool               `json:"isPaused"`
+	PausedUntil  *time.Time         `json:"pausedUntil,omitempty"`
+	Schedules    []models.MenuSchedule `json:"schedules,omitempty"`
+	OffSchedule  bool               `json:"offSchedule"`
 	MenuItems    []MenuItemResponse `json:"menuItems"`
 }
 
@@ -128,6 +235,15 @@ func (h *MenuHandler) CreateCategory(c *gin.Context) {
 		return
 	}
 
+	schedules, err := services.ParseMenuSchedules(req.Schedules)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
 	category := models.MenuCategory{
 		RestaurantID: restaurant.ID,
 		Name:         req.Name,
@@ -136,7 +252,13 @@ func (h *MenuHandler) CreateCategory(c *gin.Context) {
 		IsActive:     true,
 	}
 
-	if err := h.db.DB.Create(&category).Error; err != nil {
+	err = h.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&category).Error; err != nil {
+			return err
+		}
+		return services.SetCategorySchedules(tx, category.ID, schedules)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Message: "Failed to create category",
@@ -145,6 +267,8 @@ func (h *MenuHandler) CreateCategory(c *gin.Context) {
 		return
 	}
 
+	category.Schedules = schedules
+
 	c.JSON(http.StatusCreated, models.SuccessResponse{
 		Success: true,
 		Message: "Category created successfully",
@@ -152,31 +276,37 @@ func (h *MenuHandler) CreateCategory(c *gin.Context) {
 	})
 }
 
-// CreateMenuItem godoc
-// @Summary Create menu item
-// @Description Create a new menu item for restaurant
+// UpdateCategory godoc
+// @Summary Update menu category
+// @Description Change a category's name, description, whether it's shown, and its schedules
 // @Tags menu
 // @Accept json
 // @Produce json
 // @Security Bearer
-// @Param item body CreateMenuItemRequest true "Menu item data"
-// @Success 201 {object} models.SuccessResponse
+// @Param id path string true "Category ID"
+// @Param category body UpdateCategoryRequest true "Category data"
+// @Success 200 {object} models.SuccessResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /menu/items [post]
-func (h *MenuHandler) CreateMenuItem(c *gin.Context) {
-	userID, exists := middleware.GetCurrentUserID(c)
-	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+// @Router /menu/categories/{id} [put]
+func (h *MenuHandler) UpdateCategory(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	categoryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
-			Message: "User not authenticated",
+			Message: "Invalid category ID",
 		})
 		return
 	}
 
-	var req CreateMenuItemRequest
+	var req UpdateCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
@@ -185,37 +315,17 @@ func (h *MenuHandler) CreateMenuItem(c *gin.Context) {
 		})
 		return
 	}
-
-	categoryID, err := uuid.Parse(req.CategoryID)
+	schedules, err := services.ParseMenuSchedules(req.Schedules)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
-			Message: "Invalid category ID",
+			Message: err.Error(),
 		})
 		return
 	}
 
-	// Get user's restaurant
-	var restaurant models.Restaurant
-	if err := h.db.DB.Where("owner_id = ?", userID).First(&restaurant).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
-				Success: false,
-				Message: "Restaurant not found",
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Success: false,
-				Message: "Failed to fetch restaurant",
-				Error:   err.Error(),
-			})
-		}
-		return
-	}
-
-	// Verify category belongs to the restaurant
 	var category models.MenuCategory
-	if err := h.db.DB.Where("id = ? AND restaurant_id = ?", categoryID, restaurant.ID).First(&category).Error; err != nil {
+	if err := h.db.DB.Preload("Schedules").Where("id = ? AND restaurant_id = ?", categoryID, restaurant.ID).First(&category).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{
 				Success: false,
@@ -231,251 +341,1374 @@ func (h *MenuHandler) CreateMenuItem(c *gin.Context) {
 		return
 	}
 
-	menuItem := models.MenuItem{
-		RestaurantID:    restaurant.ID,
-		CategoryID:      categoryID,
-		Name:            req.Name,
-		Description:     req.Description,
-		Price:           req.Price,
-		Image:           req.Image,
-		IsAvailable:     true,
-		PreparationTime: req.PreparationTime,
-		Allergens:       req.Allergens,
-		Calories:        req.Calories,
-		Protein:         req.Protein,
-		Carbs:           req.Carbs,
-		Fat:             req.Fat,
-		Fiber:           req.Fiber,
-		Sodium:          req.Sodium,
+	updates := map[string]interface{}{
+		"name":        req.Name,
+		"description": req.Description,
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
 	}
 
-	if err := h.db.DB.Create(&menuItem).Error; err != nil {
+	err = h.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&category).Updates(updates).Error; err != nil {
+			return err
+		}
+		if req.Schedules == nil {
+			return nil
+		}
+		return services.SetCategorySchedules(tx, category.ID, schedules)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
-			Message: "Failed to create menu item",
+			Message: "Failed to update category",
 			Error:   err.Error(),
 		})
 		return
 	}
+	if req.Schedules != nil {
+		category.Schedules = schedules
+	}
 
-	c.JSON(http.StatusCreated, models.SuccessResponse{
+	c.JSON(http.StatusOK, models.SuccessResponse{
 		Success: true,
-		Message: "Menu item created successfully",
-		Data:    h.toMenuItemResponse(&menuItem),
+		Message: "Category updated successfully",
+		Data:    h.toCategoryResponse(&category),
 	})
 }
 
-// GetRestaurantMenu godoc
-// @Summary Get restaurant menu
-// @Description Get complete menu with categories and items for a restaurant
+// DeleteCategory godoc
+// @Summary Delete menu category
+// @Description Delete a category. If it has items, either moveTo another category to re-home them at its end, or set cascade to take them off the menu with it. Removed items are kept unavailable in the inactive "Removed items" category, since past orders refer to them
 // @Tags menu
-// @Accept json
 // @Produce json
-// @Param id path string true "Restaurant ID"
+// @Security Bearer
+// @Param id path string true "Category ID"
+// @Param moveTo query string false "Category to move the items to"
+// @Param cascade query bool false "Take the items off the menu too"
 // @Success 200 {object} models.SuccessResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
-// @Router /public/restaurants/{id}/menu [get]
-func (h *MenuHandler) GetRestaurantMenu(c *gin.Context) {
-	idStr := c.Param("id")
-	restaurantID, err := uuid.Parse(idStr)
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/categories/{id} [delete]
+func (h *MenuHandler) DeleteCategory(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	categoryID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
-			Message: "Invalid restaurant ID",
+			Message: "Invalid category ID",
 		})
 		return
 	}
 
-	var categories []models.MenuCategory
-	if err := h.db.DB.Where("restaurant_id = ? AND is_active = ?", restaurantID, true).
-		Preload("MenuItems", "is_available = ?", true).
-		Order("\"order\" ASC").
-		Find(&categories).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Success: false,
-			Message: "Failed to fetch menu",
-			Error:   err.Error(),
-		})
+	var moveTo *uuid.UUID
+	if raw := c.Query("moveTo"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Invalid category ID to move items to",
+			})
+			return
+		}
+		moveTo = &id
+	}
+	cascade := c.Query("cascade") == "true"
+
+	var items int
+	err = h.db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		items, err = services.DeleteCategory(tx, restaurant.ID, categoryID, moveTo, cascade)
+		return err
+	})
+	if err != nil {
+		if editErr, ok := err.(*services.MenuEditError); ok {
+			status := http.StatusBadRequest
+			if moveTo == nil && !cascade {
+				status = http.StatusConflict
+			}
+			c.JSON(status, models.ErrorResponse{
+				Success: false,
+				Message: editErr.Message,
+			})
+		} else if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Category not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to delete category",
+				Error:   err.Error(),
+			})
+		}
 		return
 	}
 
-	var responses []CategoryResponse
-	for _, category := range categories {
-		responses = append(responses, h.toCategoryResponse(&category))
+	result := map[string]interface{}{"itemsRemoved": 0, "itemsMoved": 0}
+	if moveTo != nil {
+		result["itemsMoved"] = items
+	} else {
+		result["itemsRemoved"] = items
 	}
 
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Success: true,
-		Message: "Menu retrieved successfully",
-		Data:    responses,
+		Message: "Category deleted successfully",
+		Data:    result,
 	})
 }
 
-// UpdateMenuItem godoc
-// @Summary Update menu item
-// @Description Update menu item details
+// ReorderCategories godoc
+// @Summary Reorder menu categories
+// @Description Set the order of the restaurant's categories, as after dragging and dropping them. ids must list every category once; all positions change together or not at all
 // @Tags menu
 // @Accept json
 // @Produce json
 // @Security Bearer
-// @Param id path string true "Menu Item ID"
-// @Param item body CreateMenuItemRequest true "Menu item update data"
+// @Param order body ReorderRequest true "Category IDs in their new order"
 // @Success 200 {object} models.SuccessResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /menu/items/{id} [put]
-func (h *MenuHandler) UpdateMenuItem(c *gin.Context) {
-	userID, exists := middleware.GetCurrentUserID(c)
-	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Success: false,
-			Message: "User not authenticated",
-		})
+// @Router /menu/categories/order [put]
+func (h *MenuHandler) ReorderCategories(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
 		return
 	}
 
-	idStr := c.Param("id")
-	menuItemID, err := uuid.Parse(idStr)
-	if err != nil {
+	var req ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
-			Message: "Invalid menu item ID",
+			Message: "Invalid request data",
+			Error:   err.Error(),
 		})
 		return
 	}
 
-	// Get user's restaurant
-	var restaurant models.Restaurant
-	if err := h.db.DB.Where("owner_id = ?", userID).First(&restaurant).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
-				Success: false,
-				Message: "Restaurant not found",
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Success: false,
-				Message: "Failed to fetch restaurant",
-				Error:   err.Error(),
-			})
-		}
-		return
-	}
-
-	var menuItem models.MenuItem
-	if err := h.db.DB.Where("id = ? AND restaurant_id = ?", menuItemID, restaurant.ID).First(&menuItem).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		return services.ReorderCategories(tx, restaurant.ID, req.IDs)
+	})
+	if err != nil {
+		if editErr, ok := err.(*services.MenuEditError); ok {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
 				Success: false,
-				Message: "Menu item not found",
+				Message: editErr.Message,
 			})
 		} else {
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 				Success: false,
-				Message: "Failed to fetch menu item",
+				Message: "Failed to reorder categories",
 				Error:   err.Error(),
 			})
 		}
 		return
 	}
 
-	var req CreateMenuItemRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Success: false,
-			Message: "Invalid request data",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	// Update fields
-	menuItem.Name = req.Name
-	menuItem.Description = req.Description
-	menuItem.Price = req.Price
-	menuItem.Image = req.Image
-	menuItem.PreparationTime = req.PreparationTime
-	menuItem.Allergens = req.Allergens
-	menuItem.Calories = req.Calories
-	menuItem.Protein = req.Protein
-	menuItem.Carbs = req.Carbs
-	menuItem.Fat = req.Fat
-	menuItem.Fiber = req.Fiber
-	menuItem.Sodium = req.Sodium
-
-	if err := h.db.DB.Save(&menuItem).Error; err != nil {
+	var categories []models.MenuCategory
+	if err := h.db.DB.Preload("Schedules").Where("restaurant_id = ?", restaurant.ID).Order("\"order\" ASC").Find(&categories).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
-			Message: "Failed to update menu item",
+			Message: "Failed to fetch categories",
 			Error:   err.Error(),
 		})
 		return
 	}
+	responses := make([]CategoryResponse, len(categories))
+	for i := range categories {
+		responses[i] = h.toCategoryResponse(&categories[i])
+	}
 
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Success: true,
-		Message: "Menu item updated successfully",
-		Data:    h.toMenuItemResponse(&menuItem),
+		Message: "Categories reordered",
+		Data:    responses,
 	})
 }
 
-// ToggleItemAvailability godoc
-// @Summary Toggle menu item availability
-// @Description Toggle availability of a menu item
+// ReorderMenuItems godoc
+// @Summary Reorder menu items
+// @Description Set the order of a category's items, as after dragging and dropping them. ids must list every item of the category once; all positions change together or not at all
 // @Tags menu
 // @Accept json
 // @Produce json
 // @Security Bearer
-// @Param id path string true "Menu Item ID"
+// @Param id path string true "Category ID"
+// @Param order body ReorderRequest true "Menu item IDs in their new order"
 // @Success 200 {object} models.SuccessResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /menu/items/{id}/toggle [patch]
-func (h *MenuHandler) ToggleItemAvailability(c *gin.Context) {
-	userID, exists := middleware.GetCurrentUserID(c)
-	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+// @Router /menu/categories/{id}/items/order [put]
+func (h *MenuHandler) ReorderMenuItems(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	categoryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
-			Message: "User not authenticated",
+			Message: "Invalid category ID",
 		})
 		return
 	}
 
-	idStr := c.Param("id")
-	menuItemID, err := uuid.Parse(idStr)
-	if err != nil {
+	var req ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
-			Message: "Invalid menu item ID",
+			Message: "Invalid request data",
+			Error:   err.Error(),
 		})
 		return
 	}
 
-	// Get user's restaurant
-	var restaurant models.Restaurant
-	if err := h.db.DB.Where("owner_id = ?", userID).First(&restaurant).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
+	err = h.db.DB.Transaction(func(tx *gorm.DB) error {
+		return services.ReorderMenuItems(tx, restaurant.ID, categoryID, req.IDs)
+	})
+	if err != nil {
+		if editErr, ok := err.(*services.MenuEditError); ok {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: editErr.Message,
+			})
+		} else if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Category not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to reorder menu items",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	var category models.MenuCategory
+	if err := h.db.DB.Preload("Schedules").
+		Preload("MenuItems", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position, name")
+		}).
+		Preload("MenuItems.Schedules").
+		Preload("MenuItems.Allergens", func(db *gorm.DB) *gorm.DB {
+			return db.Order("allergen")
+		}).
+		Preload("MenuItems.DietaryTags", func(db *gorm.DB) *gorm.DB {
+			return db.Order("tag")
+		}).
+		Where("id = ?", categoryID).
+		First(&category).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch category",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Menu items reordered",
+		Data:    h.toCategoryResponse(&category),
+	})
+}
+
+// BulkChangePrices godoc
+// @Summary Change menu item prices
+// @Description Raise or lower the prices of several menu items by a percentage, rounded to the cent. All of them change or none do
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param change body BulkPriceRequest true "Items and percentage"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/items/bulk/price [post]
+func (h *MenuHandler) BulkChangePrices(c *gin.Context) {
+	var req BulkPriceRequest
+	h.bulkEdit(c, &req, "Prices updated", func(tx *gorm.DB, restaurantID uuid.UUID) ([]models.MenuItem, error) {
+		return services.ChangeMenuPrices(tx, restaurantID, req.ItemIDs, req.Percent)
+	})
+}
+
+// BulkMoveItems godoc
+// @Summary Move menu items
+// @Description Move several menu items to the end of another category, keeping their order
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param move body BulkMoveRequest true "Items and category"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/items/bulk/move [post]
+func (h *MenuHandler) BulkMoveItems(c *gin.Context) {
+	var req BulkMoveRequest
+	h.bulkEdit(c, &req, "Menu items moved", func(tx *gorm.DB, restaurantID uuid.UUID) ([]models.MenuItem, error) {
+		return services.MoveMenuItems(tx, restaurantID, req.ItemIDs, req.CategoryID)
+	})
+}
+
+// BulkSetAvailability godoc
+// @Summary Set menu item availability
+// @Description Mark several menu items available or unavailable at once, as toggling each would
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param availability body BulkAvailabilityRequest true "Items and availability"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/items/bulk/availability [post]
+func (h *MenuHandler) BulkSetAvailability(c *gin.Context) {
+	var req BulkAvailabilityRequest
+	h.bulkEdit(c, &req, "Menu item availability updated", func(tx *gorm.DB, restaurantID uuid.UUID) ([]models.MenuItem, error) {
+		return services.SetMenuItemsAvailability(tx, restaurantID, req.ItemIDs, *req.IsAvailable)
+	})
+}
+
+// bulkEdit binds req and applies a bulk change to the owner's menu items in
+// one transaction, responding with the changed items.
+func (h *MenuHandler) bulkEdit(c *gin.Context, req interface{}, message string, edit func(*gorm.DB, uuid.UUID) ([]models.MenuItem, error)) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var items []models.MenuItem
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		items, err = edit(tx, restaurant.ID)
+		return err
+	})
+	if err != nil {
+		if editErr, ok := err.(*services.MenuEditError); ok {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: editErr.Message,
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to update menu items",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	responses := make([]MenuItemResponse, len(items))
+	for i := range items {
+		if err := h.loadItemDetails(&items[i]); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to load menu item",
+				Error:   err.Error(),
+			})
+			return
+		}
+		responses[i] = h.toMenuItemResponse(&items[i])
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: message,
+		Data:    responses,
+	})
+}
+
+// CreateMenuItem godoc
+// @Summary Create menu item
+// @Description Create a new menu item for restaurant
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param item body CreateMenuItemRequest true "Menu item data"
+// @Success 201 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/items [post]
+func (h *MenuHandler) CreateMenuItem(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	var req CreateMenuItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	categoryID, err := uuid.Parse(req.CategoryID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid category ID",
+		})
+		return
+	}
+
+	labels, err := services.ParseMenuItemLabels(req.Allergens, req.DietaryTags, req.SpiceLevel)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+	schedules, err := services.ParseMenuSchedules(req.Schedules)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Get user's restaurant
+	var restaurant models.Restaurant
+	if err := h.db.DB.Where("owner_id = ?", userID).First(&restaurant).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Restaurant not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch restaurant",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	// Verify category belongs to the restaurant
+	var category models.MenuCategory
+	if err := h.db.DB.Where("id = ? AND restaurant_id = ?", categoryID, restaurant.ID).First(&category).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Category not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch category",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	sku, ok := h.checkSKU(c, restaurant.ID, req.SKU, nil)
+	if !ok {
+		return
+	}
+
+	menuItem := models.MenuItem{
+		RestaurantID:    restaurant.ID,
+		CategoryID:      categoryID,
+		SKU:             sku,
+		Name:            req.Name,
+		Description:     req.Description,
+		Price:           req.Price,
+		Image:           req.Image,
+		IsAvailable:     true,
+		PreparationTime: req.PreparationTime,
+		SpiceLevel:      labels.SpiceLevel,
+		Calories:        req.Calories,
+		Protein:         req.Protein,
+		Carbs:           req.Carbs,
+		Fat:             req.Fat,
+		Fiber:           req.Fiber,
+		Sodium:          req.Sodium,
+	}
+
+	err = h.db.DB.Transaction(func(tx *gorm.DB) error {
+		// New items go at the end of their category
+		position, err := services.NextMenuItemPosition(tx, categoryID)
+		if err != nil {
+			return err
+		}
+		menuItem.Position = position
+		if err := tx.Create(&menuItem).Error; err != nil {
+			return err
+		}
+		if err := services.SetMenuItemLabels(tx, menuItem.ID, labels); err != nil {
+			return err
+		}
+		return services.SetMenuItemSchedules(tx, menuItem.ID, schedules)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to create menu item",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if err := h.loadItemDetails(&menuItem); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to load menu item",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Success: true,
+		Message: "Menu item created successfully",
+		Data:    h.toMenuItemResponse(&menuItem),
+	})
+}
+
+// GetRestaurantMenu godoc
+// @Summary Get restaurant menu
+// @Description Get complete menu with categories and items for a restaurant. Paused categories and items are included with isPaused and pausedUntil, and ones not served at the time asked for (now by default, in the restaurant's time zone) with offSchedule. Filtering by allergens, dietary tags or spice level, or hiding what's off schedule, hides the items that don't match, and categories left empty
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param at query string false "Time to check schedules at, RFC 3339; now by default"
+// @Param hideOffSchedule query bool false "Hide categories and items not served at that time"
+// @Param excludeAllergens query []string false "Hide items containing any of these allergens" collectionFormat(multi)
+// @Param dietary query []string false "Only items with all of these dietary tags (vegan, vegetarian, halal, gluten_free)" collectionFormat(multi)
+// @Param maxSpiceLevel query int false "Only items at most this spicy (0-3)"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /public/restaurants/{id}/menu [get]
+func (h *MenuHandler) GetRestaurantMenu(c *gin.Context) {
+	idStr := c.Param("id")
+	restaurantID, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid restaurant ID",
+		})
+		return
+	}
+
+	var filters services.MenuItemFilters
+	for _, name := range queryList(c, "excludeAllergens") {
+		if !models.IsValidAllergen(models.Allergen(name)) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Unknown allergen " + name,
+			})
+			return
+		}
+		filters.ExcludeAllergens = append(filters.ExcludeAllergens, models.Allergen(name))
+	}
+	for _, name := range queryList(c, "dietary") {
+		if !models.IsValidDietaryTag(models.DietaryTag(name)) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Unknown dietary tag " + name,
+			})
+			return
+		}
+		filters.DietaryTags = append(filters.DietaryTags, models.DietaryTag(name))
+	}
+	if level := c.Query("maxSpiceLevel"); level != "" {
+		maxSpiceLevel, err := strconv.Atoi(level)
+		if err != nil || maxSpiceLevel < 0 || maxSpiceLevel > models.MaxSpiceLevel {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: fmt.Sprintf("maxSpiceLevel must be between 0 and %d", models.MaxSpiceLevel),
+			})
+			return
+		}
+		filters.MaxSpiceLevel = &maxSpiceLevel
+	}
+	filtered := len(filters.ExcludeAllergens) > 0 || len(filters.DietaryTags) > 0 || filters.MaxSpiceLevel != nil
+
+	at := time.Now()
+	if value := c.Query("at"); value != "" {
+		if at, err = time.Parse(time.RFC3339, value); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "at must be an RFC 3339 time",
+			})
+			return
+		}
+	}
+	hideOffSchedule, _ := strconv.ParseBool(c.Query("hideOffSchedule"))
+
+	// Schedules are read in the restaurant's time zone
+	var restaurant models.Restaurant
+	if err := h.db.DB.Select("id", "time_zone").Where("id = ?", restaurantID).First(&restaurant).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Restaurant not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch restaurant",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+	local := at.In(restaurant.Location())
+
+	var categories []models.MenuCategory
+	if err := h.db.DB.Where("restaurant_id = ? AND is_active = ?", restaurantID, true).
+		Preload("Schedules").
+		Preload("MenuItems", func(db *gorm.DB) *gorm.DB {
+			return filters.Apply(db.Where("menu_items.is_available = ?", true)).Order("menu_items.position, menu_items.name")
+		}).
+		Preload("MenuItems.Schedules").
+		Preload("MenuItems.Allergens", func(db *gorm.DB) *gorm.DB {
+			return db.Order("allergen")
+		}).
+		Preload("MenuItems.DietaryTags", func(db *gorm.DB) *gorm.DB {
+			return db.Order("tag")
+		}).
+		Preload("MenuItems.ComboSlots", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position, id")
+		}).
+		Preload("MenuItems.ComboSlots.Options.MenuItem").
+		Order("\"order\" ASC").
+		Find(&categories).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch menu",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var responses []CategoryResponse
+	for _, category := range categories {
+		response := h.toCategoryResponse(&category)
+		response.OffSchedule = !models.IsOnSchedule(category.Schedules, local)
+		if hideOffSchedule && response.OffSchedule {
+			continue
+		}
+
+		// Items of a category that's off schedule are off schedule too
+		items := response.MenuItems[:0]
+		for i, itemResponse := range response.MenuItems {
+			itemResponse.OffSchedule = response.OffSchedule || !models.IsOnSchedule(category.MenuItems[i].Schedules, local)
+			if hideOffSchedule && itemResponse.OffSchedule {
+				continue
+			}
+			items = append(items, itemResponse)
+		}
+		response.MenuItems = items
+
+		if (filtered || hideOffSchedule) && len(response.MenuItems) == 0 {
+			continue
+		}
+		responses = append(responses, response)
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Menu retrieved successfully",
+		Data:    responses,
+	})
+}
+
+// UpdateMenuItem godoc
+// @Summary Update menu item
+// @Description Update menu item details
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Menu Item ID"
+// @Param item body CreateMenuItemRequest true "Menu item update data"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/items/{id} [put]
+func (h *MenuHandler) UpdateMenuItem(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	idStr := c.Param("id")
+	menuItemID, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid menu item ID",
+		})
+		return
+	}
+
+	// Get user's restaurant
+	var restaurant models.Restaurant
+	if err := h.db.DB.Where("owner_id = ?", userID).First(&restaurant).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Restaurant not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch restaurant",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	var menuItem models.MenuItem
+	if err := h.db.DB.Where("id = ? AND restaurant_id = ?", menuItemID, restaurant.ID).First(&menuItem).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Menu item not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch menu item",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	var req CreateMenuItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	labels, err := services.ParseMenuItemLabels(req.Allergens, req.DietaryTags, req.SpiceLevel)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+	schedules, err := services.ParseMenuSchedules(req.Schedules)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	sku, ok := h.checkSKU(c, restaurant.ID, req.SKU, &menuItem.ID)
+	if !ok {
+		return
+	}
+
+	// Update fields
+	menuItem.SKU = sku
+	menuItem.Name = req.Name
+	menuItem.Description = req.Description
+	menuItem.Price = req.Price
+	menuItem.Image = req.Image
+	menuItem.PreparationTime = req.PreparationTime
+	menuItem.SpiceLevel = labels.SpiceLevel
+	menuItem.Calories = req.Calories
+	menuItem.Protein = req.Protein
+	menuItem.Carbs = req.Carbs
+	menuItem.Fat = req.Fat
+	menuItem.Fiber = req.Fiber
+	menuItem.Sodium = req.Sodium
+
+	err = h.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&menuItem).Error; err != nil {
+			return err
+		}
+		if err := services.SetMenuItemLabels(tx, menuItem.ID, labels); err != nil {
+			return err
+		}
+		if req.Schedules == nil {
+			return nil
+		}
+		return services.SetMenuItemSchedules(tx, menuItem.ID, schedules)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to update menu item",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if err := h.loadItemDetails(&menuItem); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to load menu item",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Menu item updated successfully",
+		Data:    h.toMenuItemResponse(&menuItem),
+	})
+}
+
+// ToggleItemAvailability godoc
+// @Summary Toggle menu item availability
+// @Description Toggle availability of a menu item
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Menu Item ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/items/{id}/toggle [patch]
+func (h *MenuHandler) ToggleItemAvailability(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	idStr := c.Param("id")
+	menuItemID, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid menu item ID",
+		})
+		return
+	}
+
+	// Get user's restaurant
+	var restaurant models.Restaurant
+	if err := h.db.DB.Where("owner_id = ?", userID).First(&restaurant).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Restaurant not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch restaurant",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	var menuItem models.MenuItem
+	if err := h.db.DB.Where("id = ? AND restaurant_id = ?", menuItemID, restaurant.ID).First(&menuItem).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Menu item not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch menu item",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	menuItem.IsAvailable = !menuItem.IsAvailable
+	menuItem.SoldOutAt = nil
+
+	if err := h.db.DB.Save(&menuItem).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to update menu item availability",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if err := h.loadItemDetails(&menuItem); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to load menu item",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	status := "unavailable"
+	if menuItem.IsAvailable {
+		status = "available"
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Menu item is now " + status,
+		Data:    h.toMenuItemResponse(&menuItem),
+	})
+}
+
+// DeleteMenuItem godoc
+// @Summary Delete menu item
+// @Description Delete a menu item
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Menu Item ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/items/{id} [delete]
+func (h *MenuHandler) DeleteMenuItem(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	idStr := c.Param("id")
+	menuItemID, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid menu item ID",
+		})
+		return
+	}
+
+	// Get user's restaurant
+	var restaurant models.Restaurant
+	if err := h.db.DB.Where("owner_id = ?", userID).First(&restaurant).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Restaurant not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch restaurant",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	// Check if menu item exists and belongs to this restaurant
+	var menuItem models.MenuItem
+	if err := h.db.DB.Where("id = ? AND restaurant_id = ?", menuItemID, restaurant.ID).First(&menuItem).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Menu item not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch menu item",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	// Delete the menu item
+	if err := h.db.DB.Delete(&menuItem).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to delete menu item",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Menu item deleted successfully",
+	})
+}
+
+// GetMenuItem godoc
+// @Summary Get menu item details
+// @Description Get detailed information about a specific menu item
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Param id path string true "Menu Item ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu-items/{id} [get]
+func (h *MenuHandler) GetMenuItem(c *gin.Context) {
+	idStr := c.Param("id")
+	menuItemID, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid menu item ID",
+		})
+		return
+	}
+
+	var menuItem models.MenuItem
+	if err := h.db.DB.Preload("Category").Preload("Allergens").Preload("DietaryTags").Preload("Schedules").Preload("ComboSlots", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position, id")
+	}).Preload("ComboSlots.Options.MenuItem").Where("id = ?", menuItemID).First(&menuItem).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Menu item not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch menu item",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	// Get restaurant info for additional context
+	var restaurant models.Restaurant
+	if err := h.db.DB.Select("id, name, image").Where("id = ?", menuItem.RestaurantID).First(&restaurant).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch restaurant info",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response := h.toMenuItemResponse(&menuItem)
+
+	// Add additional context
+	itemWithContext := map[string]interface{}{
+		"menuItem": response,
+		"restaurant": map[string]interface{}{
+			"id":    restaurant.ID,
+			"name":  restaurant.Name,
+			"image": restaurant.Image,
+		},
+		"category": map[string]interface{}{
+			"id":   menuItem.Category.ID,
+			"name": menuItem.Category.Name,
+		},
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Menu item retrieved successfully",
+		Data:    itemWithContext,
+	})
+}
+
+// PauseCategory godoc
+// @Summary Pause menu category
+// @Description Take a whole category off the menu for a number of minutes; it comes back on its own
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Category ID"
+// @Param pause body PauseRequest true "Pause length"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/categories/{id}/pause [post]
+func (h *MenuHandler) PauseCategory(c *gin.Context) {
+	var req PauseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	pausedUntil := time.Now().Add(time.Duration(req.Minutes) * time.Minute)
+	h.setCategoryPause(c, &pausedUntil, "Category paused")
+}
+
+// ResumeCategory godoc
+// @Summary Resume menu category
+// @Description End a category pause early
+// @Tags menu
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Category ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/categories/{id}/pause [delete]
+func (h *MenuHandler) ResumeCategory(c *gin.Context) {
+	h.setCategoryPause(c, nil, "Category resumed")
+}
+
+// PauseMenuItem godoc
+// @Summary Pause menu item
+// @Description Take a menu item off the menu for a number of minutes; it comes back on its own
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Menu Item ID"
+// @Param pause body PauseRequest true "Pause length"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/items/{id}/pause [post]
+func (h *MenuHandler) PauseMenuItem(c *gin.Context) {
+	var req PauseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	pausedUntil := time.Now().Add(time.Duration(req.Minutes) * time.Minute)
+	h.setMenuItemPause(c, &pausedUntil, "Menu item paused")
+}
+
+// ResumeMenuItem godoc
+// @Summary Resume menu item
+// @Description End a menu item pause early
+// @Tags menu
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Menu Item ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/items/{id}/pause [delete]
+func (h *MenuHandler) ResumeMenuItem(c *gin.Context) {
+	h.setMenuItemPause(c, nil, "Menu item resumed")
+}
+
+// SetCategorySchedules godoc
+// @Summary Set category schedules
+// @Description Replace the times a category is served, like breakfast on weekdays from 07:00 to 11:00, read in the restaurant's time zone. Days are monday to sunday, times HH:MM and dates YYYY-MM-DD, all optional; an end time before the start runs past midnight. An empty list serves it at all times
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Category ID"
+// @Param schedules body ScheduleRequest true "Schedules"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/categories/{id}/schedules [put]
+func (h *MenuHandler) SetCategorySchedules(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	categoryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid category ID",
+		})
+		return
+	}
+
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+	schedules, err := services.ParseMenuSchedules(req.Schedules)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var category models.MenuCategory
+	if err := h.db.DB.Where("id = ? AND restaurant_id = ?", categoryID, restaurant.ID).First(&category).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
 				Success: false,
-				Message: "Restaurant not found",
+				Message: "Category not found",
 			})
 		} else {
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 				Success: false,
-				Message: "Failed to fetch restaurant",
+				Message: "Failed to fetch category",
 				Error:   err.Error(),
 			})
 		}
 		return
 	}
 
+	err = h.db.DB.Transaction(func(tx *gorm.DB) error {
+		return services.SetCategorySchedules(tx, category.ID, schedules)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to update category",
+			Error:   err.Error(),
+		})
+		return
+	}
+	category.Schedules = schedules
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Category schedules updated",
+		Data:    h.toCategoryResponse(&category),
+	})
+}
+
+// SetMenuItemSchedules godoc
+// @Summary Set menu item schedules
+// @Description Replace the times a menu item is served, read in the restaurant's time zone, in the same format as category schedules. An item is served when both it and its category are on schedule. An empty list serves it whenever its category is
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Menu Item ID"
+// @Param schedules body ScheduleRequest true "Schedules"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/items/{id}/schedules [put]
+func (h *MenuHandler) SetMenuItemSchedules(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	menuItemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid menu item ID",
+		})
+		return
+	}
+
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+	schedules, err := services.ParseMenuSchedules(req.Schedules)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
 	var menuItem models.MenuItem
-	if err := h.db.DB.Where("id = ? AND restaurant_id = ?", menuItemID, restaurant.ID).First(&menuItem).Error; err != nil {
+	if err := h.db.DB.Preload("Allergens").Preload("DietaryTags").Where("id = ? AND restaurant_id = ?", menuItemID, restaurant.ID).First(&menuItem).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{
 				Success: false,
@@ -491,55 +1724,48 @@ func (h *MenuHandler) ToggleItemAvailability(c *gin.Context) {
 		return
 	}
 
-	menuItem.IsAvailable = !menuItem.IsAvailable
-
-	if err := h.db.DB.Save(&menuItem).Error; err != nil {
+	err = h.db.DB.Transaction(func(tx *gorm.DB) error {
+		return services.SetMenuItemSchedules(tx, menuItem.ID, schedules)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
-			Message: "Failed to update menu item availability",
+			Message: "Failed to update menu item",
 			Error:   err.Error(),
 		})
 		return
 	}
-
-	status := "unavailable"
-	if menuItem.IsAvailable {
-		status = "available"
-	}
+	menuItem.Schedules = schedules
 
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Success: true,
-		Message: "Menu item is now " + status,
+		Message: "Menu item schedules updated",
 		Data:    h.toMenuItemResponse(&menuItem),
 	})
 }
 
-// DeleteMenuItem godoc
-// @Summary Delete menu item
-// @Description Delete a menu item
+// SetCombo godoc
+// @Summary Set combo slots
+// @Description Make a menu item a combo by replacing its slots, like a main, a side and a drink. Each slot lists the menu items that can fill it and what choosing each adds to the combo's price, which is the item's own price. Customers fill required slots when ordering; an empty list makes it a plain item again
 // @Tags menu
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path string true "Menu Item ID"
+// @Param combo body ComboRequest true "Combo slots"
 // @Success 200 {object} models.SuccessResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /menu/items/{id} [delete]
-func (h *MenuHandler) DeleteMenuItem(c *gin.Context) {
-	userID, exists := middleware.GetCurrentUserID(c)
-	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Success: false,
-			Message: "User not authenticated",
-		})
+// @Router /menu/items/{id}/combo [put]
+func (h *MenuHandler) SetCombo(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
 		return
 	}
 
-	idStr := c.Param("id")
-	menuItemID, err := uuid.Parse(idStr)
+	menuItemID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
@@ -548,47 +1774,112 @@ func (h *MenuHandler) DeleteMenuItem(c *gin.Context) {
 		return
 	}
 
-	// Get user's restaurant
-	var restaurant models.Restaurant
-	if err := h.db.DB.Where("owner_id = ?", userID).First(&restaurant).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+	var req ComboRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	slots := make([]models.ComboSlot, len(req.Slots))
+	for i, slot := range req.Slots {
+		slots[i] = models.ComboSlot{Name: slot.Name, Optional: slot.Optional}
+		for _, option := range slot.Options {
+			slots[i].Options = append(slots[i].Options, models.ComboSlotOption{
+				MenuItemID: option.MenuItemID,
+				PriceDelta: option.PriceDelta,
+			})
+		}
+	}
+
+	err = h.db.DB.Transaction(func(tx *gorm.DB) error {
+		return services.SetComboSlots(tx, restaurant.ID, menuItemID, slots)
+	})
+	if err != nil {
+		if comboErr, ok := err.(*services.ComboError); ok {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: comboErr.Message,
+			})
+		} else if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{
 				Success: false,
-				Message: "Restaurant not found",
+				Message: "Menu item not found",
 			})
 		} else {
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 				Success: false,
-				Message: "Failed to fetch restaurant",
+				Message: "Failed to update combo",
 				Error:   err.Error(),
 			})
 		}
 		return
 	}
 
-	// Check if menu item exists and belongs to this restaurant
 	var menuItem models.MenuItem
-	if err := h.db.DB.Where("id = ? AND restaurant_id = ?", menuItemID, restaurant.ID).First(&menuItem).Error; err != nil {
+	if err := h.db.DB.Where("id = ?", menuItemID).First(&menuItem).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch menu item",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if err := h.loadItemDetails(&menuItem); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch menu item",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Combo updated",
+		Data:    h.toMenuItemResponse(&menuItem),
+	})
+}
+
+func (h *MenuHandler) setCategoryPause(c *gin.Context, pausedUntil *time.Time, message string) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	categoryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid category ID",
+		})
+		return
+	}
+
+	var category models.MenuCategory
+	if err := h.db.DB.Where("id = ? AND restaurant_id = ?", categoryID, restaurant.ID).First(&category).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{
 				Success: false,
-				Message: "Menu item not found",
+				Message: "Category not found",
 			})
 		} else {
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 				Success: false,
-				Message: "Failed to fetch menu item",
+				Message: "Failed to fetch category",
 				Error:   err.Error(),
 			})
 		}
 		return
 	}
 
-	// Delete the menu item
-	if err := h.db.DB.Delete(&menuItem).Error; err != nil {
+	if err := h.db.DB.Model(&category).Update("paused_until", pausedUntil).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
-			Message: "Failed to delete menu item",
+			Message: "Failed to update category",
 			Error:   err.Error(),
 		})
 		return
@@ -596,25 +1887,18 @@ func (h *MenuHandler) DeleteMenuItem(c *gin.Context) {
 
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Success: true,
-		Message: "Menu item deleted successfully",
+		Message: message,
+		Data:    h.toCategoryResponse(&category),
 	})
 }
 
-// GetMenuItem godoc
-// @Summary Get menu item details
-// @Description Get detailed information about a specific menu item
-// @Tags menu
-// @Accept json
-// @Produce json
-// @Param id path string true "Menu Item ID"
-// @Success 200 {object} models.SuccessResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 404 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
-// @Router /menu-items/{id} [get]
-func (h *MenuHandler) GetMenuItem(c *gin.Context) {
-	idStr := c.Param("id")
-	menuItemID, err := uuid.Parse(idStr)
+func (h *MenuHandler) setMenuItemPause(c *gin.Context, pausedUntil *time.Time, message string) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	menuItemID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
@@ -624,7 +1908,7 @@ func (h *MenuHandler) GetMenuItem(c *gin.Context) {
 	}
 
 	var menuItem models.MenuItem
-	if err := h.db.DB.Preload("Category").Where("id = ?", menuItemID).First(&menuItem).Error; err != nil {
+	if err := h.db.DB.Preload("Allergens").Preload("DietaryTags").Preload("Schedules").Where("id = ? AND restaurant_id = ?", menuItemID, restaurant.ID).First(&menuItem).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{
 				Success: false,
@@ -640,37 +1924,19 @@ func (h *MenuHandler) GetMenuItem(c *gin.Context) {
 		return
 	}
 
-	// Get restaurant info for additional context
-	var restaurant models.Restaurant
-	if err := h.db.DB.Select("id, name, image").Where("id = ?", menuItem.RestaurantID).First(&restaurant).Error; err != nil {
+	if err := h.db.DB.Model(&menuItem).Update("paused_until", pausedUntil).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
-			Message: "Failed to fetch restaurant info",
+			Message: "Failed to update menu item",
 			Error:   err.Error(),
 		})
 		return
 	}
 
-	response := h.toMenuItemResponse(&menuItem)
-
-	// Add additional context
-	itemWithContext := map[string]interface{}{
-		"menuItem": response,
-		"restaurant": map[string]interface{}{
-			"id":    restaurant.ID,
-			"name":  restaurant.Name,
-			"image": restaurant.Image,
-		},
-		"category": map[string]interface{}{
-			"id":   menuItem.Category.ID,
-			"name": menuItem.Category.Name,
-		},
-	}
-
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Success: true,
-		Message: "Menu item retrieved successfully",
-		Data:    itemWithContext,
+		Message: message,
+		Data:    h.toMenuItemResponse(&menuItem),
 	})
 }
 
@@ -682,28 +1948,47 @@ func (h *MenuHandler) toCategoryResponse(category *models.MenuCategory) Category
 		Description:  category.Description,
 		Order:        category.Order,
 		IsActive:     category.IsActive,
+		Schedules:    category.Schedules,
 		MenuItems:    []MenuItemResponse{},
 	}
+	if category.IsPaused(time.Now()) {
+		response.IsPaused = true
+		response.PausedUntil = category.PausedUntil
+	}
 
 	for _, item := range category.MenuItems {
-		response.MenuItems = append(response.MenuItems, h.toMenuItemResponse(&item))
+		itemResponse := h.toMenuItemResponse(&item)
+		// Items of a paused category are paused at least as long
+		if response.IsPaused && (!itemResponse.IsPaused || itemResponse.PausedUntil.Before(*response.PausedUntil)) {
+			itemResponse.IsPaused = true
+			itemResponse.PausedUntil = response.PausedUntil
+		}
+		response.MenuItems = append(response.MenuItems, itemResponse)
 	}
 
 	return response
 }
 
 func (h *MenuHandler) toMenuItemResponse(item *models.MenuItem) MenuItemResponse {
-	return MenuItemResponse{
+	response := MenuItemResponse{
 		ID:              item.ID,
 		RestaurantID:    item.RestaurantID,
 		CategoryID:      item.CategoryID,
+		SKU:             item.SKU,
 		Name:            item.Name,
 		Description:     item.Description,
 		Price:           item.Price,
 		Image:           item.Image,
+		Position:        item.Position,
 		IsAvailable:     item.IsAvailable,
+		Schedules:       item.Schedules,
+		StockCount:      item.StockCount,
+		SoldOutAt:       item.SoldOutAt,
+		IsCombo:         item.IsCombo,
 		PreparationTime: item.PreparationTime,
-		Allergens:       item.Allergens,
+		Allergens:       make([]models.Allergen, len(item.Allergens)),
+		DietaryTags:     make([]models.DietaryTag, len(item.DietaryTags)),
+		SpiceLevel:      item.SpiceLevel,
 		Calories:        item.Calories,
 		Protein:         item.Protein,
 		Carbs:           item.Carbs,
@@ -711,4 +1996,78 @@ func (h *MenuHandler) toMenuItemResponse(item *models.MenuItem) MenuItemResponse
 		Fiber:           item.Fiber,
 		Sodium:          item.Sodium,
 	}
+	for i, allergen := range item.Allergens {
+		response.Allergens[i] = allergen.Allergen
+	}
+	for i, tag := range item.DietaryTags {
+		response.DietaryTags[i] = tag.Tag
+	}
+	for _, slot := range item.ComboSlots {
+		slotResponse := ComboSlotResponse{
+			ID:       slot.ID,
+			Name:     slot.Name,
+			Optional: slot.Optional,
+			Options:  make([]ComboOptionResponse, len(slot.Options)),
+		}
+		for i, option := range slot.Options {
+			slotResponse.Options[i] = ComboOptionResponse{
+				MenuItemID:  option.MenuItemID,
+				Name:        option.MenuItem.Name,
+				PriceDelta:  option.PriceDelta,
+				IsAvailable: option.MenuItem.IsAvailable && !option.MenuItem.IsPaused(time.Now()),
+			}
+		}
+		response.ComboSlots = append(response.ComboSlots, slotResponse)
+	}
+	if item.IsPaused(time.Now()) {
+		response.IsPaused = true
+		response.PausedUntil = item.PausedUntil
+	}
+	return response
+}
+
+// checkSKU returns the SKU for a menu item, nil for none, making sure no
+// other item of the restaurant has it.
+func (h *MenuHandler) checkSKU(c *gin.Context, restaurantID uuid.UUID, sku string, itemID *uuid.UUID) (*string, bool) {
+	sku = strings.TrimSpace(sku)
+	if sku == "" {
+		return nil, true
+	}
+
+	query := h.db.DB.Model(&models.MenuItem{}).Where("restaurant_id = ? AND sku = ?", restaurantID, sku)
+	if itemID != nil {
+		query = query.Where("id <> ?", *itemID)
+	}
+	var taken int64
+	if err := query.Count(&taken).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to check SKU",
+			Error:   err.Error(),
+		})
+		return nil, false
+	}
+	if taken > 0 {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Message: "Another menu item already has SKU " + sku,
+		})
+		return nil, false
+	}
+	return &sku, true
+}
+
+// loadItemDetails loads a menu item's allergens, dietary tags, schedules and
+// combo slots.
+func (h *MenuHandler) loadItemDetails(item *models.MenuItem) error {
+	if err := h.db.DB.Where("menu_item_id = ?", item.ID).Order("allergen").Find(&item.Allergens).Error; err != nil {
+		return err
+	}
+	if err := h.db.DB.Where("menu_item_id = ?", item.ID).Order("tag").Find(&item.DietaryTags).Error; err != nil {
+		return err
+	}
+	if err := h.db.DB.Where("menu_item_id = ?", item.ID).Order("created_at, id").Find(&item.Schedules).Error; err != nil {
+		return err
+	}
+	return h.db.DB.Preload("Options.MenuItem").Where("combo_id = ?", item.ID).Order("position, id").Find(&item.ComboSlots).Error
 }
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxMenuImportSize is the largest menu file an import accepts.
+const maxMenuImportSize = 5 << 20
+
+// errMenuImportRollback rolls back a dry run, or an import with row errors.
+var errMenuImportRollback = errors.New("menu import rolled back")
+
+type MenuImportHandler struct {
+	db  *repository.Database
+	cfg *config.Config
+}
+
+// MenuImportReport says what an import changed, or would change in a dry
+// run, and lists the rows that stopped it.
+type MenuImportReport struct {
+	DryRun  bool                        `json:"dryRun"`
+	Applied bool                        `json:"applied"`
+	Errors  []services.MenuImportError  `json:"errors"`
+	Summary *services.MenuImportSummary `json:"summary,omitempty"`
+}
+
+type MenuImportResponse struct {
+	Success bool             `json:"success"`
+	Message string           `json:"message"`
+	Data    MenuImportReport `json:"data"`
+}
+
+func NewMenuImportHandler(db *repository.Database, cfg *config.Config) *MenuImportHandler {
+	return &MenuImportHandler{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// ImportMenu godoc
+// @Summary Import menu
+// @Description Create and update categories and items in bulk from CSV (one row per item) or the JSON export format. Categories are matched by name and items by sku, or by name within their category when they have none; nothing is deleted. The whole file is applied in one transaction, or not at all if any row has errors. With dryRun the import is checked and counted without saving
+// @Tags menu
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Security Bearer
+// @Param format query string false "File format; defaults to csv for a text/csv body, else json" Enums(csv, json)
+// @Param dryRun query bool false "Validate and report what would change without saving"
+// @Param menu body services.MenuImport true "Menu in the JSON export format, or CSV text"
+// @Success 200 {object} MenuImportResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 422 {object} MenuImportResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/import [post]
+func (h *MenuImportHandler) ImportMenu(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		format = "json"
+		if c.ContentType() == "text/csv" {
+			format = "csv"
+		}
+	}
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Format must be csv or json",
+		})
+		return
+	}
+	dryRun, _ := strconv.ParseBool(c.Query("dryRun"))
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxMenuImportSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+				Success: false,
+				Message: "Menu files can be at most 5 MB",
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to read the menu file",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	var menu *services.MenuImport
+	var rowErrors []services.MenuImportError
+	if format == "csv" {
+		menu, rowErrors = services.ParseMenuCSV(bytes.NewReader(body))
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&menu); err != nil || menu == nil {
+			message := "The file is empty"
+			if err != nil {
+				message = "Invalid JSON: " + err.Error()
+			}
+			rowErrors = append(rowErrors, services.MenuImportError{Message: message})
+			menu = nil
+		}
+	}
+	if menu != nil {
+		rowErrors = append(rowErrors, services.ValidateMenuImport(menu)...)
+	}
+
+	report := MenuImportReport{DryRun: dryRun, Errors: []services.MenuImportError{}}
+	if len(rowErrors) > 0 {
+		report.Errors = rowErrors
+		c.JSON(http.StatusUnprocessableEntity, MenuImportResponse{
+			Success: false,
+			Message: "The menu has errors; nothing was imported",
+			Data:    report,
+		})
+		return
+	}
+
+	var summary services.MenuImportSummary
+	err = h.db.DB.Transaction(func(tx *gorm.DB) error {
+		applied, rowErrors, err := services.ApplyMenuImport(tx, restaurant.ID, menu)
+		if err != nil {
+			return err
+		}
+		summary = applied
+		if len(rowErrors) > 0 {
+			report.Errors = rowErrors
+			return errMenuImportRollback
+		}
+		if dryRun {
+			return errMenuImportRollback
+		}
+		return nil
+	})
+	if err != nil && err != errMenuImportRollback {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to import menu",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if len(report.Errors) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, MenuImportResponse{
+			Success: false,
+			Message: "The menu has errors; nothing was imported",
+			Data:    report,
+		})
+		return
+	}
+
+	report.Summary = &summary
+	message := "Menu imported successfully"
+	if dryRun {
+		message = "Dry run passed; nothing was saved"
+	} else {
+		report.Applied = true
+	}
+	c.JSON(http.StatusOK, MenuImportResponse{
+		Success: true,
+		Message: message,
+		Data:    report,
+	})
+}
+
+// ExportMenu godoc
+// @Summary Export menu
+// @Description Download the whole menu, with nutrition, allergens and customizations, as CSV or in the JSON import format. Importing an export unchanged changes nothing
+// @Tags menu
+// @Produce json
+// @Produce text/csv
+// @Security Bearer
+// @Param format query string false "File format" Enums(json, csv) default(json)
+// @Success 200 {object} services.MenuImport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/export [get]
+func (h *MenuImportHandler) ExportMenu(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", "json"))
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Format must be csv or json",
+		})
+		return
+	}
+
+	menu, err := services.ExportMenu(h.db.DB, restaurant.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to export menu",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if format == "json" {
+		c.Header("Content-Disposition", `attachment; filename="menu.json"`)
+		c.JSON(http.StatusOK, menu)
+		return
+	}
+
+	var out bytes.Buffer
+	if err := services.WriteMenuCSV(&out, menu); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to export menu",
+			Error:   err.Error(),
+		})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="menu.csv"`)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", out.Bytes())
+}
@@ -0,0 +1,654 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MenuVersionHandler struct {
+	db  *repository.Database
+	cfg *config.Config
+}
+
+// MenuVersionRequest creates a draft; without a menu it copies the live menu.
+type MenuVersionRequest struct {
+	Name string               `json:"name" binding:"max=100"`
+	Menu *services.MenuImport `json:"menu"`
+}
+
+// UpdateMenuVersionRequest edits a draft; fields left out are kept.
+type UpdateMenuVersionRequest struct {
+	Name *string              `json:"name" binding:"omitempty,max=100"`
+	Menu *services.MenuImport `json:"menu"`
+}
+
+// PublishMenuVersionRequest publishes now, or at PublishAt when it's in the
+// future.
+type PublishMenuVersionRequest struct {
+	PublishAt *time.Time `json:"publishAt"`
+}
+
+type MenuVersionResponse struct {
+	ID          uuid.UUID                `json:"id"`
+	Number      int                      `json:"number"`
+	Name        string                   `json:"name"`
+	Status      models.MenuVersionStatus `json:"status"`
+	PublishAt   *time.Time               `json:"publishAt,omitempty"`
+	PublishedAt *time.Time               `json:"publishedAt,omitempty"`
+	LastError   string                   `json:"lastError,omitempty"`
+	CreatedAt   time.Time                `json:"createdAt"`
+	UpdatedAt   time.Time                `json:"updatedAt"`
+	Menu        *services.MenuImport     `json:"menu,omitempty"`
+}
+
+// PublishMenuVersionResult is a published version and what publishing it
+// changed on the live menu.
+type PublishMenuVersionResult struct {
+	Version MenuVersionResponse         `json:"version"`
+	Summary *services.MenuImportSummary `json:"summary,omitempty"`
+}
+
+// MenuDiffResult compares a version with another version or the live menu.
+type MenuDiffResult struct {
+	From string            `json:"from"`
+	To   string            `json:"to"`
+	Diff services.MenuDiff `json:"diff"`
+}
+
+// MenuErrorsResponse lists the rows of a menu that stopped it being saved or
+// published.
+type MenuErrorsResponse struct {
+	Success bool                       `json:"success"`
+	Message string                     `json:"message"`
+	Errors  []services.MenuImportError `json:"errors"`
+}
+
+func NewMenuVersionHandler(db *repository.Database, cfg *config.Config) *MenuVersionHandler {
+	return &MenuVersionHandler{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// GetMenuVersions godoc
+// @Summary List menu versions
+// @Description List the restaurant's menu versions, newest first, without their menus. One is published: the live menu
+// @Tags menu
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/versions [get]
+func (h *MenuVersionHandler) GetMenuVersions(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	var versions []models.MenuVersion
+	if err := h.db.DB.Omit("menu").Where("restaurant_id = ?", restaurant.ID).Order("number DESC").Find(&versions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch menu versions",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	responses := make([]MenuVersionResponse, len(versions))
+	for i := range versions {
+		responses[i] = toMenuVersionResponse(&versions[i], nil)
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Menu versions retrieved successfully",
+		Data:    responses,
+	})
+}
+
+// CreateMenuVersion godoc
+// @Summary Create draft menu version
+// @Description Save a draft menu in the menu import JSON format, or a copy of the live menu when none is given. Drafts don't change the live menu until published. The first draft also saves the live menu as the first published version, so it can be rolled back to
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param version body MenuVersionRequest false "Draft name and menu"
+// @Success 201 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} MenuErrorsResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/versions [post]
+func (h *MenuVersionHandler) CreateMenuVersion(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	var req MenuVersionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Invalid request data",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+	if !h.validateMenu(c, req.Menu) {
+		return
+	}
+
+	var version *models.MenuVersion
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		version, err = services.CreateMenuVersion(tx, restaurant.ID, req.Name, req.Menu)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to create menu version",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	h.respondWithMenu(c, http.StatusCreated, "Draft menu version created", version)
+}
+
+// GetMenuVersion godoc
+// @Summary Get menu version
+// @Description Get a menu version with its menu in the menu import JSON format
+// @Tags menu
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Menu version ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/versions/{id} [get]
+func (h *MenuVersionHandler) GetMenuVersion(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+	version, ok := h.findVersion(c, restaurant.ID, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	h.respondWithMenu(c, http.StatusOK, "Menu version retrieved successfully", version)
+}
+
+// UpdateMenuVersion godoc
+// @Summary Update draft menu version
+// @Description Rename a draft or replace its menu. Scheduled versions have to be unscheduled first, and published ones can't change
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Menu version ID"
+// @Param version body UpdateMenuVersionRequest true "Draft name and menu"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} MenuErrorsResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/versions/{id} [put]
+func (h *MenuVersionHandler) UpdateMenuVersion(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+	versionID, ok := parseMenuVersionID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateMenuVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if !h.validateMenu(c, req.Menu) {
+		return
+	}
+
+	var version *models.MenuVersion
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		version, err = services.UpdateMenuVersion(tx, restaurant.ID, versionID, req.Name, req.Menu)
+		return err
+	})
+	if err != nil {
+		h.versionError(c, err, "Failed to update menu version")
+		return
+	}
+
+	h.respondWithMenu(c, http.StatusOK, "Draft menu version updated", version)
+}
+
+// DeleteMenuVersion godoc
+// @Summary Delete menu version
+// @Description Delete a draft or scheduled version. Versions that have been published are kept for the orders priced from them
+// @Tags menu
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Menu version ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/versions/{id} [delete]
+func (h *MenuVersionHandler) DeleteMenuVersion(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+	versionID, ok := parseMenuVersionID(c)
+	if !ok {
+		return
+	}
+
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		return services.DeleteMenuVersion(tx, restaurant.ID, versionID)
+	})
+	if err != nil {
+		h.versionError(c, err, "Failed to delete menu version")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Menu version deleted successfully",
+	})
+}
+
+// DiffMenuVersion godoc
+// @Summary Compare menu versions
+// @Description List the categories and items a version adds, removes and changes compared with another version, or with the live menu. By default it's compared with the published version, or the live menu before the first
+// @Tags menu
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Menu version ID"
+// @Param against query string false "Menu version ID to compare with, or live"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/versions/{id}/diff [get]
+func (h *MenuVersionHandler) DiffMenuVersion(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+	version, ok := h.findVersion(c, restaurant.ID, c.Param("id"))
+	if !ok {
+		return
+	}
+	to, err := services.VersionMenu(version)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to read menu version",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	against := c.Query("against")
+	if against == "" && restaurant.MenuVersion > 0 {
+		var published models.MenuVersion
+		if err := h.db.DB.Select("id").Where("restaurant_id = ? AND number = ?", restaurant.ID, restaurant.MenuVersion).First(&published).Error; err != nil && err != gorm.ErrRecordNotFound {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch menu version",
+				Error:   err.Error(),
+			})
+			return
+		}
+		if published.ID != uuid.Nil {
+			against = published.ID.String()
+		}
+	}
+
+	result := MenuDiffResult{From: "live", To: versionLabel(version)}
+	var from *services.MenuImport
+	if against == "" || against == "live" {
+		if from, err = services.LiveMenu(h.db.DB, restaurant.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch menu",
+				Error:   err.Error(),
+			})
+			return
+		}
+	} else {
+		other, ok := h.findVersion(c, restaurant.ID, against)
+		if !ok {
+			return
+		}
+		if from, err = services.VersionMenu(other); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to read menu version",
+				Error:   err.Error(),
+			})
+			return
+		}
+		result.From = versionLabel(other)
+	}
+	result.Diff = services.DiffMenus(from, to)
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Menu versions compared",
+		Data:    result,
+	})
+}
+
+// PublishMenuVersion godoc
+// @Summary Publish menu version
+// @Description Publish a draft now, or schedule it for publishAt. Publishing makes the live menu match the version: categories and items it lists are created or updated, and the ones it leaves out are hidden, not deleted. If the version no longer fits the live menu, nothing changes and the rows at fault are listed
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Menu version ID"
+// @Param publish body PublishMenuVersionRequest false "When to publish; now if left out"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} MenuErrorsResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/versions/{id}/publish [post]
+func (h *MenuVersionHandler) PublishMenuVersion(c *gin.Context) {
+	var req PublishMenuVersionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Invalid request data",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	if req.PublishAt == nil || !req.PublishAt.After(time.Now()) {
+		h.publish(c, services.PublishMenuVersion, "Menu version published")
+		return
+	}
+
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+	versionID, ok := parseMenuVersionID(c)
+	if !ok {
+		return
+	}
+
+	var version *models.MenuVersion
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		version, err = services.ScheduleMenuVersion(tx, restaurant.ID, versionID, *req.PublishAt)
+		return err
+	})
+	if err != nil {
+		h.versionError(c, err, "Failed to schedule menu version")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Menu version scheduled",
+		Data:    PublishMenuVersionResult{Version: toMenuVersionResponse(version, nil)},
+	})
+}
+
+// UnscheduleMenuVersion godoc
+// @Summary Unschedule menu version
+// @Description Cancel a scheduled publish; the version goes back to draft
+// @Tags menu
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Menu version ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/versions/{id}/publish [delete]
+func (h *MenuVersionHandler) UnscheduleMenuVersion(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+	versionID, ok := parseMenuVersionID(c)
+	if !ok {
+		return
+	}
+
+	var version *models.MenuVersion
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		version, err = services.UnscheduleMenuVersion(tx, restaurant.ID, versionID)
+		return err
+	})
+	if err != nil {
+		h.versionError(c, err, "Failed to unschedule menu version")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Menu version unscheduled",
+		Data:    toMenuVersionResponse(version, nil),
+	})
+}
+
+// RollbackMenuVersion godoc
+// @Summary Roll back menu version
+// @Description Publish again a version that was published before, replacing the current one, as publishing does
+// @Tags menu
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Menu version ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} MenuErrorsResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /menu/versions/{id}/rollback [post]
+func (h *MenuVersionHandler) RollbackMenuVersion(c *gin.Context) {
+	h.publish(c, services.RollbackMenuVersion, "Menu rolled back")
+}
+
+type publishFunc func(tx *gorm.DB, restaurantID, versionID uuid.UUID) (*models.MenuVersion, services.MenuImportSummary, []services.MenuImportError, error)
+
+// publish makes a version live with publishFn, in one transaction that rolls
+// back on row errors.
+func (h *MenuVersionHandler) publish(c *gin.Context, publishFn publishFunc, message string) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+	versionID, ok := parseMenuVersionID(c)
+	if !ok {
+		return
+	}
+
+	var version *models.MenuVersion
+	var summary services.MenuImportSummary
+	var rowErrors []services.MenuImportError
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		version, summary, rowErrors, err = publishFn(tx, restaurant.ID, versionID)
+		if err == nil && len(rowErrors) > 0 {
+			return errMenuImportRollback
+		}
+		return err
+	})
+	if err == errMenuImportRollback {
+		c.JSON(http.StatusUnprocessableEntity, MenuErrorsResponse{
+			Success: false,
+			Message: "The version doesn't fit the live menu; nothing was published",
+			Errors:  rowErrors,
+		})
+		return
+	}
+	if err != nil {
+		h.versionError(c, err, "Failed to publish menu version")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: message,
+		Data: PublishMenuVersionResult{
+			Version: toMenuVersionResponse(version, nil),
+			Summary: &summary,
+		},
+	})
+}
+
+// validateMenu checks a menu sent with a draft, writing the 422 itself when
+// it has errors.
+func (h *MenuVersionHandler) validateMenu(c *gin.Context, menu *services.MenuImport) bool {
+	if menu == nil {
+		return true
+	}
+	if errs := services.ValidateMenuImport(menu); len(errs) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, MenuErrorsResponse{
+			Success: false,
+			Message: "The menu has errors; nothing was saved",
+			Errors:  errs,
+		})
+		return false
+	}
+	return true
+}
+
+// versionError writes the response for an error from a menu version service.
+func (h *MenuVersionHandler) versionError(c *gin.Context, err error, message string) {
+	if versionErr, ok := err.(*services.MenuVersionError); ok {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Message: versionErr.Message,
+		})
+	} else if err == gorm.ErrRecordNotFound {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Message: "Menu version not found",
+		})
+	} else {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: message,
+			Error:   err.Error(),
+		})
+	}
+}
+
+func parseMenuVersionID(c *gin.Context) (uuid.UUID, bool) {
+	versionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid menu version ID",
+		})
+		return uuid.Nil, false
+	}
+	return versionID, true
+}
+
+// findVersion loads one of the restaurant's versions, writing the error
+// response itself when it can't.
+func (h *MenuVersionHandler) findVersion(c *gin.Context, restaurantID uuid.UUID, id string) (*models.MenuVersion, bool) {
+	versionID, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid menu version ID",
+		})
+		return nil, false
+	}
+
+	var version models.MenuVersion
+	if err := h.db.DB.Where("id = ? AND restaurant_id = ?", versionID, restaurantID).First(&version).Error; err != nil {
+		h.versionError(c, err, "Failed to fetch menu version")
+		return nil, false
+	}
+	return &version, true
+}
+
+func (h *MenuVersionHandler) respondWithMenu(c *gin.Context, status int, message string, version *models.MenuVersion) {
+	menu, err := services.VersionMenu(version)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to read menu version",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(status, models.SuccessResponse{
+		Success: true,
+		Message: message,
+		Data:    toMenuVersionResponse(version, menu),
+	})
+}
+
+func versionLabel(version *models.MenuVersion) string {
+	return "v" + strconv.Itoa(version.Number)
+}
+
+func toMenuVersionResponse(version *models.MenuVersion, menu *services.MenuImport) MenuVersionResponse {
+	return MenuVersionResponse{
+		ID:          version.ID,
+		Number:      version.Number,
+		Name:        version.Name,
+		Status:      version.Status,
+		PublishAt:   version.PublishAt,
+		PublishedAt: version.PublishedAt,
+		LastError:   version.LastError,
+		CreatedAt:   version.CreatedAt,
+		UpdatedAt:   version.UpdatedAt,
+		Menu:        menu,
+	}
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"net/http"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/middleware"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationHandler struct {
+	db  *repository.Database
+	cfg *config.Config
+}
+
+type NotificationPreferencesResponse struct {
+	Locale   string                         `json:"locale"`
+	Channels models.NotificationPreferences `json:"channels"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	Locale string `json:"locale"`
+	Email  *bool  `json:"email"`
+	SMS    *bool  `json:"sms"`
+	Push   *bool  `json:"push"`
+}
+
+func NewNotificationHandler(db *repository.Database, cfg *config.Config) *NotificationHandler {
+	return &NotificationHandler{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// GetNotificationPreferences godoc
+// @Summary Get notification preferences
+// @Description Get the current user's notification channels and locale
+// @Tags notifications
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /users/me/notification-preferences [get]
+func (h *NotificationHandler) GetNotificationPreferences(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Notification preferences retrieved successfully",
+		Data:    toNotificationPreferencesResponse(user),
+	})
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary Update notification preferences
+// @Description Enable or disable email, SMS and push notifications and set the notification locale
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param preferences body UpdateNotificationPreferencesRequest true "Notification preferences"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users/me/notification-preferences [put]
+func (h *NotificationHandler) UpdateNotificationPreferences(c *gin.Context) {
+	var req UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if req.Locale != "" && !services.IsSupportedLocale(req.Locale) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Unsupported locale",
+		})
+		return
+	}
+
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	if req.Locale != "" {
+		user.Locale = req.Locale
+	}
+	if req.Email != nil {
+		user.NotificationPreferences.Email = *req.Email
+	}
+	if req.SMS != nil {
+		user.NotificationPreferences.SMS = *req.SMS
+	}
+	if req.Push != nil {
+		user.NotificationPreferences.Push = *req.Push
+	}
+
+	if err := h.db.DB.Model(user).Updates(map[string]interface{}{
+		"locale":       user.Locale,
+		"notify_email": user.NotificationPreferences.Email,
+		"notify_sms":   user.NotificationPreferences.SMS,
+		"notify_push":  user.NotificationPreferences.Push,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to update notification preferences",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Notification preferences updated successfully",
+		Data:    toNotificationPreferencesResponse(user),
+	})
+}
+
+// GetNotifications godoc
+// @Summary Get notification history
+// @Description Get the notifications sent or queued for the current user
+// @Tags notifications
+// @Produce json
+// @Security Bearer
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Page cursor: empty for the first page, then the previous page's nextCursor; replaces page"
+// @Param limit query int false "Items per page" default(20)
+// @Param channel query string false "Filter by channel (email, sms, push)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users/me/notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	page, ok := parsePage(c, newestFirst("notifications"), 20)
+	if !ok {
+		return
+	}
+
+	query := h.db.DB.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if channel := c.Query("channel"); channel != "" {
+		query = query.Where("channel = ?", channel)
+	}
+
+	total, err := page.countTotal(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to count notifications",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var notifications []models.Notification
+	if err := page.apply(query).Find(&notifications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch notifications",
+			Error:   err.Error(),
+		})
+		return
+	}
+	notifications, pagination := finishPage(page, notifications, total, func(notification *models.Notification) (uuid.UUID, []interface{}) {
+		return notification.ID, []interface{}{notification.CreatedAt}
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Notifications retrieved successfully",
+		"data": gin.H{
+			"notifications": notifications,
+			"pagination":    pagination,
+		},
+	})
+}
+
+func (h *NotificationHandler) currentUser(c *gin.Context) (*models.User, bool) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return nil, false
+	}
+
+	var user models.User
+	if err := h.db.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Message: "User not found",
+		})
+		return nil, false
+	}
+
+	return &user, true
+}
+
+func toNotificationPreferencesResponse(user *models.User) NotificationPreferencesResponse {
+	return NotificationPreferencesResponse{
+		Locale:   user.Locale,
+		Channels: user.NotificationPreferences,
+	}
+}
@@ -2,16 +2,18 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
+	"time"
 
 	"restaurantapp/config"
 	"restaurantapp/internal/models"
 	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
 	"restaurantapp/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderHandler struct {
@@ -20,25 +22,38 @@ type OrderHandler struct {
 }
 
 type CreateOrderRequest struct {
-	RestaurantID        uuid.UUID                   `json:"restaurantId" binding:"required"`
-	Items               []CreateOrderItemRequest    `json:"items" binding:"required,min=1"`
-	DeliveryAddressID   uuid.UUID                   `json:"deliveryAddressId" binding:"required"`
+	RestaurantID        uuid.UUID                   `json:"restaurantId"`
+	CartID              *uuid.UUID                  `json:"cartId"`
+	GroupOrderID        *uuid.UUID                  `json:"groupOrderId"`
+	Items               []CreateOrderItemRequest    `json:"items" binding:"omitempty,dive"`
+	FulfilmentType      models.FulfilmentType       `json:"fulfilmentType"`
+	DeliveryAddressID   *uuid.UUID                  `json:"deliveryAddressId"`
+	TableNumber         string                      `json:"tableNumber"`
 	PaymentMethodType   models.PaymentMethodType    `json:"paymentMethodType" binding:"required"`
 	PaymentDetails      interface{}                 `json:"paymentDetails"`
 	SpecialInstructions string                      `json:"specialInstructions"`
 	Tip                 float64                     `json:"tip"`
+	PromoCode           string                      `json:"promoCode"`
+	RedeemPoints        int                         `json:"redeemPoints" binding:"gte=0"`
 }
 
 type CreateOrderItemRequest struct {
 	MenuItemID          uuid.UUID   `json:"menuItemId" binding:"required"`
 	Quantity            int         `json:"quantity" binding:"required,min=1"`
+	Selections          []models.CustomizationSelection `json:"selections" binding:"omitempty,dive"`
+	// Components fill the slots of a combo
+	Components          []models.ComboSelection `json:"components" binding:"omitempty,dive"`
 	CustomizationsData  interface{} `json:"customizationsData"`
 	SpecialInstructions string      `json:"specialInstructions"`
+
+	// addedByID is the group order participant who added the item
+	addedByID *uuid.UUID
 }
 
 type UpdateOrderStatusRequest struct {
-	Status  models.OrderStatus `json:"status" binding:"required"`
-	Message string             `json:"message"`
+	Status     models.OrderStatus `json:"status" binding:"required"`
+	Message    string             `json:"message"`
+	PickupCode string             `json:"pickupCode"`
 }
 
 func NewOrderHandler(db *repository.Database, cfg *config.Config) *OrderHandler {
@@ -50,14 +65,16 @@ func NewOrderHandler(db *repository.Database, cfg *config.Config) *OrderHandler
 
 // CreateOrder handles order creation
 // @Summary Create a new order
-// @Description Create a new order with items
+// @Description Create a new order from a list of items, by checking out a saved cart (cartId) or by submitting a locked group order as its host (groupOrderId). Delivery orders need a delivery address; pickup and dine-in orders have no delivery fee, and pickup orders get a pickup code. Combo items fill their slots with components. allergenWarnings lists items containing allergens saved on the customer's profile
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Param order body CreateOrderRequest true "Order details"
+// @Param Idempotency-Key header string false "Key that makes retries of this request safe"
 // @Success 201 {object} models.OrderResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Security Bearer
 // @Router /orders [post]
@@ -74,6 +91,17 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		return
 	}
 
+	if req.CartID != nil && req.GroupOrderID != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Use either cartId or groupOrderId, not both"})
+		return
+	}
+	if req.CartID == nil && req.GroupOrderID == nil {
+		if req.RestaurantID == uuid.Nil || len(req.Items) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Either cartId, groupOrderId or restaurantId with items is required"})
+			return
+		}
+	}
+
 	// Start transaction
 	tx := h.db.DB.Begin()
 	defer func() {
@@ -82,6 +110,97 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		}
 	}()
 
+	// Check out from a saved cart
+	var cart models.Cart
+	if req.CartID != nil {
+		if err := tx.Preload("Items").Where("id = ? AND user_id = ?", *req.CartID, userID).First(&cart).Error; err != nil {
+			tx.Rollback()
+			if err == gorm.ErrRecordNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Cart not found"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load cart"})
+			}
+			return
+		}
+		if cart.IsExpired() {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cart has expired"})
+			return
+		}
+		if len(cart.Items) == 0 {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cart is empty"})
+			return
+		}
+		if req.RestaurantID != uuid.Nil && req.RestaurantID != cart.RestaurantID {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cart belongs to a different restaurant"})
+			return
+		}
+
+		req.RestaurantID = cart.RestaurantID
+		req.Items = nil
+		for _, cartItem := range cart.Items {
+			req.Items = append(req.Items, CreateOrderItemRequest{
+				MenuItemID:          cartItem.MenuItemID,
+				Quantity:            cartItem.Quantity,
+				Selections:          cartItem.Selections,
+				Components:          cartItem.Components,
+				SpecialInstructions: cartItem.SpecialInstructions,
+			})
+		}
+	}
+
+	// Check out a locked group order; only its host can submit it
+	var groupOrder models.GroupOrder
+	if req.GroupOrderID != nil {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND host_id = ?", *req.GroupOrderID, userID).
+			First(&groupOrder).Error; err != nil {
+			tx.Rollback()
+			if err == gorm.ErrRecordNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Group order not found"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load group order"})
+			}
+			return
+		}
+		if groupOrder.Status != models.GroupOrderLocked {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Lock the group order before submitting it"})
+			return
+		}
+		if req.RestaurantID != uuid.Nil && req.RestaurantID != groupOrder.RestaurantID {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Group order belongs to a different restaurant"})
+			return
+		}
+		if err := tx.Where("group_order_id = ?", groupOrder.ID).Order("created_at ASC").Find(&groupOrder.Items).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load group order items"})
+			return
+		}
+		if len(groupOrder.Items) == 0 {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Group order is empty"})
+			return
+		}
+
+		req.RestaurantID = groupOrder.RestaurantID
+		req.Items = nil
+		for _, groupItem := range groupOrder.Items {
+			addedByID := groupItem.AddedByID
+			req.Items = append(req.Items, CreateOrderItemRequest{
+				MenuItemID:          groupItem.MenuItemID,
+				Quantity:            groupItem.Quantity,
+				Selections:          groupItem.Selections,
+				Components:          groupItem.Components,
+				SpecialInstructions: groupItem.SpecialInstructions,
+				addedByID:           &addedByID,
+			})
+		}
+	}
+
 	// Verify restaurant exists and is active
 	var restaurant models.Restaurant
 	if err := tx.Where("id = ? AND is_active = true", req.RestaurantID).First(&restaurant).Error; err != nil {
@@ -93,28 +212,91 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		}
 		return
 	}
+	// Closed restaurants take no orders, including ones closed for missing too
+	// many in a row
+	if !restaurant.IsOpen {
+		tx.Rollback()
+		c.JSON(http.StatusConflict, gin.H{"error": "Restaurant is closed"})
+		return
+	}
 
-	// Verify delivery address belongs to user
-	var address models.Address
-	if err := tx.Where("id = ? AND user_id = ?", req.DeliveryAddressID, userID).First(&address).Error; err != nil {
+	// Pickup and dine-in have to be offered by the restaurant
+	if req.FulfilmentType == "" {
+		req.FulfilmentType = models.DeliveryFulfilment
+	}
+	switch req.FulfilmentType {
+	case models.DeliveryFulfilment:
+	case models.PickupFulfilment:
+		if !restaurant.OffersPickup {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Restaurant does not offer pickup"})
+			return
+		}
+	case models.DineInFulfilment:
+		if !restaurant.OffersDineIn {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Restaurant does not offer dine-in"})
+			return
+		}
+	default:
 		tx.Rollback()
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Delivery address not found"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fulfilment type"})
+		return
+	}
+
+	// Verify delivery address belongs to user; only delivery orders need one
+	if req.FulfilmentType == models.DeliveryFulfilment {
+		if req.DeliveryAddressID == nil {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Delivery address is required for delivery orders"})
+			return
+		}
+		var address models.Address
+		if err := tx.Where("id = ? AND user_id = ?", *req.DeliveryAddressID, userID).First(&address).Error; err != nil {
+			tx.Rollback()
+			if err == gorm.ErrRecordNotFound {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Delivery address not found"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify delivery address"})
+			}
+			return
+		}
+	} else {
+		req.DeliveryAddressID = nil
+	}
+
+	// A busy restaurant may cap the orders in its kitchen
+	if err := services.ReserveKitchenCapacity(tx, &restaurant); err != nil {
+		tx.Rollback()
+		if err == services.ErrRestaurantAtCapacity {
+			c.JSON(http.StatusConflict, gin.H{"error": "Restaurant is too busy to take more orders right now"})
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify delivery address"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check restaurant capacity"})
 		}
 		return
 	}
 
+	// Items record the menu version they were priced from
+	menuVersion, err := services.CurrentMenuVersion(tx, req.RestaurantID)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load menu version"})
+		return
+	}
+
 	// Calculate order total
 	var totalAmount float64
 	var orderItems []models.OrderItem
+	var prepMinutes int
+	stock := services.NewStockTake()
 
 	for _, item := range req.Items {
-		var menuItem models.MenuItem
-		if err := tx.Where("id = ? AND restaurant_id = ? AND is_available = true", item.MenuItemID, req.RestaurantID).First(&menuItem).Error; err != nil {
+		priced, err := services.PriceOrderItem(tx, req.RestaurantID, item.MenuItemID, item.Selections, item.Components)
+		if err != nil {
 			tx.Rollback()
-			if err == gorm.ErrRecordNotFound {
+			if selectionErr, ok := err.(*services.SelectionError); ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": selectionErr.Message})
+			} else if err == services.ErrMenuItemUnavailable {
 				c.JSON(http.StatusBadRequest, gin.H{"error": "Menu item not found or unavailable"})
 			} else {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify menu item"})
@@ -122,17 +304,47 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 			return
 		}
 
-		itemTotal := menuItem.Price * float64(item.Quantity)
+		// Dayparted items can only be ordered while they're served, combo
+		// components included
+		scheduled := []*models.MenuItem{&priced.MenuItem}
+		for _, component := range priced.Components {
+			scheduled = append(scheduled, &component.Item.MenuItem)
+		}
+		for _, menuItem := range scheduled {
+			if err := services.CheckMenuSchedule(tx, &restaurant, menuItem, time.Now()); err != nil {
+				tx.Rollback()
+				if scheduleErr, ok := err.(*services.ScheduleError); ok {
+					c.JSON(http.StatusBadRequest, gin.H{"error": scheduleErr.Message})
+				} else {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check menu schedule"})
+				}
+				return
+			}
+		}
+
+		stock.Add(priced, item.Quantity)
+
+		itemTotal := priced.UnitPrice * float64(item.Quantity)
 		totalAmount += itemTotal
+		if priced.MenuItem.PreparationTime > prepMinutes {
+			prepMinutes = priced.MenuItem.PreparationTime
+		}
 
+		// Selections are stored resolved, with option names and modifiers
 		customizationsJSON, _ := utils.ToJSON(item.CustomizationsData)
+		if len(item.Selections) > 0 {
+			customizationsJSON, _ = utils.ToJSON(priced.Customizations)
+		}
 
 		orderItem := models.OrderItem{
 			MenuItemID:          item.MenuItemID,
-			Name:                menuItem.Name,
-			Price:               menuItem.Price,
+			AddedByID:           item.addedByID,
+			Name:                priced.MenuItem.Name,
+			Price:               priced.UnitPrice,
+			MenuVersion:         menuVersion,
 			Quantity:            item.Quantity,
 			CustomizationsData:  customizationsJSON,
+			Components:          priced.OrderComponents(),
 			SpecialInstructions: item.SpecialInstructions,
 		}
 		orderItems = append(orderItems, orderItem)
@@ -140,12 +352,33 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 
 	// Calculate delivery fee based on distance (simplified)
 	deliveryFee := 2.99
-	if totalAmount > 35 {
-		deliveryFee = 0 // Free delivery for orders over $35
+	if totalAmount > 35 || req.FulfilmentType != models.DeliveryFulfilment {
+		deliveryFee = 0 // Free delivery for orders over $35; no fee without delivery
+	}
+
+	// Apply promo code
+	var promotion *models.Promotion
+	var discount float64
+	if req.PromoCode != "" {
+		var err error
+		promotion, discount, err = services.ApplyPromotion(tx, req.PromoCode, userID.(uuid.UUID), req.RestaurantID, totalAmount, deliveryFee)
+		if err != nil {
+			tx.Rollback()
+			if promoErr, ok := err.(*services.PromotionError); ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": promoErr.Message})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply promo code"})
+			}
+			return
+		}
 	}
 
-	// Calculate tax (8% for example)
-	tax := totalAmount * 0.08
+	// Calculate tax (8% for example) on the discounted item subtotal
+	taxable := totalAmount
+	if promotion != nil && promotion.Type != models.FreeDeliveryPromotion {
+		taxable -= discount
+	}
+	tax := taxable * 0.08
 
 	paymentDetailsJSON, _ := utils.ToJSON(req.PaymentDetails)
 
@@ -158,10 +391,42 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		DeliveryFee:           deliveryFee,
 		Tax:                   tax,
 		Tip:                   req.Tip,
+		FulfilmentType:        req.FulfilmentType,
 		DeliveryAddressID:     req.DeliveryAddressID,
 		PaymentMethodType:     req.PaymentMethodType,
 		PaymentDetails:        paymentDetailsJSON,
 		SpecialInstructions:   req.SpecialInstructions,
+		DiscountAmount:        discount,
+		GroupOrderID:          req.GroupOrderID,
+	}
+	estimatedAt := services.EstimateReadyAt(&restaurant, req.FulfilmentType, prepMinutes, time.Now())
+	order.EstimatedDeliveryTime = &estimatedAt
+	if promotion != nil {
+		order.PromotionID = &promotion.ID
+		order.PromoCode = promotion.Code
+	}
+	if req.FulfilmentType == models.DineInFulfilment {
+		order.TableNumber = req.TableNumber
+	}
+	if req.FulfilmentType == models.PickupFulfilment {
+		pickupCode, err := services.NewPickupCode()
+		if err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate pickup code"})
+			return
+		}
+		order.PickupCode = pickupCode
+	}
+
+	// Loyalty points pay for part of the order; they can't cover more than its total
+	if req.RedeemPoints > 0 {
+		order.PointsRedeemed = req.RedeemPoints
+		order.PointsAmount = services.PointsValue(&h.cfg.Loyalty, req.RedeemPoints)
+		if order.PointsAmount > order.GrandTotal() {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Redeemed points exceed the order total"})
+			return
+		}
 	}
 
 	if err := tx.Create(&order).Error; err != nil {
@@ -170,6 +435,26 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		return
 	}
 
+	if promotion != nil {
+		if err := services.RedeemPromotion(tx, promotion, &order); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to redeem promo code"})
+			return
+		}
+	}
+
+	if order.PointsRedeemed > 0 {
+		if err := services.RedeemPoints(tx, order.UserID, order.ID, order.PointsRedeemed); err != nil {
+			tx.Rollback()
+			if walletErr, ok := err.(*services.WalletError); ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": walletErr.Message})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to redeem loyalty points"})
+			}
+			return
+		}
+	}
+
 	// Create order items
 	for i := range orderItems {
 		orderItems[i].OrderID = order.ID
@@ -180,6 +465,17 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		}
 	}
 
+	// Tracked items and options come out of stock with the order
+	if err := services.TakeStock(tx, &order, stock); err != nil {
+		tx.Rollback()
+		if stockErr, ok := err.(*services.StockError); ok {
+			c.JSON(http.StatusConflict, gin.H{"error": stockErr.Message})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update stock"})
+		}
+		return
+	}
+
 	// Create initial tracking update
 	trackingUpdate := models.TrackingUpdate{
 		OrderID: order.ID,
@@ -192,6 +488,35 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		return
 	}
 
+	// A checked-out cart is consumed by the order
+	if req.CartID != nil {
+		if err := tx.Delete(&cart).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear cart"})
+			return
+		}
+	}
+
+	// A submitted group order points at the order it became
+	if req.GroupOrderID != nil {
+		if err := tx.Model(&groupOrder).Updates(map[string]interface{}{
+			"status":   models.GroupOrderSubmitted,
+			"order_id": order.ID,
+		}).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit group order"})
+			return
+		}
+	}
+
+	// Publish the domain event in the same transaction as the order
+	order.Items = orderItems
+	if err := services.PublishEvent(tx, "order", order.ID, models.OrderCreatedEvent, models.NewOrderEventPayload(&order, "", trackingUpdate.Message)); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record order event"})
+		return
+	}
+
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit order"})
@@ -204,10 +529,26 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		return
 	}
 
+	// Warn about items containing allergens the customer avoids; the order
+	// stands either way
+	var menuItemIDs []uuid.UUID
+	for _, item := range order.Items {
+		menuItemIDs = append(menuItemIDs, item.MenuItemID)
+		for _, component := range item.Components {
+			menuItemIDs = append(menuItemIDs, component.MenuItemID)
+		}
+	}
+	allergenWarnings, err := services.AllergenWarnings(h.db.DB, order.UserID, menuItemIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check allergens"})
+		return
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
-		"success": true,
-		"message": "Order created successfully",
-		"data":    order,
+		"success":          true,
+		"message":          "Order created successfully",
+		"data":             order,
+		"allergenWarnings": allergenWarnings,
 	})
 }
 
@@ -217,8 +558,10 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 // @Tags orders
 // @Produce json
 // @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Page cursor: empty for the first page, then the previous page's nextCursor; replaces page"
 // @Param limit query int false "Items per page" default(10)
 // @Success 200 {object} models.OrdersResponse
+// @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Security Bearer
@@ -230,39 +573,34 @@ func (h *OrderHandler) GetUserOrders(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	offset := (page - 1) * limit
-
-	var orders []models.Order
-	var total int64
+	page, ok := parsePage(c, newestFirst("orders"), 10)
+	if !ok {
+		return
+	}
 
 	// Get total count
-	if err := h.db.DB.Model(&models.Order{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+	total, err := page.countTotal(h.db.DB.Model(&models.Order{}).Where("user_id = ?", userID))
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count orders"})
 		return
 	}
 
 	// Get orders with relationships
-	if err := h.db.DB.Where("user_id = ?", userID).
+	var orders []models.Order
+	if err := page.apply(h.db.DB.Where("user_id = ?", userID)).
 		Preload("Restaurant").
 		Preload("DeliveryAddress").
 		Preload("Items.MenuItem").
-		Order("created_at DESC").
-		Limit(limit).Offset(offset).
 		Find(&orders).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch orders"})
 		return
 	}
+	orders, pagination := finishPage(page, orders, total, orderPageKey)
 
 	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    orders,
-		"pagination": gin.H{
-			"page":  page,
-			"limit": limit,
-			"total": total,
-		},
+		"success":    true,
+		"data":       orders,
+		"pagination": pagination,
 	})
 }
 
@@ -300,6 +638,10 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 		Preload("TrackingUpdates", func(db *gorm.DB) *gorm.DB {
 			return db.Order("created_at DESC")
 		}).
+		Preload("Refunds", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at DESC")
+		}).
+		Preload("Refunds.Items").
 		First(&order).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
@@ -317,17 +659,19 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 
 // UpdateOrderStatus handles updating order status (restaurant owner only)
 // @Summary Update order status
-// @Description Update order status and add tracking update
+// @Description Update order status along the order's fulfilment path and add tracking update. Delivery goes ready_for_pickup → picked_up → on_the_way → delivered, pickup goes ready_for_pickup → collected (send the customer's pickupCode), dine-in goes preparing → delivered
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Param id path string true "Order ID"
 // @Param status body UpdateOrderStatusRequest true "Status update"
+// @Param Idempotency-Key header string false "Key that makes retries of this request safe"
 // @Success 200 {object} models.OrderResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Security Bearer
 // @Router /orders/{id}/status [patch]
@@ -352,7 +696,7 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 
 	// Verify user owns the restaurant for this order
 	var order models.Order
-	if err := h.db.DB.Preload("Restaurant").Where("id = ?", orderID).First(&order).Error; err != nil {
+	if err := h.db.DB.Preload("Restaurant").Preload("Items").Where("id = ?", orderID).First(&order).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
 		} else {
@@ -366,6 +710,18 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 		return
 	}
 
+	// Each fulfilment type has its own status path
+	if !order.CanMoveTo(req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot change a " + string(order.FulfilmentType) + " order from " + string(order.Status) + " to " + string(req.Status)})
+		return
+	}
+
+	// Pickup orders are handed over against the customer's code
+	if req.Status == models.CollectedStatus && req.PickupCode != order.PickupCode {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pickup code does not match"})
+		return
+	}
+
 	// Start transaction
 	tx := h.db.DB.Begin()
 	defer func() {
@@ -374,12 +730,29 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 		}
 	}()
 
-	// Update order status
-	if err := tx.Model(&order).Update("status", req.Status).Error; err != nil {
+	// Update order status, unless it changed since it was read (a pending
+	// order may have just been cancelled for not being accepted in time)
+	previousStatus := order.Status
+	result := tx.Model(&order).Where("status = ?", previousStatus).Update("status", req.Status)
+	if result.Error != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update order status"})
 		return
 	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		c.JSON(http.StatusConflict, gin.H{"error": "Order status has changed, reload the order and try again"})
+		return
+	}
+
+	// Accepting an order clears the restaurant's run of missed orders
+	if previousStatus == models.PendingStatus && req.Status == models.ConfirmedStatus && order.Restaurant.MissedOrders > 0 {
+		if err := tx.Model(&models.Restaurant{}).Where("id = ?", order.RestaurantID).Update("missed_orders", 0).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update restaurant"})
+			return
+		}
+	}
 
 	// Create tracking update
 	message := req.Message
@@ -391,12 +764,20 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 			message = "Order is being prepared"
 		case models.ReadyForPickupStatus:
 			message = "Order is ready for pickup"
+			if order.FulfilmentType == models.PickupFulfilment {
+				message = "Order is ready for pickup at the counter"
+			}
 		case models.PickedUpStatus:
 			message = "Order picked up by delivery driver"
 		case models.OnTheWayStatus:
 			message = "Order is on the way"
 		case models.DeliveredStatus:
 			message = "Order delivered successfully"
+			if order.FulfilmentType == models.DineInFulfilment {
+				message = "Order served"
+			}
+		case models.CollectedStatus:
+			message = "Order collected"
 		case models.CancelledStatus:
 			message = "Order cancelled"
 		default:
@@ -415,6 +796,12 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 		return
 	}
 
+	if err := services.PublishEvent(tx, "order", order.ID, models.OrderStatusChangedEvent, models.NewOrderEventPayload(&order, previousStatus, message)); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record order event"})
+		return
+	}
+
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit status update"})
@@ -443,8 +830,10 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 // @Produce json
 // @Param status query string false "Filter by status"
 // @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Page cursor: empty for the first page, then the previous page's nextCursor; replaces page"
 // @Param limit query int false "Items per page" default(10)
 // @Success 200 {object} models.OrdersResponse
+// @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Security Bearer
@@ -467,43 +856,44 @@ func (h *OrderHandler) GetRestaurantOrders(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	offset := (page - 1) * limit
+	page, ok := parsePage(c, newestFirst("orders"), 10)
+	if !ok {
+		return
+	}
 	status := c.Query("status")
 
-	query := h.db.DB.Where("restaurant_id = ?", restaurant.ID)
+	query := h.db.DB.Model(&models.Order{}).Where("restaurant_id = ?", restaurant.ID)
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
 
-	var orders []models.Order
-	var total int64
-
 	// Get total count
-	if err := query.Model(&models.Order{}).Count(&total).Error; err != nil {
+	total, err := page.countTotal(query)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count orders"})
 		return
 	}
 
 	// Get orders
-	if err := query.Preload("User").
+	var orders []models.Order
+	if err := page.apply(query).
+		Preload("User").
 		Preload("DeliveryAddress").
 		Preload("Items.MenuItem").
-		Order("created_at DESC").
-		Limit(limit).Offset(offset).
 		Find(&orders).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch orders"})
 		return
 	}
+	orders, pagination := finishPage(page, orders, total, orderPageKey)
 
 	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    orders,
-		"pagination": gin.H{
-			"page":  page,
-			"limit": limit,
-			"total": total,
-		},
+		"success":    true,
+		"data":       orders,
+		"pagination": pagination,
 	})
+}
+
+// orderPageKey is the cursor key of order lists, newest first.
+func orderPageKey(order *models.Order) (uuid.UUID, []interface{}) {
+	return order.ID, []interface{}{order.CreatedAt}
 }
\ No newline at end of file
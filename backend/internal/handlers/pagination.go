@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"restaurantapp/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Pagination is the envelope every paginated list returns. Lists page either
+// by number (?page=2) or, without the cost of OFFSET and COUNT and without
+// skipping or repeating rows as new ones arrive, by cursor (?cursor= for the
+// first page, then the nextCursor of the previous one). Page, Total and Pages
+// are only set when paging by number.
+type Pagination struct {
+	Limit      int    `json:"limit"`
+	HasMore    bool   `json:"hasMore"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+	Pages      *int64 `json:"pages,omitempty"`
+}
+
+type sortKeyKind int
+
+const (
+	timeKey sortKeyKind = iota
+	floatKey
+	intKey
+	stringKey
+)
+
+// sortKey is one column, or expression, a list is sorted by. Keys must not
+// be NULL.
+type sortKey struct {
+	Column string
+	Kind   sortKeyKind
+}
+
+// listOrder is the sort order of a list: its keys, then the row ID to break
+// ties, all in the same direction. Vars are the named parameters of key
+// expressions.
+type listOrder struct {
+	Keys []sortKey
+	ID   string
+	Desc bool
+	Vars map[string]interface{}
+}
+
+// newestFirst is the order of most lists: latest created first.
+func newestFirst(table string) listOrder {
+	return listOrder{
+		Keys: []sortKey{{Column: table + ".created_at", Kind: timeKey}},
+		ID:   table + ".id",
+		Desc: true,
+	}
+}
+
+// signature identifies the order so a cursor from one can't be used with
+// another.
+func (o listOrder) signature() string {
+	columns := make([]string, len(o.Keys))
+	for i, key := range o.Keys {
+		columns[i] = key.Column
+	}
+	if o.Desc {
+		return strings.Join(columns, ",") + " desc"
+	}
+	return strings.Join(columns, ",") + " asc"
+}
+
+// pageCursor is the position after the last row of a page: its sort key
+// values and ID. It travels as base64url-encoded JSON.
+type pageCursor struct {
+	Order  string        `json:"o"`
+	Values []interface{} `json:"v"`
+	ID     uuid.UUID     `json:"id"`
+}
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+func encodeCursor(order listOrder, id uuid.UUID, values []interface{}) string {
+	cursor := pageCursor{Order: order.signature(), Values: make([]interface{}, len(values)), ID: id}
+	for i, value := range values {
+		if t, ok := value.(time.Time); ok {
+			value = t.UTC().Format(time.RFC3339Nano)
+		}
+		cursor.Values[i] = value
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(order listOrder, s string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	var cursor pageCursor
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&cursor); err != nil || cursor.Order != order.signature() || len(cursor.Values) != len(order.Keys) {
+		return nil, errInvalidCursor
+	}
+
+	// JSON loses the key types; restore them so they compare as the columns do
+	for i, key := range order.Keys {
+		var value interface{}
+		switch raw := cursor.Values[i].(type) {
+		case string:
+			switch key.Kind {
+			case timeKey:
+				value, err = time.Parse(time.RFC3339Nano, raw)
+			case stringKey:
+				value = raw
+			}
+		case json.Number:
+			switch key.Kind {
+			case floatKey:
+				value, err = raw.Float64()
+			case intKey:
+				value, err = raw.Int64()
+			}
+		}
+		if value == nil || err != nil {
+			return nil, errInvalidCursor
+		}
+		cursor.Values[i] = value
+	}
+	return &cursor, nil
+}
+
+// pageRequest is the page a list request asks for.
+type pageRequest struct {
+	order  listOrder
+	Limit  int
+	Page   int
+	keyset bool
+	after  *pageCursor
+}
+
+// parsePage reads the limit and either the page number or the cursor of a
+// list request. It writes the error response itself for a bad cursor.
+func parsePage(c *gin.Context, order listOrder, defaultLimit int) (*pageRequest, bool) {
+	page := &pageRequest{order: order, Limit: defaultLimit, Page: 1}
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 100 {
+		page.Limit = l
+	}
+
+	cursor, keyset := c.GetQuery("cursor")
+	if !keyset {
+		if p, err := strconv.Atoi(c.Query("page")); err == nil && p > 0 {
+			page.Page = p
+		}
+		return page, true
+	}
+
+	page.keyset = true
+	page.Page = 0
+	if cursor != "" {
+		after, err := decodeCursor(order, cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Invalid cursor; start again without one",
+			})
+			return nil, false
+		}
+		page.after = after
+	}
+	return page, true
+}
+
+// countTotal counts the rows of a list for paging by number. Paging by cursor
+// doesn't count, and gets nil.
+func (p *pageRequest) countTotal(query *gorm.DB) (*int64, error) {
+	if p.keyset {
+		return nil, nil
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	return &total, nil
+}
+
+// apply sorts query and limits it to the page, plus one row to tell whether
+// there are more.
+func (p *pageRequest) apply(query *gorm.DB) *gorm.DB {
+	direction, comparison := " ASC", ">"
+	if p.order.Desc {
+		direction, comparison = " DESC", "<"
+	}
+
+	vars := make(map[string]interface{}, len(p.order.Vars)+len(p.order.Keys)+1)
+	for name, value := range p.order.Vars {
+		vars[name] = value
+	}
+
+	columns := make([]string, 0, len(p.order.Keys)+1)
+	orderBy := make([]string, 0, len(p.order.Keys)+1)
+	for _, key := range p.order.Keys {
+		columns = append(columns, key.Column)
+		orderBy = append(orderBy, key.Column+direction)
+	}
+	columns = append(columns, p.order.ID)
+	orderBy = append(orderBy, p.order.ID+direction)
+
+	query = query.Order(clause.OrderBy{Expression: clause.NamedExpr{
+		SQL:  strings.Join(orderBy, ", "),
+		Vars: []interface{}{vars},
+	}})
+
+	if !p.keyset {
+		return query.Offset((p.Page - 1) * p.Limit).Limit(p.Limit + 1)
+	}
+
+	if p.after != nil {
+		params := make([]string, 0, len(columns))
+		for i, value := range p.after.Values {
+			name := fmt.Sprintf("cursor%d", i)
+			vars[name] = value
+			params = append(params, "@"+name)
+		}
+		vars["cursorID"] = p.after.ID
+		params = append(params, "@cursorID")
+
+		query = query.Where(clause.NamedExpr{
+			SQL:  "(" + strings.Join(columns, ", ") + ") " + comparison + " (" + strings.Join(params, ", ") + ")",
+			Vars: []interface{}{vars},
+		})
+	}
+	return query.Limit(p.Limit + 1)
+}
+
+// finishPage drops the extra row apply fetched and builds the pagination
+// envelope. key returns a row's ID and sort key values, in the order's key
+// order, for the next cursor.
+func finishPage[T any](p *pageRequest, rows []T, total *int64, key func(row *T) (uuid.UUID, []interface{})) ([]T, Pagination) {
+	pagination := Pagination{Limit: p.Limit, Page: p.Page, Total: total}
+	if total != nil {
+		pages := (*total + int64(p.Limit) - 1) / int64(p.Limit)
+		pagination.Pages = &pages
+	}
+
+	if len(rows) > p.Limit {
+		rows = rows[:p.Limit]
+		pagination.HasMore = true
+	}
+	if pagination.HasMore {
+		id, values := key(&rows[len(rows)-1])
+		pagination.NextCursor = encodeCursor(p.order, id, values)
+	}
+	return rows, pagination
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	order := listOrder{
+		Keys: []sortKey{
+			{Column: "restaurants.rating", Kind: floatKey},
+			{Column: "restaurants.review_count", Kind: intKey},
+			{Column: "restaurants.name", Kind: stringKey},
+			{Column: "restaurants.created_at", Kind: timeKey},
+		},
+		ID:   "restaurants.id",
+		Desc: true,
+	}
+	id := uuid.New()
+	created := time.Date(2026, 3, 14, 9, 26, 53, 589793000, time.FixedZone("CET", 3600))
+
+	cursor, err := decodeCursor(order, encodeCursor(order, id, []interface{}{4.5, int64(120), "Luigi's", created}))
+	if err != nil {
+		t.Fatalf("decodeCursor: %v", err)
+	}
+	if cursor.ID != id {
+		t.Errorf("ID = %s, want %s", cursor.ID, id)
+	}
+	want := []interface{}{4.5, int64(120), "Luigi's", created.UTC()}
+	if !reflect.DeepEqual(cursor.Values, want) {
+		t.Errorf("Values = %#v, want %#v", cursor.Values, want)
+	}
+}
+
+func TestDecodeCursorRejectsTampering(t *testing.T) {
+	order := newestFirst("orders")
+	raw := func(json string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(json))
+	}
+	id := uuid.New()
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "not a cursor!"},
+		{"not JSON", raw("orders.created_at desc")},
+		{"other direction", encodeCursor(listOrder{Keys: order.Keys, ID: order.ID}, id, []interface{}{time.Now()})},
+		{"other list", encodeCursor(newestFirst("reviews"), id, []interface{}{time.Now()})},
+		{"missing value", raw(`{"o":"orders.created_at desc","v":[],"id":"` + id.String() + `"}`)},
+		{"extra value", raw(`{"o":"orders.created_at desc","v":["2026-01-02T03:04:05Z","x"],"id":"` + id.String() + `"}`)},
+		{"number for a time", raw(`{"o":"orders.created_at desc","v":[12],"id":"` + id.String() + `"}`)},
+		{"malformed time", raw(`{"o":"orders.created_at desc","v":["yesterday"],"id":"` + id.String() + `"}`)},
+		{"malformed ID", raw(`{"o":"orders.created_at desc","v":["2026-01-02T03:04:05Z"],"id":"42"}`)},
+	}
+	for _, tt := range tests {
+		if _, err := decodeCursor(order, tt.cursor); err != errInvalidCursor {
+			t.Errorf("%s: err = %v, want errInvalidCursor", tt.name, err)
+		}
+	}
+
+	intOrder := listOrder{Keys: []sortKey{{Column: "menu_items.position", Kind: intKey}}, ID: "menu_items.id"}
+	if _, err := decodeCursor(intOrder, raw(`{"o":"menu_items.position asc","v":[1.5],"id":"`+id.String()+`"}`)); err != errInvalidCursor {
+		t.Errorf("fraction for an int: err = %v, want errInvalidCursor", err)
+	}
+}
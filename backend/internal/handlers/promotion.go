@@ -0,0 +1,694 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/middleware"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PromotionHandler struct {
+	db  *repository.Database
+	cfg *config.Config
+}
+
+type PromotionRequest struct {
+	Code                  string               `json:"code" binding:"required,min=3,max=32"`
+	Description           string               `json:"description"`
+	Type                  models.PromotionType `json:"type" binding:"required,oneof=percentage fixed free_delivery"`
+	Value                 float64              `json:"value" binding:"gte=0"`
+	MaxDiscount           *float64             `json:"maxDiscount,omitempty" binding:"omitempty,gt=0"`
+	MinOrderAmount        float64              `json:"minOrderAmount" binding:"gte=0"`
+	MaxRedemptions        *int                 `json:"maxRedemptions,omitempty" binding:"omitempty,min=1"`
+	MaxRedemptionsPerUser *int                 `json:"maxRedemptionsPerUser,omitempty" binding:"omitempty,min=1"`
+	StartsAt              *time.Time           `json:"startsAt,omitempty"`
+	EndsAt                *time.Time           `json:"endsAt,omitempty"`
+	FirstOrderOnly        bool                 `json:"firstOrderOnly"`
+	IsActive              *bool                `json:"isActive,omitempty"`
+	// RestaurantID scopes an admin-created code to one restaurant; owners'
+	// codes are always scoped to their own restaurant.
+	RestaurantID *uuid.UUID `json:"restaurantId,omitempty"`
+}
+
+type ValidatePromotionRequest struct {
+	Code         string    `json:"code" binding:"required"`
+	RestaurantID uuid.UUID `json:"restaurantId" binding:"required"`
+	Subtotal     float64   `json:"subtotal" binding:"gte=0"`
+	DeliveryFee  float64   `json:"deliveryFee" binding:"gte=0"`
+}
+
+type PromotionReport struct {
+	Redemptions       int64   `json:"redemptions"`
+	UniqueCustomers   int64   `json:"uniqueCustomers"`
+	TotalDiscount     float64 `json:"totalDiscount"`
+	TotalOrderValue   float64 `json:"totalOrderValue"`
+	VoidedRedemptions int64   `json:"voidedRedemptions"`
+}
+
+func NewPromotionHandler(db *repository.Database, cfg *config.Config) *PromotionHandler {
+	return &PromotionHandler{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// GetRestaurantPromotions godoc
+// @Summary List restaurant promo codes
+// @Description List promo codes of the current owner's restaurant
+// @Tags promotions
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/promotions [get]
+func (h *PromotionHandler) GetRestaurantPromotions(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	h.listPromotions(c, h.db.DB.Where("restaurant_id = ?", restaurant.ID))
+}
+
+// CreateRestaurantPromotion godoc
+// @Summary Create a restaurant promo code
+// @Description Create a promo code valid only at the current owner's restaurant
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param promotion body PromotionRequest true "Promo code"
+// @Success 201 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/promotions [post]
+func (h *PromotionHandler) CreateRestaurantPromotion(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	var req PromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+	req.RestaurantID = &restaurant.ID
+
+	h.createPromotion(c, &req)
+}
+
+// UpdateRestaurantPromotion godoc
+// @Summary Update a restaurant promo code
+// @Description Replace the settings of one of the current owner's promo codes
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Promotion ID"
+// @Param promotion body PromotionRequest true "Promo code"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/promotions/{id} [put]
+func (h *PromotionHandler) UpdateRestaurantPromotion(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	promotion, ok := h.findPromotion(c, &restaurant.ID)
+	if !ok {
+		return
+	}
+
+	var req PromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+	req.RestaurantID = &restaurant.ID
+
+	h.updatePromotion(c, promotion, &req)
+}
+
+// DeleteRestaurantPromotion godoc
+// @Summary Delete a restaurant promo code
+// @Description Delete a promo code that has never been redeemed. Redeemed codes can only be deactivated.
+// @Tags promotions
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/promotions/{id} [delete]
+func (h *PromotionHandler) DeleteRestaurantPromotion(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	promotion, ok := h.findPromotion(c, &restaurant.ID)
+	if !ok {
+		return
+	}
+
+	h.deletePromotion(c, promotion)
+}
+
+// GetRestaurantPromotionRedemptions godoc
+// @Summary Get redemptions of a restaurant promo code
+// @Description Redemption report for one of the current owner's promo codes
+// @Tags promotions
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Promotion ID"
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Page cursor: empty for the first page, then the previous page's nextCursor; replaces page"
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/promotions/{id}/redemptions [get]
+func (h *PromotionHandler) GetRestaurantPromotionRedemptions(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	promotion, ok := h.findPromotion(c, &restaurant.ID)
+	if !ok {
+		return
+	}
+
+	h.respondWithRedemptions(c, promotion)
+}
+
+// GetPromotions godoc
+// @Summary List all promo codes
+// @Description List platform-wide and restaurant promo codes
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param restaurantId query string false "Only codes of this restaurant"
+// @Param scope query string false "platform to list only platform-wide codes"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/promotions [get]
+func (h *PromotionHandler) GetPromotions(c *gin.Context) {
+	query := h.db.DB.Model(&models.Promotion{})
+
+	if c.Query("scope") == "platform" {
+		query = query.Where("restaurant_id IS NULL")
+	}
+	if value := c.Query("restaurantId"); value != "" {
+		restaurantID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Invalid restaurant ID",
+			})
+			return
+		}
+		query = query.Where("restaurant_id = ?", restaurantID)
+	}
+
+	h.listPromotions(c, query)
+}
+
+// CreatePromotion godoc
+// @Summary Create a promo code
+// @Description Create a platform-wide promo code, or one scoped to restaurantId
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param promotion body PromotionRequest true "Promo code"
+// @Success 201 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/promotions [post]
+func (h *PromotionHandler) CreatePromotion(c *gin.Context) {
+	var req PromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if req.RestaurantID != nil {
+		var count int64
+		h.db.DB.Model(&models.Restaurant{}).Where("id = ?", *req.RestaurantID).Count(&count)
+		if count == 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Restaurant not found",
+			})
+			return
+		}
+	}
+
+	h.createPromotion(c, &req)
+}
+
+// UpdatePromotion godoc
+// @Summary Update a promo code
+// @Description Replace the settings of any promo code
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Promotion ID"
+// @Param promotion body PromotionRequest true "Promo code"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/promotions/{id} [put]
+func (h *PromotionHandler) UpdatePromotion(c *gin.Context) {
+	promotion, ok := h.findPromotion(c, nil)
+	if !ok {
+		return
+	}
+
+	var req PromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	h.updatePromotion(c, promotion, &req)
+}
+
+// DeletePromotion godoc
+// @Summary Delete a promo code
+// @Description Delete a promo code that has never been redeemed. Redeemed codes can only be deactivated.
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/promotions/{id} [delete]
+func (h *PromotionHandler) DeletePromotion(c *gin.Context) {
+	promotion, ok := h.findPromotion(c, nil)
+	if !ok {
+		return
+	}
+
+	h.deletePromotion(c, promotion)
+}
+
+// GetPromotionRedemptions godoc
+// @Summary Get redemptions of a promo code
+// @Description Redemption report for any promo code
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Promotion ID"
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Page cursor: empty for the first page, then the previous page's nextCursor; replaces page"
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/promotions/{id}/redemptions [get]
+func (h *PromotionHandler) GetPromotionRedemptions(c *gin.Context) {
+	promotion, ok := h.findPromotion(c, nil)
+	if !ok {
+		return
+	}
+
+	h.respondWithRedemptions(c, promotion)
+}
+
+// ValidatePromotion godoc
+// @Summary Check a promo code
+// @Description Check whether a promo code applies to an order and preview the discount
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body ValidatePromotionRequest true "Code and order details"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /promotions/validate [post]
+func (h *PromotionHandler) ValidatePromotion(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	var req ValidatePromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Run the checks in a transaction that is always rolled back, so the
+	// promotion row lock is released straight away
+	tx := h.db.DB.Begin()
+	promotion, discount, err := services.ApplyPromotion(tx, req.Code, userID, req.RestaurantID, req.Subtotal, req.DeliveryFee)
+	tx.Rollback()
+	if err != nil {
+		if promoErr, ok := err.(*services.PromotionError); ok {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: promoErr.Message,
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to check promo code",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Promo code applies",
+		Data: gin.H{
+			"code":        promotion.Code,
+			"type":        promotion.Type,
+			"description": promotion.Description,
+			"discount":    discount,
+		},
+	})
+}
+
+func (h *PromotionHandler) listPromotions(c *gin.Context, query *gorm.DB) {
+	var promotions []models.Promotion
+	if err := query.Order("created_at DESC").Find(&promotions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch promotions",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Promotions retrieved successfully",
+		Data:    promotions,
+	})
+}
+
+func (h *PromotionHandler) createPromotion(c *gin.Context, req *PromotionRequest) {
+	userID, _ := middleware.GetCurrentUserID(c)
+
+	if message := validatePromotion(req); message != "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: message,
+		})
+		return
+	}
+
+	promotion := models.Promotion{CreatedByID: userID}
+	applyPromotionRequest(&promotion, req)
+
+	if h.codeTaken(promotion.Code, uuid.Nil) {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Message: "Promo code already exists",
+		})
+		return
+	}
+
+	// Select all columns so an inactive code isn't swapped for the column default
+	if err := h.db.DB.Select("*").Create(&promotion).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to create promotion",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Success: true,
+		Message: "Promotion created successfully",
+		Data:    promotion,
+	})
+}
+
+func (h *PromotionHandler) updatePromotion(c *gin.Context, promotion *models.Promotion, req *PromotionRequest) {
+	if message := validatePromotion(req); message != "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: message,
+		})
+		return
+	}
+
+	applyPromotionRequest(promotion, req)
+
+	if h.codeTaken(promotion.Code, promotion.ID) {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Message: "Promo code already exists",
+		})
+		return
+	}
+
+	if err := h.db.DB.Save(promotion).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to update promotion",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Promotion updated successfully",
+		Data:    promotion,
+	})
+}
+
+func (h *PromotionHandler) deletePromotion(c *gin.Context, promotion *models.Promotion) {
+	var redemptions int64
+	h.db.DB.Model(&models.PromotionRedemption{}).Where("promotion_id = ?", promotion.ID).Count(&redemptions)
+	if redemptions > 0 {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Message: "Promo code has been redeemed; deactivate it instead",
+		})
+		return
+	}
+
+	if err := h.db.DB.Delete(promotion).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to delete promotion",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Promotion deleted successfully",
+	})
+}
+
+func (h *PromotionHandler) respondWithRedemptions(c *gin.Context, promotion *models.Promotion) {
+	page, ok := parsePage(c, newestFirst("promotion_redemptions"), 20)
+	if !ok {
+		return
+	}
+
+	var report PromotionReport
+	h.db.DB.Model(&models.PromotionRedemption{}).
+		Select("COUNT(*) AS redemptions, COUNT(DISTINCT user_id) AS unique_customers, COALESCE(SUM(discount_amount), 0) AS total_discount, COALESCE(SUM(order_subtotal), 0) AS total_order_value").
+		Where("promotion_id = ? AND voided_at IS NULL", promotion.ID).
+		Scan(&report)
+	h.db.DB.Model(&models.PromotionRedemption{}).
+		Where("promotion_id = ? AND voided_at IS NOT NULL", promotion.ID).
+		Count(&report.VoidedRedemptions)
+
+	query := h.db.DB.Model(&models.PromotionRedemption{}).Where("promotion_id = ?", promotion.ID)
+	total, err := page.countTotal(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to count redemptions",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var redemptions []models.PromotionRedemption
+	if err := page.apply(query).Find(&redemptions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch redemptions",
+			Error:   err.Error(),
+		})
+		return
+	}
+	redemptions, pagination := finishPage(page, redemptions, total, func(redemption *models.PromotionRedemption) (uuid.UUID, []interface{}) {
+		return redemption.ID, []interface{}{redemption.CreatedAt}
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Redemptions retrieved successfully",
+		"data": gin.H{
+			"promotion":   promotion,
+			"summary":     report,
+			"redemptions": redemptions,
+			"pagination":  pagination,
+		},
+	})
+}
+
+// findPromotion loads the promotion in the :id param. When restaurantID is
+// set the promotion must belong to that restaurant.
+func (h *PromotionHandler) findPromotion(c *gin.Context, restaurantID *uuid.UUID) (*models.Promotion, bool) {
+	promotionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid promotion ID",
+		})
+		return nil, false
+	}
+
+	query := h.db.DB.Where("id = ?", promotionID)
+	if restaurantID != nil {
+		query = query.Where("restaurant_id = ?", *restaurantID)
+	}
+
+	var promotion models.Promotion
+	if err := query.First(&promotion).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Promotion not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch promotion",
+				Error:   err.Error(),
+			})
+		}
+		return nil, false
+	}
+
+	return &promotion, true
+}
+
+func (h *PromotionHandler) codeTaken(code string, exceptID uuid.UUID) bool {
+	var count int64
+	h.db.DB.Model(&models.Promotion{}).Where("code = ? AND id <> ?", code, exceptID).Count(&count)
+	return count > 0
+}
+
+func applyPromotionRequest(promotion *models.Promotion, req *PromotionRequest) {
+	promotion.RestaurantID = req.RestaurantID
+	promotion.Code = services.NormalizePromoCode(req.Code)
+	promotion.Description = req.Description
+	promotion.Type = req.Type
+	promotion.Value = req.Value
+	promotion.MaxDiscount = req.MaxDiscount
+	promotion.MinOrderAmount = req.MinOrderAmount
+	promotion.MaxRedemptions = req.MaxRedemptions
+	promotion.MaxRedemptionsPerUser = req.MaxRedemptionsPerUser
+	promotion.StartsAt = req.StartsAt
+	promotion.EndsAt = req.EndsAt
+	promotion.FirstOrderOnly = req.FirstOrderOnly
+	promotion.IsActive = true
+	if req.IsActive != nil {
+		promotion.IsActive = *req.IsActive
+	}
+}
+
+// validatePromotion returns a user-facing message when the request is invalid.
+func validatePromotion(req *PromotionRequest) string {
+	switch req.Type {
+	case models.PercentagePromotion:
+		if req.Value <= 0 || req.Value > 100 {
+			return "Percentage promotions need a value between 0 and 100"
+		}
+	case models.FixedPromotion:
+		if req.Value <= 0 {
+			return "Fixed promotions need a positive value"
+		}
+	}
+
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return "endsAt must be after startsAt"
+	}
+
+	return ""
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/middleware"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ReceiptHandler struct {
+	db  *repository.Database
+	cfg *config.Config
+}
+
+func NewReceiptHandler(db *repository.Database, cfg *config.Config) *ReceiptHandler {
+	return &ReceiptHandler{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// GetOrderInvoice godoc
+// @Summary Download order invoice
+// @Description Download the itemized invoice of one of the current user's orders as a PDF. The invoice number is issued the first time any party downloads it, once the restaurant has accepted the order.
+// @Tags receipts
+// @Produce application/pdf
+// @Security Bearer
+// @Param id path string true "Order ID"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /orders/{id}/invoice [get]
+func (h *ReceiptHandler) GetOrderInvoice(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	order, ok := h.loadOrder(c, h.db.DB.Where("user_id = ?", userID))
+	if !ok {
+		return
+	}
+	h.sendInvoice(c, order)
+}
+
+// GetRestaurantOrderInvoice godoc
+// @Summary Download restaurant order invoice
+// @Description Download the invoice of an order placed with the owner's restaurant as a PDF
+// @Tags receipts
+// @Produce application/pdf
+// @Security Bearer
+// @Param id path string true "Order ID"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/orders/{id}/invoice [get]
+func (h *ReceiptHandler) GetRestaurantOrderInvoice(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	order, ok := h.loadOrder(c, h.db.DB.Where("restaurant_id = ?", restaurant.ID))
+	if !ok {
+		return
+	}
+	h.sendInvoice(c, order)
+}
+
+// GetAdminOrderInvoice godoc
+// @Summary Download any order invoice
+// @Description Download the invoice of any order as a PDF (admin only)
+// @Tags admin
+// @Produce application/pdf
+// @Security Bearer
+// @Param id path string true "Order ID"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/orders/{id}/invoice [get]
+func (h *ReceiptHandler) GetAdminOrderInvoice(c *gin.Context) {
+	order, ok := h.loadOrder(c, h.db.DB)
+	if !ok {
+		return
+	}
+	h.sendInvoice(c, order)
+}
+
+// GetKitchenTicket godoc
+// @Summary Download kitchen ticket
+// @Description Download an order of the owner's restaurant as a raw ESC/POS print job for an 80mm thermal printer, listing items, options and notes without prices
+// @Tags kitchen
+// @Produce application/octet-stream
+// @Security Bearer
+// @Param id path string true "Order ID"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/orders/{id}/ticket [get]
+func (h *ReceiptHandler) GetKitchenTicket(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	order, ok := h.loadOrder(c, h.db.DB.Where("restaurant_id = ?", restaurant.ID))
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="ticket-%s.bin"`, order.ID.String()[:8]))
+	c.Data(http.StatusOK, "application/octet-stream", services.BuildKitchenTicket(order, time.Now()))
+}
+
+// loadOrder fetches the order named in the path from the orders query allows,
+// with everything a receipt prints. It writes the error response itself.
+func (h *ReceiptHandler) loadOrder(c *gin.Context, query *gorm.DB) (*models.Order, bool) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid order ID",
+		})
+		return nil, false
+	}
+
+	var order models.Order
+	if err := query.Preload("Restaurant").Preload("User").Preload("DeliveryAddress").
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Where("id = ?", orderID).
+		First(&order).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Order not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch order",
+				Error:   err.Error(),
+			})
+		}
+		return nil, false
+	}
+	return &order, true
+}
+
+func (h *ReceiptHandler) sendInvoice(c *gin.Context, order *models.Order) {
+	var invoice *models.Invoice
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		invoice, err = services.IssueInvoice(tx, order)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrOrderNotInvoiceable) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Orders can only be invoiced once accepted, and cancelled orders can't be invoiced",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to issue invoice",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, invoice.Number))
+	c.Data(http.StatusOK, "application/pdf", services.BuildInvoicePDF(order, invoice))
+}
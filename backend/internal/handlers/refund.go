@@ -0,0 +1,507 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/middleware"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RefundHandler struct {
+	db  *repository.Database
+	cfg *config.Config
+}
+
+type CreateRefundRequest struct {
+	Items  []services.RefundItemRequest `json:"items" binding:"required,min=1,dive"`
+	Reason string                       `json:"reason" binding:"required"`
+	Photos []string                     `json:"photos" binding:"max=5"`
+	Method models.RefundMethod          `json:"method"`
+}
+
+type ApproveRefundRequest struct {
+	Amount *float64            `json:"amount"`
+	Method models.RefundMethod `json:"method"`
+	Note   string              `json:"note"`
+}
+
+type DenyRefundRequest struct {
+	Note string `json:"note" binding:"required"`
+}
+
+func NewRefundHandler(db *repository.Database, cfg *config.Config) *RefundHandler {
+	return &RefundHandler{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// CreateRefund godoc
+// @Summary Request a refund
+// @Description Request a refund for items of a completed order, with a reason and optional photos (upload them with type=refund first)
+// @Tags refunds
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Order ID"
+// @Param Idempotency-Key header string false "Key that makes retries of this request safe"
+// @Param refund body CreateRefundRequest true "Refund request"
+// @Success 201 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /orders/{id}/refunds [post]
+func (h *RefundHandler) CreateRefund(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid order ID",
+		})
+		return
+	}
+
+	var req CreateRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "A reason is required",
+		})
+		return
+	}
+	for _, photo := range req.Photos {
+		if !strings.HasPrefix(photo, "/api/uploads/") {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Photos must be uploaded images",
+			})
+			return
+		}
+	}
+
+	var refund *models.Refund
+	err = h.db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		refund, err = services.RequestRefund(tx, h.cfg, userID, orderID, req.Items, req.Reason, req.Photos, req.Method)
+		return err
+	})
+	if err != nil {
+		respondWithRefundError(c, err, "Failed to request refund")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Success: true,
+		Message: "Refund requested successfully",
+		Data:    refund,
+	})
+}
+
+// GetOrderRefunds godoc
+// @Summary Get refunds for an order
+// @Description Get the refund history of one of the current user's orders
+// @Tags refunds
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Order ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /orders/{id}/refunds [get]
+func (h *RefundHandler) GetOrderRefunds(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid order ID",
+		})
+		return
+	}
+
+	var order models.Order
+	if err := h.db.DB.Select("id").Where("id = ? AND user_id = ?", orderID, userID).First(&order).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Order not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch order",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	var refunds []models.Refund
+	if err := h.db.DB.Preload("Items").Where("order_id = ?", order.ID).Order("created_at DESC").Find(&refunds).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch refunds",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Refunds retrieved successfully",
+		Data:    refunds,
+	})
+}
+
+// GetRestaurantRefunds godoc
+// @Summary Get restaurant refunds
+// @Description Get refund requests for the owner's restaurant
+// @Tags refunds
+// @Produce json
+// @Security Bearer
+// @Param status query string false "Filter by status (requested, approved, denied)"
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Page cursor: empty for the first page, then the previous page's nextCursor; replaces page"
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/refunds [get]
+func (h *RefundHandler) GetRestaurantRefunds(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	h.respondWithRefunds(c, h.db.DB.Model(&models.Refund{}).Where("restaurant_id = ?", restaurant.ID))
+}
+
+// ApproveRestaurantRefund godoc
+// @Summary Approve a refund
+// @Description Approve a refund request for the owner's restaurant, optionally for a lower amount or another payout method
+// @Tags refunds
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Refund ID"
+// @Param decision body ApproveRefundRequest false "Approval"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/refunds/{id}/approve [post]
+func (h *RefundHandler) ApproveRestaurantRefund(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	h.approve(c, &restaurant.ID)
+}
+
+// DenyRestaurantRefund godoc
+// @Summary Deny a refund
+// @Description Deny a refund request for the owner's restaurant with a note for the customer
+// @Tags refunds
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Refund ID"
+// @Param decision body DenyRefundRequest true "Denial"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/refunds/{id}/deny [post]
+func (h *RefundHandler) DenyRestaurantRefund(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	h.deny(c, &restaurant.ID)
+}
+
+// GetRefunds godoc
+// @Summary Get all refunds
+// @Description Get refund requests across restaurants (admin only)
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param status query string false "Filter by status (requested, approved, denied)"
+// @Param restaurantId query string false "Filter by restaurant"
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Page cursor: empty for the first page, then the previous page's nextCursor; replaces page"
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/refunds [get]
+func (h *RefundHandler) GetRefunds(c *gin.Context) {
+	query := h.db.DB.Model(&models.Refund{})
+	if restaurantIDStr := c.Query("restaurantId"); restaurantIDStr != "" {
+		restaurantID, err := uuid.Parse(restaurantIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Invalid restaurant ID",
+			})
+			return
+		}
+		query = query.Where("restaurant_id = ?", restaurantID)
+	}
+
+	h.respondWithRefunds(c, query)
+}
+
+// ApproveRefund godoc
+// @Summary Approve a refund
+// @Description Approve any refund request, optionally for a lower amount or another payout method (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Refund ID"
+// @Param decision body ApproveRefundRequest false "Approval"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/refunds/{id}/approve [post]
+func (h *RefundHandler) ApproveRefund(c *gin.Context) {
+	h.approve(c, nil)
+}
+
+// DenyRefund godoc
+// @Summary Deny a refund
+// @Description Deny any refund request with a note for the customer (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Refund ID"
+// @Param decision body DenyRefundRequest true "Denial"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/refunds/{id}/deny [post]
+func (h *RefundHandler) DenyRefund(c *gin.Context) {
+	h.deny(c, nil)
+}
+
+// approve decides a refund on behalf of the current user. A non-nil
+// restaurantID restricts it to refunds of that restaurant.
+func (h *RefundHandler) approve(c *gin.Context, restaurantID *uuid.UUID) {
+	deciderID, refundID, ok := h.decisionParams(c)
+	if !ok {
+		return
+	}
+
+	var req ApproveRefundRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Invalid request data",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	var refund *models.Refund
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		refund, err = services.ApproveRefund(tx, h.cfg, refundID, restaurantID, services.RefundDecision{
+			DeciderID: deciderID,
+			Amount:    req.Amount,
+			Method:    req.Method,
+			Note:      strings.TrimSpace(req.Note),
+		})
+		return err
+	})
+	if err != nil {
+		respondWithRefundError(c, err, "Failed to approve refund")
+		return
+	}
+
+	h.respondWithRefund(c, refund.ID, "Refund approved successfully")
+}
+
+func (h *RefundHandler) deny(c *gin.Context, restaurantID *uuid.UUID) {
+	deciderID, refundID, ok := h.decisionParams(c)
+	if !ok {
+		return
+	}
+
+	var req DenyRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var refund *models.Refund
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		refund, err = services.DenyRefund(tx, refundID, restaurantID, services.RefundDecision{
+			DeciderID: deciderID,
+			Note:      strings.TrimSpace(req.Note),
+		})
+		return err
+	})
+	if err != nil {
+		respondWithRefundError(c, err, "Failed to deny refund")
+		return
+	}
+
+	h.respondWithRefund(c, refund.ID, "Refund denied successfully")
+}
+
+func (h *RefundHandler) decisionParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	deciderID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	refundID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid refund ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return deciderID, refundID, true
+}
+
+func (h *RefundHandler) respondWithRefund(c *gin.Context, refundID uuid.UUID, message string) {
+	var refund models.Refund
+	if err := h.db.DB.Preload("Items").Where("id = ?", refundID).First(&refund).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to load refund",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: message,
+		Data:    refund,
+	})
+}
+
+func (h *RefundHandler) respondWithRefunds(c *gin.Context, query *gorm.DB) {
+	page, ok := parsePage(c, newestFirst("refunds"), 20)
+	if !ok {
+		return
+	}
+
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	total, err := page.countTotal(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to count refunds",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var refunds []models.Refund
+	if err := page.apply(query).Preload("Items").Find(&refunds).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch refunds",
+			Error:   err.Error(),
+		})
+		return
+	}
+	refunds, pagination := finishPage(page, refunds, total, func(refund *models.Refund) (uuid.UUID, []interface{}) {
+		return refund.ID, []interface{}{refund.CreatedAt}
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Refunds retrieved successfully",
+		"data": gin.H{
+			"refunds":    refunds,
+			"pagination": pagination,
+		},
+	})
+}
+
+func respondWithRefundError(c *gin.Context, err error, message string) {
+	if refundErr, ok := err.(*services.RefundError); ok {
+		status := http.StatusBadRequest
+		if strings.HasSuffix(refundErr.Message, "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, models.ErrorResponse{
+			Success: false,
+			Message: refundErr.Message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		Success: false,
+		Message: message,
+		Error:   err.Error(),
+	})
+}
@@ -1,14 +1,16 @@
 package handlers
 
 import (
-	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"restaurantapp/config"
 	"restaurantapp/internal/middleware"
 	"restaurantapp/internal/models"
 	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -31,6 +33,9 @@ type CreateRestaurantRequest struct {
 	DeliveryFee     float64 `json:"deliveryFee"`
 	MinDeliveryTime int     `json:"minDeliveryTime"`
 	MaxDeliveryTime int     `json:"maxDeliveryTime"`
+	OffersPickup    bool    `json:"offersPickup"`
+	OffersDineIn    bool    `json:"offersDineIn"`
+	TimeZone        string  `json:"timeZone" example:"Europe/London"`
 	Image           string  `json:"image"`
 }
 
@@ -46,7 +51,13 @@ type UpdateRestaurantRequest struct {
 	MinDeliveryTime *int     `json:"minDeliveryTime,omitempty"`
 	MaxDeliveryTime *int     `json:"maxDeliveryTime,omitempty"`
 	Image           *string  `json:"image,omitempty"`
+	OffersPickup    *bool    `json:"offersPickup,omitempty"`
+	OffersDineIn    *bool    `json:"offersDineIn,omitempty"`
 	IsOpen          *bool    `json:"isOpen,omitempty"`
+	TimeZone        *string  `json:"timeZone,omitempty"`
+	// StockResetTime is when daily stock resets, HH:MM in the time zone;
+	// empty turns the reset off
+	StockResetTime  *string  `json:"stockResetTime,omitempty"`
 }
 
 type RestaurantResponse struct {
@@ -64,11 +75,29 @@ type RestaurantResponse struct {
 	DeliveryFee     float64   `json:"deliveryFee"`
 	MinDeliveryTime int       `json:"minDeliveryTime"`
 	MaxDeliveryTime int       `json:"maxDeliveryTime"`
+	OffersPickup    bool      `json:"offersPickup"`
+	OffersDineIn    bool      `json:"offersDineIn"`
 	IsOpen          bool      `json:"isOpen"`
+	TimeZone        string    `json:"timeZone"`
+	StockResetTime  string    `json:"stockResetTime"`
 	IsActive        bool      `json:"isActive"`
 	Image           string    `json:"image"`
 	CreatedAt       string    `json:"createdAt"`
 	UpdatedAt       string    `json:"updatedAt"`
+
+	// Throttle is the current busy-mode state, on reads only
+	Throttle *services.Throttle `json:"throttle,omitempty"`
+
+	// MatchedDishes are the dishes matching the query, on search results only
+	MatchedDishes []services.MatchedDish `json:"matchedDishes,omitempty"`
+}
+
+// SetBusyModeRequest turns on busy mode. DurationMinutes 0 keeps it on until
+// it is turned off; MaxActiveOrders 0 doesn't cap orders.
+type SetBusyModeRequest struct {
+	ExtraPrepMinutes int `json:"extraPrepMinutes" binding:"gte=0,lte=240"`
+	MaxActiveOrders  int `json:"maxActiveOrders" binding:"gte=0"`
+	DurationMinutes  int `json:"durationMinutes" binding:"gte=0,lte=1440"`
 }
 
 func NewRestaurantHandler(db *repository.Database, cfg *config.Config) *RestaurantHandler {
@@ -113,6 +142,18 @@ func (h *RestaurantHandler) CreateRestaurant(c *gin.Context) {
 		return
 	}
 
+	// Menu schedules are read in the restaurant's time zone
+	if req.TimeZone == "" {
+		req.TimeZone = "UTC"
+	}
+	if !validTimeZone(req.TimeZone) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Unknown time zone",
+		})
+		return
+	}
+
 	// Check if user already has a restaurant
 	var existingRestaurant models.Restaurant
 	result := h.db.DB.Where("owner_id = ?", userID).First(&existingRestaurant)
@@ -137,6 +178,9 @@ func (h *RestaurantHandler) CreateRestaurant(c *gin.Context) {
 		DeliveryFee:     req.DeliveryFee,
 		MinDeliveryTime: req.MinDeliveryTime,
 		MaxDeliveryTime: req.MaxDeliveryTime,
+		OffersPickup:    req.OffersPickup,
+		OffersDineIn:    req.OffersDineIn,
+		TimeZone:        req.TimeZone,
 		Image:           req.Image,
 		IsOpen:          true,
 		IsActive:        true,
@@ -166,6 +210,7 @@ func (h *RestaurantHandler) CreateRestaurant(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Page cursor: empty for the first page, then the previous page's nextCursor; replaces page"
 // @Param limit query int false "Items per page" default(10)
 // @Param cuisine query string false "Filter by cuisine type"
 // @Param search query string false "Search in restaurant names"
@@ -173,21 +218,22 @@ func (h *RestaurantHandler) CreateRestaurant(c *gin.Context) {
 // @Failure 500 {object} map[string]interface{}
 // @Router /public/restaurants [get]
 func (h *RestaurantHandler) GetRestaurants(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	cuisine := c.Query("cuisine")
 	search := c.Query("search")
 
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 100 {
-		limit = 10
+	page, ok := parsePage(c, listOrder{
+		Keys: []sortKey{
+			{Column: "restaurants.rating", Kind: floatKey},
+			{Column: "restaurants.review_count", Kind: intKey},
+		},
+		ID:   "restaurants.id",
+		Desc: true,
+	}, 10)
+	if !ok {
+		return
 	}
 
-	offset := (page - 1) * limit
-
-	query := h.db.DB.Where("is_active = ?", true)
+	query := h.db.DB.Model(&models.Restaurant{}).Where("is_active = ?", true)
 
 	if cuisine != "" {
 		query = query.Where("LOWER(cuisine_type) = LOWER(?)", cuisine)
@@ -197,14 +243,20 @@ func (h *RestaurantHandler) GetRestaurants(c *gin.Context) {
 		query = query.Where("LOWER(name) ILIKE LOWER(?)", "%"+search+"%")
 	}
 
-	var restaurants []models.Restaurant
-	var total int64
-
 	// Get total count
-	query.Model(&models.Restaurant{}).Count(&total)
+	total, err := page.countTotal(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to count restaurants",
+			"error":   err.Error(),
+		})
+		return
+	}
 
 	// Get paginated results
-	if err := query.Offset(offset).Limit(limit).Order("rating DESC, review_count DESC").Find(&restaurants).Error; err != nil {
+	var restaurants []models.Restaurant
+	if err := page.apply(query).Find(&restaurants).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"message": "Failed to fetch restaurants",
@@ -212,10 +264,26 @@ func (h *RestaurantHandler) GetRestaurants(c *gin.Context) {
 		})
 		return
 	}
+	restaurants, pagination := finishPage(page, restaurants, total, func(r *models.Restaurant) (uuid.UUID, []interface{}) {
+		return r.ID, []interface{}{r.Rating, r.ReviewCount}
+	})
+
+	throttles, err := services.RestaurantThrottles(h.db.DB, restaurants)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch restaurant status",
+			"error":   err.Error(),
+		})
+		return
+	}
 
 	var responses []RestaurantResponse
 	for _, restaurant := range restaurants {
-		responses = append(responses, h.toRestaurantResponse(&restaurant))
+		response := h.toRestaurantResponse(&restaurant)
+		throttle := throttles[restaurant.ID]
+		response.Throttle = &throttle
+		responses = append(responses, response)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -223,12 +291,7 @@ func (h *RestaurantHandler) GetRestaurants(c *gin.Context) {
 		"message": "Restaurants retrieved successfully",
 		"data": gin.H{
 			"restaurants": responses,
-			"pagination": gin.H{
-				"page":  page,
-				"limit": limit,
-				"total": total,
-				"pages": (total + int64(limit) - 1) / int64(limit),
-			},
+			"pagination":  pagination,
 		},
 	})
 }
@@ -273,6 +336,17 @@ func (h *RestaurantHandler) GetRestaurant(c *gin.Context) {
 	}
 
 	response := h.toRestaurantResponse(&restaurant)
+	throttle, err := services.RestaurantThrottle(h.db.DB, &restaurant)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch restaurant status",
+			"error":   err.Error(),
+		})
+		return
+	}
+	response.Throttle = &throttle
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Restaurant retrieved successfully",
@@ -386,8 +460,38 @@ func (h *RestaurantHandler) UpdateRestaurant(c *gin.Context) {
 	if req.Image != nil {
 		restaurant.Image = *req.Image
 	}
+	if req.OffersPickup != nil {
+		restaurant.OffersPickup = *req.OffersPickup
+	}
+	if req.OffersDineIn != nil {
+		restaurant.OffersDineIn = *req.OffersDineIn
+	}
 	if req.IsOpen != nil {
 		restaurant.IsOpen = *req.IsOpen
+		// Opening again starts a new count of missed orders
+		if restaurant.IsOpen {
+			restaurant.MissedOrders = 0
+		}
+	}
+	if req.TimeZone != nil {
+		if !validTimeZone(*req.TimeZone) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Unknown time zone",
+			})
+			return
+		}
+		restaurant.TimeZone = *req.TimeZone
+	}
+	if req.StockResetTime != nil {
+		if _, err := time.Parse("15:04", *req.StockResetTime); *req.StockResetTime != "" && err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Stock reset time must be HH:MM",
+			})
+			return
+		}
+		restaurant.StockResetTime = *req.StockResetTime
 	}
 
 	if err := h.db.DB.Save(&restaurant).Error; err != nil {
@@ -446,6 +550,17 @@ func (h *RestaurantHandler) GetMyRestaurant(c *gin.Context) {
 	}
 
 	response := h.toRestaurantResponse(&restaurant)
+	throttle, err := services.RestaurantThrottle(h.db.DB, &restaurant)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch restaurant status",
+			"error":   err.Error(),
+		})
+		return
+	}
+	response.Throttle = &throttle
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Restaurant retrieved successfully",
@@ -455,46 +570,38 @@ func (h *RestaurantHandler) GetMyRestaurant(c *gin.Context) {
 
 // SearchRestaurants godoc
 // @Summary Search restaurants with advanced filters
-// @Description Search restaurants with various filters like cuisine, price range, rating, etc.
+// @Description Search restaurants with various filters like cuisine, price range, rating, etc. The response counts the results each filter option would give (facets), each counted with the other filters applied. A query matches restaurant names, cuisines and descriptions and the names and descriptions of their dishes, tolerating typos in names; each result lists its matching dishes, and the response suggests close names, cuisines and dishes.
 // @Tags restaurants
 // @Accept json
 // @Produce json
-// @Param q query string false "Search query (name or cuisine)"
-// @Param cuisine query string false "Cuisine type filter"
+// @Param q query string false "Search query (restaurant, cuisine or dish)"
+// @Param cuisine query []string false "Cuisine type filters, repeated or comma-separated; matches any" collectionFormat(multi)
 // @Param minRating query number false "Minimum rating (0-5)"
 // @Param maxPrice query number false "Maximum price range (1-4)"
 // @Param deliveryFee query number false "Maximum delivery fee"
 // @Param isOpen query bool false "Filter by open status"
-// @Param sortBy query string false "Sort by: rating, delivery_fee, delivery_time" Enums(rating, delivery_fee, delivery_time)
+// @Param dietary query []string false "Dietary filters, repeated or comma-separated; restaurants need a dish suiting all of them" collectionFormat(multi) Enums(dairy_free, egg_free, fish_free, gluten_free, halal, nut_free, shellfish_free, soy_free, vegan, vegetarian)
+// @Param sortBy query string false "Sort by: relevance (default with a query), rating (default without), delivery_fee, delivery_time" Enums(relevance, rating, delivery_fee, delivery_time)
 // @Param sortOrder query string false "Sort order: asc, desc" Enums(asc, desc)
 // @Param page query int false "Page number (default: 1)"
+// @Param cursor query string false "Page cursor: empty for the first page, then the previous page's nextCursor; replaces page"
 // @Param limit query int false "Items per page (default: 10)"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /restaurants/search [get]
 func (h *RestaurantHandler) SearchRestaurants(c *gin.Context) {
-	query := c.DefaultQuery("q", "")
-	cuisine := c.DefaultQuery("cuisine", "")
+	query := services.NormalizeSearchQuery(c.Query("q"))
+	cuisines := queryList(c, "cuisine")
+	dietary := queryList(c, "dietary")
 	minRating := c.DefaultQuery("minRating", "0")
 	maxPrice := c.DefaultQuery("maxPrice", "4")
 	deliveryFee := c.DefaultQuery("deliveryFee", "999")
 	isOpenStr := c.DefaultQuery("isOpen", "")
-	sortBy := c.DefaultQuery("sortBy", "rating")
+	sortBy := c.DefaultQuery("sortBy", "")
 	sortOrder := c.DefaultQuery("sortOrder", "desc")
-	pageStr := c.DefaultQuery("page", "1")
-	limitStr := c.DefaultQuery("limit", "10")
 
 	// Parse query parameters
-	page := 1
-	if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-		page = p
-	}
-
-	limit := 10
-	if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-		limit = l
-	}
 
 	minRat := 0.0
 	if r, err := strconv.ParseFloat(minRating, 64); err == nil && r >= 0 && r <= 5 {
@@ -511,67 +618,97 @@ func (h *RestaurantHandler) SearchRestaurants(c *gin.Context) {
 		maxDeliveryFee = f
 	}
 
-	// Build the query
-	dbQuery := h.db.DB.Model(&models.Restaurant{}).Where("is_active = ?", true)
-
-	// Text search
-	if query != "" {
-		searchTerm := "%" + query + "%"
-		dbQuery = dbQuery.Where("name ILIKE ? OR cuisine_type ILIKE ? OR description ILIKE ?", 
-			searchTerm, searchTerm, searchTerm)
-	}
-
-	// Cuisine filter
-	if cuisine != "" {
-		dbQuery = dbQuery.Where("cuisine_type ILIKE ?", "%"+cuisine+"%")
+	for _, name := range dietary {
+		if !services.IsValidDietaryFilter(name) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Unknown dietary filter " + name + "; use one of " + strings.Join(services.DietaryFilters(), ", "),
+			})
+			return
+		}
 	}
 
-	// Rating filter
-	if minRat > 0 {
-		dbQuery = dbQuery.Where("rating >= ?", minRat)
+	filters := services.RestaurantFilters{
+		Cuisines:  cuisines,
+		MinRating: minRat,
+		Dietary:   dietary,
 	}
-
-	// Price range filter
 	if maxPr < 4 {
-		dbQuery = dbQuery.Where("price_range <= ?", maxPr)
+		filters.MaxPrice = maxPr
 	}
-
-	// Delivery fee filter
 	if maxDeliveryFee < 999 {
-		dbQuery = dbQuery.Where("delivery_fee <= ?", maxDeliveryFee)
+		filters.MaxDeliveryFee = &maxDeliveryFee
 	}
-
-	// Open status filter
 	if isOpenStr != "" {
 		if isOpen, err := strconv.ParseBool(isOpenStr); err == nil {
-			dbQuery = dbQuery.Where("is_open = ?", isOpen)
+			filters.IsOpen = &isOpen
 		}
 	}
 
-	// Sorting
-	validSortFields := map[string]string{
-		"rating":        "rating",
-		"delivery_fee":  "delivery_fee",
-		"delivery_time": "min_delivery_time",
-		"name":          "name",
-		"created_at":    "created_at",
+	// Active restaurants matching the text query, before any other filter
+	base := func() *gorm.DB {
+		db := h.db.DB.Model(&models.Restaurant{}).Where("is_active = ?", true)
+		if query != "" {
+			db = services.MatchRestaurants(db, query)
+		}
+		return db
 	}
 
-	sortField, exists := validSortFields[sortBy]
-	if !exists {
-		sortField = "rating"
+	// Build the query
+	dbQuery := filters.Apply(base(), "")
+
+	// Sorting, with the value each sort key takes from a restaurant for the
+	// next page's cursor
+	validSortFields := map[string]struct {
+		key   sortKey
+		value func(r *models.Restaurant) interface{}
+	}{
+		"rating":        {sortKey{Column: "restaurants.rating", Kind: floatKey}, func(r *models.Restaurant) interface{} { return r.Rating }},
+		"delivery_fee":  {sortKey{Column: "restaurants.delivery_fee", Kind: floatKey}, func(r *models.Restaurant) interface{} { return r.DeliveryFee }},
+		"delivery_time": {sortKey{Column: "restaurants.min_delivery_time", Kind: intKey}, func(r *models.Restaurant) interface{} { return r.MinDeliveryTime }},
+		"name":          {sortKey{Column: "restaurants.name", Kind: stringKey}, func(r *models.Restaurant) interface{} { return r.Name }},
+		"created_at":    {sortKey{Column: "restaurants.created_at", Kind: timeKey}, func(r *models.Restaurant) interface{} { return r.CreatedAt }},
+	}
+
+	if sortBy == "" {
+		sortBy = "rating"
+		if query != "" {
+			sortBy = "relevance"
+		}
 	}
 
 	if sortOrder != "asc" && sortOrder != "desc" {
 		sortOrder = "desc"
 	}
 
-	dbQuery = dbQuery.Order(sortField + " " + sortOrder)
+	// Relevance needs a query to rank by; without one it falls back to rating.
+	// Ties in relevance go to the better rated.
+	relevance := sortBy == "relevance" && query != ""
+	sortField, exists := validSortFields[sortBy]
+	if !exists {
+		sortField = validSortFields["rating"]
+	}
+	order := listOrder{Keys: []sortKey{sortField.key}, ID: "restaurants.id", Desc: sortOrder == "desc"}
+	if relevance {
+		order = listOrder{
+			Keys: []sortKey{
+				{Column: services.RelevanceRank, Kind: floatKey},
+				{Column: "restaurants.rating", Kind: floatKey},
+			},
+			ID:   "restaurants.id",
+			Desc: true,
+			Vars: map[string]interface{}{"q": query},
+		}
+	}
+
+	page, ok := parsePage(c, order, 10)
+	if !ok {
+		return
+	}
 
 	// Get total count for pagination
-	var total int64
-	countQuery := dbQuery
-	if err := countQuery.Count(&total).Error; err != nil {
+	total, err := page.countTotal(dbQuery)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"message": "Failed to count restaurants",
@@ -581,11 +718,8 @@ func (h *RestaurantHandler) SearchRestaurants(c *gin.Context) {
 	}
 
 	// Apply pagination
-	offset := (page - 1) * limit
-	dbQuery = dbQuery.Offset(offset).Limit(limit)
-
 	var restaurants []models.Restaurant
-	if err := dbQuery.Find(&restaurants).Error; err != nil {
+	if err := page.apply(dbQuery).Find(&restaurants).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"message": "Failed to fetch restaurants",
@@ -594,20 +728,78 @@ func (h *RestaurantHandler) SearchRestaurants(c *gin.Context) {
 		return
 	}
 
-	// Convert to response format
-	var restaurantResponses []RestaurantResponse
-	for _, restaurant := range restaurants {
-		restaurantResponses = append(restaurantResponses, h.toRestaurantResponse(&restaurant))
+	var scores map[uuid.UUID]float64
+	if relevance && len(restaurants) > page.Limit {
+		last := restaurants[page.Limit-1]
+		if scores, err = services.RelevanceScores(h.db.DB, []uuid.UUID{last.ID}, query); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "Failed to rank restaurants",
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+	restaurants, pagination := finishPage(page, restaurants, total, func(r *models.Restaurant) (uuid.UUID, []interface{}) {
+		if relevance {
+			return r.ID, []interface{}{scores[r.ID], r.Rating}
+		}
+		return r.ID, []interface{}{sortField.value(r)}
+	})
+
+	throttles, err := services.RestaurantThrottles(h.db.DB, restaurants)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch restaurant status",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	restaurantIDs := make([]uuid.UUID, len(restaurants))
+	for i, restaurant := range restaurants {
+		restaurantIDs[i] = restaurant.ID
+	}
+	dishes, err := services.MatchedDishes(h.db.DB, restaurantIDs, query, 3)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch matching dishes",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	facets, err := services.CountSearchFacets(base, filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to count search facets",
+			"error":   err.Error(),
+		})
+		return
 	}
 
-	// Calculate pagination info
-	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	suggestions, err := services.SearchSuggestions(h.db.DB, query, 5)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch suggestions",
+			"error":   err.Error(),
+		})
+		return
+	}
 
-	pagination := gin.H{
-		"page":   page,
-		"limit":  limit,
-		"total":  total,
-		"pages":  totalPages,
+	// Convert to response format, with the current busy-mode state and the
+	// dishes that matched
+	var restaurantResponses []RestaurantResponse
+	for _, restaurant := range restaurants {
+		response := h.toRestaurantResponse(&restaurant)
+		throttle := throttles[restaurant.ID]
+		response.Throttle = &throttle
+		response.MatchedDishes = dishes[restaurant.ID]
+		restaurantResponses = append(restaurantResponses, response)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -616,9 +808,12 @@ func (h *RestaurantHandler) SearchRestaurants(c *gin.Context) {
 		"data": gin.H{
 			"restaurants": restaurantResponses,
 			"pagination":  pagination,
+			"suggestions": suggestions,
+			"facets":      facets,
 			"filters": gin.H{
 				"query":           query,
-				"cuisine":         cuisine,
+				"cuisine":         cuisines,
+				"dietary":         dietary,
 				"minRating":       minRat,
 				"maxPrice":        maxPr,
 				"maxDeliveryFee":  maxDeliveryFee,
@@ -630,6 +825,143 @@ func (h *RestaurantHandler) SearchRestaurants(c *gin.Context) {
 	})
 }
 
+// GetSearchSuggestions godoc
+// @Summary Get search suggestions
+// @Description Restaurant names, cuisines and dish names close to a partial or misspelled query, best match first
+// @Tags restaurants
+// @Produce json
+// @Param q query string true "Search query"
+// @Param limit query int false "Number of suggestions (default: 5, max: 20)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /restaurants/suggestions [get]
+func (h *RestaurantHandler) GetSearchSuggestions(c *gin.Context) {
+	query := services.NormalizeSearchQuery(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Search query is required",
+		})
+		return
+	}
+
+	limit := 5
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 20 {
+		limit = l
+	}
+
+	suggestions, err := services.SearchSuggestions(h.db.DB, query, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch suggestions",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Suggestions retrieved successfully",
+		"data": gin.H{
+			"query":       query,
+			"suggestions": suggestions,
+		},
+	})
+}
+
+// SetBusyMode godoc
+// @Summary Turn on busy mode
+// @Description Add extra preparation minutes to estimated times and optionally cap the orders in the kitchen, until turned off or for a number of minutes
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param busy body SetBusyModeRequest true "Busy mode settings"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /restaurant/busy [put]
+func (h *RestaurantHandler) SetBusyMode(c *gin.Context) {
+	var req SetBusyModeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	var busyUntil *time.Time
+	if req.DurationMinutes > 0 {
+		until := time.Now().Add(time.Duration(req.DurationMinutes) * time.Minute)
+		busyUntil = &until
+	}
+
+	h.updateBusyMode(c, map[string]interface{}{
+		"busy_mode":               true,
+		"busy_extra_prep_minutes": req.ExtraPrepMinutes,
+		"busy_max_active_orders":  req.MaxActiveOrders,
+		"busy_until":              busyUntil,
+	}, "Busy mode turned on")
+}
+
+// EndBusyMode godoc
+// @Summary Turn off busy mode
+// @Description Go back to normal preparation times and stop capping orders
+// @Tags restaurants
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /restaurant/busy [delete]
+func (h *RestaurantHandler) EndBusyMode(c *gin.Context) {
+	h.updateBusyMode(c, map[string]interface{}{
+		"busy_mode":               false,
+		"busy_extra_prep_minutes": 0,
+		"busy_max_active_orders":  0,
+		"busy_until":              nil,
+	}, "Busy mode turned off")
+}
+
+func (h *RestaurantHandler) updateBusyMode(c *gin.Context, updates map[string]interface{}, message string) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	if err := h.db.DB.Model(restaurant).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to update busy mode",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	throttle, err := services.RestaurantThrottle(h.db.DB, restaurant)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch restaurant status",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": message,
+		"data":    throttle,
+	})
+}
+
 func (h *RestaurantHandler) toRestaurantResponse(restaurant *models.Restaurant) RestaurantResponse {
 	return RestaurantResponse{
 		ID:              restaurant.ID,
@@ -646,10 +978,24 @@ func (h *RestaurantHandler) toRestaurantResponse(restaurant *models.Restaurant)
 		DeliveryFee:     restaurant.DeliveryFee,
 		MinDeliveryTime: restaurant.MinDeliveryTime,
 		MaxDeliveryTime: restaurant.MaxDeliveryTime,
+		OffersPickup:    restaurant.OffersPickup,
+		OffersDineIn:    restaurant.OffersDineIn,
 		IsOpen:          restaurant.IsOpen,
+		TimeZone:        restaurant.TimeZone,
+		StockResetTime:  restaurant.StockResetTime,
 		IsActive:        restaurant.IsActive,
 		Image:           restaurant.Image,
 		CreatedAt:       restaurant.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:       restaurant.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
-}
\ No newline at end of file
+}
+
+// validTimeZone reports whether name is an IANA time zone like
+// "Europe/London". "Local" would follow the server, so it isn't allowed.
+func validTimeZone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
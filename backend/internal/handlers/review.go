@@ -2,12 +2,12 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
 
 	"restaurantapp/config"
 	"restaurantapp/internal/middleware"
 	"restaurantapp/internal/models"
 	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -55,6 +55,7 @@ func NewReviewHandler(db *repository.Database, cfg *config.Config) *ReviewHandle
 // @Security Bearer
 // @Param review body CreateReviewRequest true "Review data"
 // @Param restaurantId path string true "Restaurant ID"
+// @Param Idempotency-Key header string false "Key that makes retries of this request safe"
 // @Success 201 {object} models.SuccessResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
@@ -117,11 +118,11 @@ func (h *ReviewHandler) CreateReview(c *gin.Context) {
 		return
 	}
 
-	// Check if order is delivered
-	if order.Status != "delivered" {
+	// Check if order is completed
+	if !order.IsCompleted() {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
-			Error:   "Can only review delivered orders",
+			Error:   "Can only review completed orders",
 		})
 		return
 	}
@@ -147,7 +148,14 @@ func (h *ReviewHandler) CreateReview(c *gin.Context) {
 		Photos:       req.Photos,
 	}
 
-	if err := h.db.DB.Create(&review).Error; err != nil {
+	// The restaurant rating is recalculated by the outbox consumer
+	err = h.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&review).Error; err != nil {
+			return err
+		}
+		return services.PublishEvent(tx, "review", review.ID, models.ReviewCreatedEvent, models.NewReviewEventPayload(&review))
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to create review",
@@ -155,9 +163,6 @@ func (h *ReviewHandler) CreateReview(c *gin.Context) {
 		return
 	}
 
-	// Update restaurant rating
-	h.updateRestaurantRating(restaurantID)
-
 	c.JSON(http.StatusCreated, models.SuccessResponse{
 		Success: true,
 		Message: "Review created successfully",
@@ -173,6 +178,7 @@ func (h *ReviewHandler) CreateReview(c *gin.Context) {
 // @Produce json
 // @Param restaurantId path string true "Restaurant ID"
 // @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Page cursor: empty for the first page, then the previous page's nextCursor; replaces page"
 // @Param limit query int false "Items per page" default(10)
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} models.ErrorResponse
@@ -189,30 +195,25 @@ func (h *ReviewHandler) GetRestaurantReviews(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 100 {
-		limit = 10
+	page, ok := parsePage(c, newestFirst("reviews"), 10)
+	if !ok {
+		return
 	}
 
-	offset := (page - 1) * limit
-
-	var reviews []models.Review
-	var total int64
-
 	// Get total count
-	h.db.DB.Model(&models.Review{}).Where("restaurant_id = ?", restaurantID).Count(&total)
+	total, err := page.countTotal(h.db.DB.Model(&models.Review{}).Where("restaurant_id = ?", restaurantID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Failed to count reviews",
+		})
+		return
+	}
 
 	// Get paginated reviews with user information
-	if err := h.db.DB.Where("restaurant_id = ?", restaurantID).
+	var reviews []models.Review
+	if err := page.apply(h.db.DB.Where("restaurant_id = ?", restaurantID)).
 		Preload("User").
-		Order("created_at DESC").
-		Offset(offset).
-		Limit(limit).
 		Find(&reviews).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
@@ -220,6 +221,9 @@ func (h *ReviewHandler) GetRestaurantReviews(c *gin.Context) {
 		})
 		return
 	}
+	reviews, pagination := finishPage(page, reviews, total, func(review *models.Review) (uuid.UUID, []interface{}) {
+		return review.ID, []interface{}{review.CreatedAt}
+	})
 
 	var responses []ReviewResponse
 	for _, review := range reviews {
@@ -230,13 +234,8 @@ func (h *ReviewHandler) GetRestaurantReviews(c *gin.Context) {
 		"success": true,
 		"message": "Reviews retrieved successfully",
 		"data": gin.H{
-			"reviews": responses,
-			"pagination": gin.H{
-				"page":  page,
-				"limit": limit,
-				"total": total,
-				"pages": (total + int64(limit) - 1) / int64(limit),
-			},
+			"reviews":    responses,
+			"pagination": pagination,
 		},
 	})
 }
@@ -360,7 +359,13 @@ func (h *ReviewHandler) UpdateReview(c *gin.Context) {
 	review.Comment = req.Comment
 	review.Photos = req.Photos
 
-	if err := h.db.DB.Save(&review).Error; err != nil {
+	err = h.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&review).Error; err != nil {
+			return err
+		}
+		return services.PublishEvent(tx, "review", review.ID, models.ReviewUpdatedEvent, models.NewReviewEventPayload(&review))
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to update review",
@@ -368,9 +373,6 @@ func (h *ReviewHandler) UpdateReview(c *gin.Context) {
 		return
 	}
 
-	// Update restaurant rating
-	h.updateRestaurantRating(review.RestaurantID)
-
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Success: true,
 		Message: "Review updated successfully",
@@ -439,10 +441,14 @@ func (h *ReviewHandler) DeleteReview(c *gin.Context) {
 		return
 	}
 
-	restaurantID := review.RestaurantID
-
 	// Delete review
-	if err := h.db.DB.Delete(&review).Error; err != nil {
+	err = h.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&review).Error; err != nil {
+			return err
+		}
+		return services.PublishEvent(tx, "review", review.ID, models.ReviewDeletedEvent, models.NewReviewEventPayload(&review))
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error:   "Failed to delete review",
@@ -450,28 +456,12 @@ func (h *ReviewHandler) DeleteReview(c *gin.Context) {
 		return
 	}
 
-	// Update restaurant rating
-	h.updateRestaurantRating(restaurantID)
-
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Success: true,
 		Message: "Review deleted successfully",
 	})
 }
 
-func (h *ReviewHandler) updateRestaurantRating(restaurantID uuid.UUID) {
-	var avgRating float64
-	var reviewCount int64
-
-	h.db.DB.Model(&models.Review{}).Where("restaurant_id = ?", restaurantID).Count(&reviewCount)
-	h.db.DB.Model(&models.Review{}).Where("restaurant_id = ?", restaurantID).Select("AVG(rating)").Scan(&avgRating)
-
-	h.db.DB.Model(&models.Restaurant{}).Where("id = ?", restaurantID).Updates(map[string]interface{}{
-		"rating":       avgRating,
-		"review_count": reviewCount,
-	})
-}
-
 func (h *ReviewHandler) toReviewResponse(review *models.Review) ReviewResponse {
 	response := ReviewResponse{
 		ID:           review.ID,
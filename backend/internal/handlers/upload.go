@@ -37,6 +37,7 @@ const (
 	ImagesDir     = "images"
 	RestaurantDir = "restaurants"
 	MenuDir       = "menu"
+	RefundDir     = "refunds"
 )
 
 var AllowedImageTypes = map[string]bool{
@@ -62,7 +63,7 @@ func NewUploadHandler(db *repository.Database, cfg *config.Config) *UploadHandle
 // @Produce json
 // @Security Bearer
 // @Param file formData file true "Image file to upload"
-// @Param type formData string true "Upload type" Enums(restaurant, menu)
+// @Param type formData string true "Upload type" Enums(restaurant, menu, refund)
 // @Success 200 {object} models.SuccessResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
@@ -79,21 +80,21 @@ func (h *UploadHandler) UploadImage(c *gin.Context) {
 		return
 	}
 
-	// Get upload type (restaurant or menu)
+	// Get upload type (restaurant, menu or refund)
 	uploadType := c.PostForm("type")
 	if uploadType == "" {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
-			Error:   "Upload type is required (restaurant or menu)",
+			Error:   "Upload type is required (restaurant, menu or refund)",
 		})
 		return
 	}
 
 	// Validate upload type
-	if uploadType != "restaurant" && uploadType != "menu" {
+	if uploadType != "restaurant" && uploadType != "menu" && uploadType != "refund" {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
-			Error:   "Invalid upload type. Must be 'restaurant' or 'menu'",
+			Error:   "Invalid upload type. Must be 'restaurant', 'menu' or 'refund'",
 		})
 		return
 	}
@@ -155,6 +156,8 @@ func (h *UploadHandler) UploadImage(c *gin.Context) {
 		subDir = RestaurantDir
 	case "menu":
 		subDir = MenuDir
+	case "refund":
+		subDir = RefundDir
 	}
 
 	uploadPath := filepath.Join(UploadDir, ImagesDir, subDir)
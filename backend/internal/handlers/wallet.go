@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/middleware"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WalletHandler struct {
+	db  *repository.Database
+	cfg *config.Config
+}
+
+type WalletAdjustmentRequest struct {
+	Points int    `json:"points" binding:"required,ne=0"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+func NewWalletHandler(db *repository.Database, cfg *config.Config) *WalletHandler {
+	return &WalletHandler{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// GetMyWallet godoc
+// @Summary Get loyalty wallet
+// @Description Get the current user's points balance and ledger
+// @Tags wallet
+// @Produce json
+// @Security Bearer
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Page cursor: empty for the first page, then the previous page's nextCursor; replaces page"
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users/me/wallet [get]
+func (h *WalletHandler) GetMyWallet(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	h.respondWithWallet(c, userID)
+}
+
+// GetUserWallet godoc
+// @Summary Get a user's loyalty wallet
+// @Description Get a user's points balance and ledger (admin only)
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param userId path string true "User ID"
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Page cursor: empty for the first page, then the previous page's nextCursor; replaces page"
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users/{userId}/wallet [get]
+func (h *WalletHandler) GetUserWallet(c *gin.Context) {
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	h.respondWithWallet(c, user.ID)
+}
+
+// AdjustUserWallet godoc
+// @Summary Adjust a user's loyalty points
+// @Description Credit (positive points) or debit (negative points) a user's wallet with a reason (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId path string true "User ID"
+// @Param adjustment body WalletAdjustmentRequest true "Adjustment"
+// @Success 201 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users/{userId}/wallet/adjustments [post]
+func (h *WalletHandler) AdjustUserWallet(c *gin.Context) {
+	adminID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	var req WalletAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "A reason is required",
+		})
+		return
+	}
+
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	var entry *models.WalletEntry
+	var balance int
+	err := h.db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if entry, err = services.AdjustWallet(tx, user.ID, adminID, req.Points, req.Reason); err != nil {
+			return err
+		}
+		balance, err = services.WalletBalance(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		if walletErr, ok := err.(*services.WalletError); ok {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: walletErr.Message,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to adjust wallet",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Success: true,
+		Message: "Wallet adjusted successfully",
+		Data: gin.H{
+			"entry":   entry,
+			"balance": balance,
+			"value":   services.PointsValue(&h.cfg.Loyalty, balance),
+		},
+	})
+}
+
+func (h *WalletHandler) findUser(c *gin.Context) (*models.User, bool) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid user ID",
+		})
+		return nil, false
+	}
+
+	var user models.User
+	if err := h.db.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "User not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch user",
+				Error:   err.Error(),
+			})
+		}
+		return nil, false
+	}
+
+	return &user, true
+}
+
+func (h *WalletHandler) respondWithWallet(c *gin.Context, userID uuid.UUID) {
+	page, ok := parsePage(c, newestFirst("wallet_entries"), 20)
+	if !ok {
+		return
+	}
+
+	balance, err := services.WalletBalance(h.db.DB, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch wallet balance",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	query := h.db.DB.Model(&models.WalletEntry{}).Where("user_id = ?", userID)
+
+	total, err := page.countTotal(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to count wallet entries",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var entries []models.WalletEntry
+	if err := page.apply(query).Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch wallet entries",
+			Error:   err.Error(),
+		})
+		return
+	}
+	entries, pagination := finishPage(page, entries, total, func(entry *models.WalletEntry) (uuid.UUID, []interface{}) {
+		return entry.ID, []interface{}{entry.CreatedAt}
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Wallet retrieved successfully",
+		"data": gin.H{
+			"balance":    balance,
+			"value":      services.PointsValue(&h.cfg.Loyalty, balance),
+			"entries":    entries,
+			"pagination": pagination,
+		},
+	})
+}
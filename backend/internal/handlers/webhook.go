@@ -0,0 +1,537 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+	"restaurantapp/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WebhookHandler struct {
+	db       *repository.Database
+	cfg      *config.Config
+	webhooks *services.WebhookService
+}
+
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	EventTypes  []string `json:"eventTypes" binding:"required,min=1"`
+	Description string   `json:"description"`
+}
+
+type UpdateWebhookRequest struct {
+	URL          *string   `json:"url,omitempty"`
+	EventTypes   *[]string `json:"eventTypes,omitempty"`
+	Description  *string   `json:"description,omitempty"`
+	IsActive     *bool     `json:"isActive,omitempty"`
+	RotateSecret bool      `json:"rotateSecret"`
+}
+
+type WebhookResponse struct {
+	ID          uuid.UUID `json:"id"`
+	URL         string    `json:"url"`
+	EventTypes  []string  `json:"eventTypes"`
+	Description string    `json:"description"`
+	IsActive    bool      `json:"isActive"`
+	Secret      string    `json:"secret,omitempty"`
+	CreatedAt   string    `json:"createdAt"`
+	UpdatedAt   string    `json:"updatedAt"`
+}
+
+func NewWebhookHandler(db *repository.Database, cfg *config.Config, webhooks *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		db:       db,
+		cfg:      cfg,
+		webhooks: webhooks,
+	}
+}
+
+// GetWebhooks godoc
+// @Summary List webhook subscriptions
+// @Description List webhook subscriptions for the current owner's restaurant
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/webhooks [get]
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	var subscriptions []models.WebhookSubscription
+	if err := h.db.DB.Where("restaurant_id = ?", restaurant.ID).Order("created_at ASC").Find(&subscriptions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch webhooks",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	responses := []WebhookResponse{}
+	for _, subscription := range subscriptions {
+		responses = append(responses, h.toWebhookResponse(&subscription, false))
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Webhooks retrieved successfully",
+		Data:    responses,
+	})
+}
+
+// CreateWebhook godoc
+// @Summary Create webhook subscription
+// @Description Subscribe a URL to order lifecycle events. The signing secret is only returned once.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param webhook body CreateWebhookRequest true "Webhook data"
+// @Success 201 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if msg := validateWebhook(c.Request.Context(), req.URL, req.EventTypes); msg != "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: msg,
+		})
+		return
+	}
+
+	secret, err := services.GenerateWebhookSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to generate webhook secret",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	subscription := models.WebhookSubscription{
+		RestaurantID: restaurant.ID,
+		URL:          req.URL,
+		Secret:       secret,
+		EventTypes:   req.EventTypes,
+		Description:  req.Description,
+		IsActive:     true,
+	}
+
+	if err := h.db.DB.Create(&subscription).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to create webhook",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Success: true,
+		Message: "Webhook created successfully",
+		Data:    h.toWebhookResponse(&subscription, true),
+	})
+}
+
+// UpdateWebhook godoc
+// @Summary Update webhook subscription
+// @Description Update URL, event types or status of a webhook, optionally rotating its secret
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Param webhook body UpdateWebhookRequest true "Webhook update data"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	subscription, ok := h.findSubscription(c, restaurant.ID)
+	if !ok {
+		return
+	}
+
+	var req UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if req.URL != nil {
+		subscription.URL = *req.URL
+	}
+	if req.EventTypes != nil {
+		subscription.EventTypes = *req.EventTypes
+	}
+	if req.Description != nil {
+		subscription.Description = *req.Description
+	}
+	if req.IsActive != nil {
+		subscription.IsActive = *req.IsActive
+	}
+
+	if msg := validateWebhook(c.Request.Context(), subscription.URL, subscription.EventTypes); msg != "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: msg,
+		})
+		return
+	}
+
+	if req.RotateSecret {
+		secret, err := services.GenerateWebhookSecret()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to generate webhook secret",
+				Error:   err.Error(),
+			})
+			return
+		}
+		subscription.Secret = secret
+	}
+
+	if err := h.db.DB.Save(subscription).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to update webhook",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Webhook updated successfully",
+		Data:    h.toWebhookResponse(subscription, req.RotateSecret),
+	})
+}
+
+// DeleteWebhook godoc
+// @Summary Delete webhook subscription
+// @Description Delete a webhook subscription and its delivery history
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	subscription, ok := h.findSubscription(c, restaurant.ID)
+	if !ok {
+		return
+	}
+
+	if err := h.db.DB.Delete(subscription).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to delete webhook",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Webhook deleted successfully",
+	})
+}
+
+// GetWebhookDeliveries godoc
+// @Summary List webhook deliveries
+// @Description List delivery attempts for a webhook subscription
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Param status query string false "Filter by status" Enums(pending, succeeded, dead)
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Page cursor: empty for the first page, then the previous page's nextCursor; replaces page"
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	subscription, ok := h.findSubscription(c, restaurant.ID)
+	if !ok {
+		return
+	}
+
+	query := h.db.DB.Model(&models.WebhookDelivery{}).Where("subscription_id = ?", subscription.ID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	h.respondWithDeliveries(c, query)
+}
+
+// GetDeadLetters godoc
+// @Summary List dead-lettered webhook deliveries
+// @Description List deliveries that exhausted their retries across all of the restaurant's webhooks
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Page cursor: empty for the first page, then the previous page's nextCursor; replaces page"
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/webhooks/dead-letters [get]
+func (h *WebhookHandler) GetDeadLetters(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	query := h.db.DB.Model(&models.WebhookDelivery{}).
+		Where("restaurant_id = ? AND status = ?", restaurant.ID, models.WebhookDeliveryDead)
+
+	h.respondWithDeliveries(c, query)
+}
+
+// RedeliverWebhook godoc
+// @Summary Redeliver a webhook
+// @Description Requeue a delivery (typically a dead letter) for another round of attempts
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Param deliveryId path string true "Delivery ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /restaurant/webhooks/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) RedeliverWebhook(c *gin.Context) {
+	restaurant, ok := currentOwnerRestaurant(c, h.db)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("deliveryId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid delivery ID",
+		})
+		return
+	}
+
+	var delivery models.WebhookDelivery
+	if err := h.db.DB.Where("id = ? AND restaurant_id = ?", deliveryID, restaurant.ID).First(&delivery).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Delivery not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch delivery",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	if err := h.webhooks.Redeliver(&delivery); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to requeue delivery",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if err := h.db.DB.Where("id = ?", delivery.ID).First(&delivery).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch delivery",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Delivery queued for redelivery",
+		Data:    delivery,
+	})
+}
+
+func (h *WebhookHandler) respondWithDeliveries(c *gin.Context, query *gorm.DB) {
+	page, ok := parsePage(c, newestFirst("webhook_deliveries"), 20)
+	if !ok {
+		return
+	}
+
+	total, err := page.countTotal(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to count deliveries",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := page.apply(query).Find(&deliveries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to fetch deliveries",
+			Error:   err.Error(),
+		})
+		return
+	}
+	deliveries, pagination := finishPage(page, deliveries, total, func(delivery *models.WebhookDelivery) (uuid.UUID, []interface{}) {
+		return delivery.ID, []interface{}{delivery.CreatedAt}
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Deliveries retrieved successfully",
+		"data": gin.H{
+			"deliveries": deliveries,
+			"pagination": pagination,
+		},
+	})
+}
+
+func (h *WebhookHandler) findSubscription(c *gin.Context, restaurantID uuid.UUID) (*models.WebhookSubscription, bool) {
+	subscriptionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid webhook ID",
+		})
+		return nil, false
+	}
+
+	var subscription models.WebhookSubscription
+	if err := h.db.DB.Where("id = ? AND restaurant_id = ?", subscriptionID, restaurantID).First(&subscription).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Message: "Webhook not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to fetch webhook",
+				Error:   err.Error(),
+			})
+		}
+		return nil, false
+	}
+
+	return &subscription, true
+}
+
+func (h *WebhookHandler) toWebhookResponse(subscription *models.WebhookSubscription, includeSecret bool) WebhookResponse {
+	response := WebhookResponse{
+		ID:          subscription.ID,
+		URL:         subscription.URL,
+		EventTypes:  subscription.EventTypes,
+		Description: subscription.Description,
+		IsActive:    subscription.IsActive,
+		CreatedAt:   subscription.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:   subscription.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if includeSecret {
+		response.Secret = subscription.Secret
+	}
+
+	return response
+}
+
+// validateWebhook returns a user-facing error message, or "" when the URL and
+// event types are acceptable.
+func validateWebhook(ctx context.Context, rawURL string, eventTypes []string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "Webhook URL must be an absolute http or https URL"
+	}
+	if err := services.CheckWebhookHost(ctx, parsed.Hostname()); err != nil {
+		if err == services.ErrWebhookAddressNotAllowed {
+			return "Webhook URL must point to a public address"
+		}
+		return "Webhook URL host could not be resolved"
+	}
+
+	if len(eventTypes) == 0 {
+		return "At least one event type is required"
+	}
+
+	for _, eventType := range eventTypes {
+		if !models.IsWebhookEventType(models.EventType(eventType)) {
+			return "Unsupported event type: " + eventType
+		}
+	}
+
+	return ""
+}
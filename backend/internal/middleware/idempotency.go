@@ -0,0 +1,265 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyMiddleware makes a route safe to retry. When a request carries an
+// Idempotency-Key header, the response is stored per user and key; a retry
+// with the same body gets the stored response back, and reusing the key with a
+// different body is rejected with 422. The key is claimed with a pending
+// record before the handler runs, in a short transaction of its own, so no
+// connection is held while the handler works. A concurrent request with the
+// same key waits for the first to finish, up to idempotencyWaitTimeout, and
+// gets its response. Only successful and deterministic client error responses
+// are stored; anything else releases the key so the client can retry.
+func IdempotencyMiddleware(db *repository.Database, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Idempotency-Key must be at most 255 characters",
+			})
+			c.Abort()
+			return
+		}
+
+		userID, exists := GetCurrentUserID(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"message": "User not authenticated",
+			})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Failed to read request body",
+			})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Request.Method + "\n" + c.Request.URL.Path + "\n"))
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		record := models.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: requestHash,
+		}
+		deadline := time.Now().Add(idempotencyWaitTimeout)
+		for {
+			stored, err := claimIdempotencyKey(db.DB, &record)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"success": false,
+					"message": "Failed to claim idempotency key",
+				})
+				c.Abort()
+				return
+			}
+			if stored == nil {
+				break
+			}
+
+			if stored.RequestHash != requestHash {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"success": false,
+					"message": "Idempotency-Key was already used with a different request",
+				})
+				c.Abort()
+				return
+			}
+			if !stored.IsPending() {
+				c.Header(IdempotencyReplayedHeader, "true")
+				c.Data(stored.StatusCode, stored.ContentType, []byte(stored.ResponseBody))
+				c.Abort()
+				return
+			}
+			if time.Now().After(deadline) {
+				c.Header("Retry-After", "1")
+				c.JSON(http.StatusConflict, gin.H{
+					"success": false,
+					"message": "A request with this Idempotency-Key is still being processed",
+				})
+				c.Abort()
+				return
+			}
+
+			// Wait for the first request to finish or release the key
+			select {
+			case <-c.Request.Context().Done():
+				c.Abort()
+				return
+			case <-time.After(idempotencyPollInterval):
+			}
+		}
+
+		// Keep the claim while the handler runs, however long it takes
+		done := make(chan struct{})
+		defer close(done)
+		go renewIdempotencyClaim(db.DB, record.ID, done)
+
+		// Release the key if the handler panics or its response isn't kept.
+		// Only the claim this request owns is touched: if it was lost,
+		// another request holds the key and its record stays as it is
+		saved := false
+		defer func() {
+			if !saved {
+				db.DB.Where("id = ? AND status_code = 0", record.ID).Delete(&models.IdempotencyKey{})
+			}
+		}()
+
+		recorder := &idempotencyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if !storeIdempotentResponse(status) {
+			return
+		}
+
+		result := db.DB.Model(&models.IdempotencyKey{}).
+			Where("id = ? AND status_code = 0", record.ID).
+			Updates(map[string]interface{}{
+				"status_code":   status,
+				"content_type":  recorder.Header().Get("Content-Type"),
+				"response_body": recorder.body.String(),
+				"expires_at":    time.Now().Add(ttl),
+			})
+		if result.Error != nil {
+			return
+		}
+		if result.RowsAffected == 0 {
+			log.Printf("idempotency: claim on key %s of user %s was lost before the response was stored", key, userID)
+		}
+		saved = true
+	}
+}
+
+const (
+	// idempotencyLease is how long a claimed key stays locked without being
+	// renewed, so a request that never finishes, say because the server
+	// stopped, doesn't lock it for long.
+	idempotencyLease = 30 * time.Second
+
+	// idempotencyWaitTimeout is how long a request waits for another with the
+	// same key to finish before giving up with 409.
+	idempotencyWaitTimeout = 10 * time.Second
+
+	idempotencyPollInterval = 100 * time.Millisecond
+)
+
+// claimIdempotencyKey claims the record's key for the request, with a pending
+// record that holds it for idempotencyLease. It returns the unexpired record
+// already holding the key instead, if there is one.
+func claimIdempotencyKey(db *gorm.DB, record *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	var stored *models.IdempotencyKey
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", record.UserID.String()+":"+record.Key).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		var existing models.IdempotencyKey
+		err := tx.Where("user_id = ? AND key = ? AND expires_at > ?", record.UserID, record.Key, now).First(&existing).Error
+		if err == nil {
+			stored = &existing
+			return nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		// Drop this user's expired keys, including an expired record for this key
+		if err := tx.Where("user_id = ? AND expires_at <= ?", record.UserID, now).Delete(&models.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+		record.ID = uuid.Nil
+		record.ExpiresAt = now.Add(idempotencyLease)
+		return tx.Create(record).Error
+	})
+	return stored, err
+}
+
+// renewIdempotencyClaim pushes the expiry of a pending claim forward until
+// done is closed.
+func renewIdempotencyClaim(db *gorm.DB, id uuid.UUID, done <-chan struct{}) {
+	ticker := time.NewTicker(idempotencyLease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := db.Model(&models.IdempotencyKey{}).
+				Where("id = ? AND status_code = 0", id).
+				Update("expires_at", time.Now().Add(idempotencyLease)).Error; err != nil {
+				log.Printf("idempotency: failed to renew claim %s: %v", id, err)
+			}
+		}
+	}
+}
+
+// storeIdempotentResponse reports whether a response is the same every time
+// the request is sent, so retries should get it back. Conflicts, rate limits
+// and server errors can go differently on a retry.
+func storeIdempotentResponse(status int) bool {
+	if status >= 200 && status < 300 {
+		return true
+	}
+	return status == http.StatusBadRequest || status == http.StatusUnprocessableEntity
+}
+
+// idempotencyRecorder copies the response body while it is written.
+type idempotencyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
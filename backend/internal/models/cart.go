@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CustomizationSelection is the set of options picked for one customization
+// of a menu item.
+type CustomizationSelection struct {
+	CustomizationID uuid.UUID   `json:"customizationId" binding:"required"`
+	OptionIDs       []uuid.UUID `json:"optionIds"`
+}
+
+// Cart is a server-side basket. A user has at most one cart per restaurant.
+type Cart struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID       uuid.UUID `json:"userId" gorm:"type:uuid;not null;uniqueIndex:idx_carts_user_restaurant,priority:1"`
+	RestaurantID uuid.UUID `json:"restaurantId" gorm:"type:uuid;not null;uniqueIndex:idx_carts_user_restaurant,priority:2"`
+	ExpiresAt    time.Time `json:"expiresAt" gorm:"not null;index"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+
+	// Relationships
+	User       User       `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Restaurant Restaurant `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Items      []CartItem `json:"items" gorm:"foreignKey:CartID"`
+}
+
+func (c *Cart) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
+
+// IsExpired reports whether the cart has outlived its TTL.
+func (c *Cart) IsExpired() bool {
+	return !c.ExpiresAt.After(time.Now())
+}
+
+// CartItem keeps the name and unit price seen when the item was added, so
+// later reads can flag items whose price or availability changed.
+type CartItem struct {
+	ID                  uuid.UUID                `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CartID              uuid.UUID                `json:"cartId" gorm:"type:uuid;not null;index"`
+	MenuItemID          uuid.UUID                `json:"menuItemId" gorm:"type:uuid;not null"`
+	Quantity            int                      `json:"quantity" gorm:"not null"`
+	Selections          []CustomizationSelection `json:"selections" gorm:"serializer:json;type:jsonb"`
+	Components          []ComboSelection         `json:"components,omitempty" gorm:"serializer:json;type:jsonb"`
+	SpecialInstructions string                   `json:"specialInstructions"`
+	Name                string                   `json:"name" gorm:"not null"`
+	UnitPrice           float64                  `json:"unitPrice" gorm:"not null"`
+	CreatedAt           time.Time                `json:"createdAt"`
+	UpdatedAt           time.Time                `json:"updatedAt"`
+
+	// Relationships
+	Cart     Cart     `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	MenuItem MenuItem `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (ci *CartItem) BeforeCreate(tx *gorm.DB) (err error) {
+	if ci.ID == uuid.Nil {
+		ci.ID = uuid.New()
+	}
+	return
+}
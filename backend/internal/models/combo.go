@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ComboSlot is one part of a combo menu item, like its main, side or drink,
+// filled with one of its options when the combo is ordered. The combo's own
+// price is the bundle price; options can cost more as upgrades.
+type ComboSlot struct {
+	ID        uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ComboID   uuid.UUID         `json:"comboId" gorm:"type:uuid;not null;index"`
+	Name      string            `json:"name" gorm:"not null"`
+	Optional  bool              `json:"optional" gorm:"default:false"`
+	Position  int               `json:"position" gorm:"default:0"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Options   []ComboSlotOption `json:"options" gorm:"foreignKey:SlotID;constraint:OnDelete:CASCADE"`
+}
+
+func (cs *ComboSlot) BeforeCreate(tx *gorm.DB) (err error) {
+	if cs.ID == uuid.Nil {
+		cs.ID = uuid.New()
+	}
+	return
+}
+
+// ComboSlotOption is a menu item that can fill a combo slot, and what it adds
+// to the combo's price.
+type ComboSlotOption struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SlotID     uuid.UUID `json:"slotId" gorm:"type:uuid;not null;index"`
+	MenuItemID uuid.UUID `json:"menuItemId" gorm:"type:uuid;not null;index"`
+	PriceDelta float64   `json:"priceDelta" gorm:"default:0.0"`
+
+	// Relationships
+	MenuItem MenuItem `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (cso *ComboSlotOption) BeforeCreate(tx *gorm.DB) (err error) {
+	if cso.ID == uuid.Nil {
+		cso.ID = uuid.New()
+	}
+	return
+}
+
+// ComboSelection fills a combo slot with one of its menu items, customized as
+// that item allows.
+type ComboSelection struct {
+	SlotID     uuid.UUID                `json:"slotId" binding:"required"`
+	MenuItemID uuid.UUID                `json:"menuItemId" binding:"required"`
+	Selections []CustomizationSelection `json:"selections" binding:"omitempty,dive"`
+}
+
+// OrderItemComponent is what filled one slot of a combo order item, resolved
+// when the order was placed. Options describe its customizations, like
+// "Size: Large".
+type OrderItemComponent struct {
+	SlotID     uuid.UUID                `json:"slotId"`
+	Slot       string                   `json:"slot"`
+	MenuItemID uuid.UUID                `json:"menuItemId"`
+	Name       string                   `json:"name"`
+	PriceDelta float64                  `json:"priceDelta"`
+	Selections []CustomizationSelection `json:"selections,omitempty"`
+	Options    []string                 `json:"options,omitempty"`
+}
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Allergen is one of the allergens a menu item can declare: the 14 EU
+// allergens, which include the US major allergens, plus wheat, which US
+// labelling names on its own.
+type Allergen string
+
+const (
+	CeleryAllergen      Allergen = "celery"
+	CrustaceansAllergen Allergen = "crustaceans"
+	EggsAllergen        Allergen = "eggs"
+	FishAllergen        Allergen = "fish"
+	GlutenAllergen      Allergen = "gluten"
+	LupinAllergen       Allergen = "lupin"
+	MilkAllergen        Allergen = "milk"
+	MolluscsAllergen    Allergen = "molluscs"
+	MustardAllergen     Allergen = "mustard"
+	PeanutsAllergen     Allergen = "peanuts"
+	SesameAllergen      Allergen = "sesame"
+	SoyAllergen         Allergen = "soy"
+	SulphitesAllergen   Allergen = "sulphites"
+	TreeNutsAllergen    Allergen = "tree_nuts"
+	WheatAllergen       Allergen = "wheat"
+)
+
+// Allergens lists every allergen in alphabetical order.
+var Allergens = []Allergen{
+	CeleryAllergen, CrustaceansAllergen, EggsAllergen, FishAllergen, GlutenAllergen,
+	LupinAllergen, MilkAllergen, MolluscsAllergen, MustardAllergen, PeanutsAllergen,
+	SesameAllergen, SoyAllergen, SulphitesAllergen, TreeNutsAllergen, WheatAllergen,
+}
+
+func IsValidAllergen(allergen Allergen) bool {
+	for _, known := range Allergens {
+		if allergen == known {
+			return true
+		}
+	}
+	return false
+}
+
+// allergenKeywords are the words free-text allergen notes used for each
+// allergen, for converting them. They match whole words, in the singular or
+// plural, so "shellfish" isn't fish and "eggplant" isn't egg; compounds that
+// do contain an allergen are listed on their own.
+var allergenKeywords = map[Allergen][]string{
+	CeleryAllergen:      {"celery", "celeriac"},
+	CrustaceansAllergen: {"crustacean", "shellfish", "shrimp", "prawn", "crab", "lobster"},
+	EggsAllergen:        {"egg"},
+	FishAllergen:        {"fish", "catfish", "anchovy", "salmon", "tuna", "cod"},
+	GlutenAllergen:      {"gluten", "wheat", "barley", "rye", "oat"},
+	LupinAllergen:       {"lupin"},
+	MilkAllergen:        {"milk", "buttermilk", "dairy", "lactose", "cheese", "butter", "cream", "yogurt", "yoghurt", "whey", "casein", "ghee"},
+	MolluscsAllergen:    {"mollusc", "mussel", "oyster", "clam", "squid"},
+	MustardAllergen:     {"mustard"},
+	PeanutsAllergen:     {"peanut"},
+	SesameAllergen:      {"sesame"},
+	SoyAllergen:         {"soy", "soya", "soybean"},
+	SulphitesAllergen:   {"sulphite", "sulfite", "sulphur dioxide", "sulfur dioxide"},
+	TreeNutsAllergen:    {"tree nut", "almond", "hazelnut", "walnut", "cashew", "pecan", "pistachio", "macadamia"},
+	WheatAllergen:       {"wheat"},
+}
+
+// AllergensInText finds the allergens a free-text note mentions.
+func AllergensInText(text string) []Allergen {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	var found []Allergen
+	for _, allergen := range Allergens {
+		for _, keyword := range allergenKeywords[allergen] {
+			if containsPhrase(words, strings.Fields(keyword)) {
+				found = append(found, allergen)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// containsPhrase reports whether words has the words of phrase in a row, the
+// last of them maybe in the plural.
+func containsPhrase(words, phrase []string) bool {
+	for start := 0; start+len(phrase) <= len(words); start++ {
+		matched := true
+		for i, want := range phrase {
+			word := words[start+i]
+			if word == want {
+				continue
+			}
+			if i == len(phrase)-1 && isPlural(word, want) {
+				continue
+			}
+			matched = false
+			break
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func isPlural(word, singular string) bool {
+	if strings.HasSuffix(singular, "y") && word == strings.TrimSuffix(singular, "y")+"ies" {
+		return true
+	}
+	return word == singular+"s" || word == singular+"es"
+}
+
+// DietaryTag marks a menu item as suiting a diet.
+type DietaryTag string
+
+const (
+	VeganTag      DietaryTag = "vegan"
+	VegetarianTag DietaryTag = "vegetarian"
+	HalalTag      DietaryTag = "halal"
+	GlutenFreeTag DietaryTag = "gluten_free"
+)
+
+// DietaryTags lists every dietary tag.
+var DietaryTags = []DietaryTag{VeganTag, VegetarianTag, HalalTag, GlutenFreeTag}
+
+func IsValidDietaryTag(tag DietaryTag) bool {
+	for _, known := range DietaryTags {
+		if tag == known {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxSpiceLevel is the hottest spice level: 0 is not spicy, then mild,
+// medium and hot.
+const MaxSpiceLevel = 3
+
+type MenuItemAllergen struct {
+	MenuItemID uuid.UUID `json:"-" gorm:"type:uuid;primaryKey"`
+	Allergen   Allergen  `json:"allergen" gorm:"type:varchar(20);primaryKey;index"`
+}
+
+type MenuItemDietaryTag struct {
+	MenuItemID uuid.UUID  `json:"-" gorm:"type:uuid;primaryKey"`
+	Tag        DietaryTag `json:"tag" gorm:"type:varchar(20);primaryKey;index"`
+}
+
+// UserAllergen is an allergen the user avoids; checkout warns about items
+// that contain it.
+type UserAllergen struct {
+	UserID   uuid.UUID `json:"-" gorm:"type:uuid;primaryKey"`
+	Allergen Allergen  `json:"allergen" gorm:"type:varchar(20);primaryKey"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAllergensInText(t *testing.T) {
+	tests := []struct {
+		text string
+		want []Allergen
+	}{
+		{"", nil},
+		{"Contains eggs and milk", []Allergen{EggsAllergen, MilkAllergen}},
+		{"Shellfish", []Allergen{CrustaceansAllergen}},
+		{"shellfish, fish sauce", []Allergen{CrustaceansAllergen, FishAllergen}},
+		{"Eggplant", nil},
+		{"Peanuts; tree nuts", []Allergen{PeanutsAllergen, TreeNutsAllergen}},
+		{"Made with oats", []Allergen{GlutenAllergen}},
+		{"Wheat flour", []Allergen{GlutenAllergen, WheatAllergen}},
+		{"soya lecithin", []Allergen{SoyAllergen}},
+		{"sulphur dioxide", []Allergen{SulphitesAllergen}},
+		{"buttermilk", []Allergen{MilkAllergen}},
+		{"Catfish", []Allergen{FishAllergen}},
+		{"anchovies", []Allergen{FishAllergen}},
+	}
+	for _, tt := range tests {
+		if got := AllergensInText(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("AllergensInText(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
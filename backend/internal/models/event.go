@@ -0,0 +1,194 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	OrderCreatedEvent            EventType = "order.created"
+	OrderStatusChangedEvent      EventType = "order.status_changed"
+	OrderAcceptanceReminderEvent EventType = "order.acceptance_reminder"
+	RestaurantPausedEvent        EventType = "restaurant.paused"
+	MenuLowStockEvent            EventType = "menu.low_stock"
+	ReviewCreatedEvent           EventType = "review.created"
+	ReviewUpdatedEvent           EventType = "review.updated"
+	ReviewDeletedEvent           EventType = "review.deleted"
+	RefundRequestedEvent         EventType = "refund.requested"
+	RefundApprovedEvent          EventType = "refund.approved"
+	RefundDeniedEvent            EventType = "refund.denied"
+)
+
+// WebhookEventTypes lists the events restaurants can subscribe to.
+var WebhookEventTypes = []EventType{
+	OrderCreatedEvent,
+	OrderStatusChangedEvent,
+	OrderAcceptanceReminderEvent,
+	MenuLowStockEvent,
+}
+
+func IsWebhookEventType(eventType EventType) bool {
+	for _, t := range WebhookEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type OrderEventItem struct {
+	MenuItemID          uuid.UUID            `json:"menuItemId"`
+	Name                string               `json:"name"`
+	Price               float64              `json:"price"`
+	Quantity            int                  `json:"quantity"`
+	CustomizationsData  string               `json:"customizationsData,omitempty"`
+	Components          []OrderItemComponent `json:"components,omitempty"`
+	SpecialInstructions string               `json:"specialInstructions,omitempty"`
+}
+
+// OrderEventPayload is the data published for order lifecycle events.
+type OrderEventPayload struct {
+	OrderID             uuid.UUID         `json:"orderId"`
+	RestaurantID        uuid.UUID         `json:"restaurantId"`
+	UserID              uuid.UUID         `json:"userId"`
+	Status              OrderStatus       `json:"status"`
+	PreviousStatus      OrderStatus       `json:"previousStatus,omitempty"`
+	Message             string            `json:"message,omitempty"`
+	FulfilmentType      FulfilmentType    `json:"fulfilmentType"`
+	PickupCode          string            `json:"pickupCode,omitempty"`
+	TableNumber         string            `json:"tableNumber,omitempty"`
+	TotalAmount         float64           `json:"totalAmount"`
+	DeliveryFee         float64           `json:"deliveryFee"`
+	Tax                 float64           `json:"tax"`
+	Tip                 float64           `json:"tip"`
+	DiscountAmount      float64           `json:"discountAmount"`
+	PromoCode           string            `json:"promoCode,omitempty"`
+	PaymentMethodType   PaymentMethodType `json:"paymentMethodType"`
+	SpecialInstructions string            `json:"specialInstructions,omitempty"`
+	Items               []OrderEventItem  `json:"items"`
+	OccurredAt          time.Time         `json:"occurredAt"`
+}
+
+func NewOrderEventPayload(order *Order, previousStatus OrderStatus, message string) OrderEventPayload {
+	payload := OrderEventPayload{
+		OrderID:             order.ID,
+		RestaurantID:        order.RestaurantID,
+		UserID:              order.UserID,
+		Status:              order.Status,
+		PreviousStatus:      previousStatus,
+		Message:             message,
+		FulfilmentType:      order.FulfilmentType,
+		PickupCode:          order.PickupCode,
+		TableNumber:         order.TableNumber,
+		TotalAmount:         order.TotalAmount,
+		DeliveryFee:         order.DeliveryFee,
+		Tax:                 order.Tax,
+		Tip:                 order.Tip,
+		DiscountAmount:      order.DiscountAmount,
+		PromoCode:           order.PromoCode,
+		PaymentMethodType:   order.PaymentMethodType,
+		SpecialInstructions: order.SpecialInstructions,
+		Items:               []OrderEventItem{},
+		OccurredAt:          time.Now().UTC(),
+	}
+
+	for _, item := range order.Items {
+		payload.Items = append(payload.Items, OrderEventItem{
+			MenuItemID:          item.MenuItemID,
+			Name:                item.Name,
+			Price:               item.Price,
+			Quantity:            item.Quantity,
+			CustomizationsData:  item.CustomizationsData,
+			Components:          item.Components,
+			SpecialInstructions: item.SpecialInstructions,
+		})
+	}
+
+	return payload
+}
+
+// ReviewEventPayload is the data published for review events.
+type ReviewEventPayload struct {
+	ReviewID     uuid.UUID `json:"reviewId"`
+	RestaurantID uuid.UUID `json:"restaurantId"`
+	UserID       uuid.UUID `json:"userId"`
+	OrderID      uuid.UUID `json:"orderId"`
+	Rating       int       `json:"rating"`
+	OccurredAt   time.Time `json:"occurredAt"`
+}
+
+func NewReviewEventPayload(review *Review) ReviewEventPayload {
+	return ReviewEventPayload{
+		ReviewID:     review.ID,
+		RestaurantID: review.RestaurantID,
+		UserID:       review.UserID,
+		OrderID:      review.OrderID,
+		Rating:       review.Rating,
+		OccurredAt:   time.Now().UTC(),
+	}
+}
+
+// RestaurantEventPayload is the data published for restaurant events.
+type RestaurantEventPayload struct {
+	RestaurantID uuid.UUID `json:"restaurantId"`
+	OwnerID      uuid.UUID `json:"ownerId"`
+	MissedOrders int       `json:"missedOrders"`
+	Reason       string    `json:"reason,omitempty"`
+	OccurredAt   time.Time `json:"occurredAt"`
+}
+
+func NewRestaurantEventPayload(restaurant *Restaurant, reason string) RestaurantEventPayload {
+	return RestaurantEventPayload{
+		RestaurantID: restaurant.ID,
+		OwnerID:      restaurant.OwnerID,
+		MissedOrders: restaurant.MissedOrders,
+		Reason:       reason,
+		OccurredAt:   time.Now().UTC(),
+	}
+}
+
+// StockEventPayload is the data published when a menu item or option runs
+// low on stock. OptionID is set for options, and Name then names both.
+type StockEventPayload struct {
+	RestaurantID      uuid.UUID  `json:"restaurantId"`
+	MenuItemID        uuid.UUID  `json:"menuItemId"`
+	OptionID          *uuid.UUID `json:"optionId,omitempty"`
+	Name              string     `json:"name"`
+	StockCount        int        `json:"stockCount"`
+	LowStockThreshold int        `json:"lowStockThreshold"`
+	OccurredAt        time.Time  `json:"occurredAt"`
+}
+
+// RefundEventPayload is the data published for refund events.
+type RefundEventPayload struct {
+	RefundID        uuid.UUID    `json:"refundId"`
+	OrderID         uuid.UUID    `json:"orderId"`
+	RestaurantID    uuid.UUID    `json:"restaurantId"`
+	UserID          uuid.UUID    `json:"userId"`
+	Status          RefundStatus `json:"status"`
+	Method          RefundMethod `json:"method"`
+	RequestedAmount float64      `json:"requestedAmount"`
+	Amount          float64      `json:"amount"`
+	OrderTotal      float64      `json:"orderTotal"`
+	Note            string       `json:"note,omitempty"`
+	OccurredAt      time.Time    `json:"occurredAt"`
+}
+
+func NewRefundEventPayload(refund *Refund, order *Order) RefundEventPayload {
+	return RefundEventPayload{
+		RefundID:        refund.ID,
+		OrderID:         refund.OrderID,
+		RestaurantID:    refund.RestaurantID,
+		UserID:          refund.UserID,
+		Status:          refund.Status,
+		Method:          refund.Method,
+		RequestedAmount: refund.RequestedAmount,
+		Amount:          refund.Amount,
+		OrderTotal:      order.GrandTotal(),
+		Note:            refund.DecisionNote,
+		OccurredAt:      time.Now().UTC(),
+	}
+}
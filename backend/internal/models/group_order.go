@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GroupOrderStatus string
+
+const (
+	GroupOrderOpen      GroupOrderStatus = "open"
+	GroupOrderLocked    GroupOrderStatus = "locked"
+	GroupOrderSubmitted GroupOrderStatus = "submitted"
+	GroupOrderCancelled GroupOrderStatus = "cancelled"
+)
+
+// GroupOrder is a shared cart for one restaurant. Participants join with the
+// invite code and add their own items; the host locks it and checks it out as
+// a single order.
+type GroupOrder struct {
+	ID           uuid.UUID        `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	HostID       uuid.UUID        `json:"hostId" gorm:"type:uuid;not null;index"`
+	RestaurantID uuid.UUID        `json:"restaurantId" gorm:"type:uuid;not null"`
+	InviteCode   string           `json:"inviteCode" gorm:"type:varchar(32);uniqueIndex;not null"`
+	Status       GroupOrderStatus `json:"status" gorm:"type:varchar(20);default:'open';not null"`
+	OrderID      *uuid.UUID       `json:"orderId,omitempty" gorm:"type:uuid"`
+	CreatedAt    time.Time        `json:"createdAt"`
+	UpdatedAt    time.Time        `json:"updatedAt"`
+
+	// Relationships
+	Host         User                    `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Restaurant   Restaurant              `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Participants []GroupOrderParticipant `json:"participants" gorm:"foreignKey:GroupOrderID"`
+	Items        []GroupOrderItem        `json:"items" gorm:"foreignKey:GroupOrderID"`
+}
+
+func (g *GroupOrder) BeforeCreate(tx *gorm.DB) (err error) {
+	if g.ID == uuid.Nil {
+		g.ID = uuid.New()
+	}
+	return
+}
+
+// GroupOrderParticipant is a user who joined a group order, host included.
+type GroupOrderParticipant struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	GroupOrderID uuid.UUID `json:"groupOrderId" gorm:"type:uuid;not null;uniqueIndex:idx_group_order_participants_user,priority:1"`
+	UserID       uuid.UUID `json:"userId" gorm:"type:uuid;not null;uniqueIndex:idx_group_order_participants_user,priority:2"`
+	CreatedAt    time.Time `json:"joinedAt"`
+
+	// Relationships
+	GroupOrder GroupOrder `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	User       User       `json:"user" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (p *GroupOrderParticipant) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return
+}
+
+// GroupOrderItem is an item a participant put in the shared cart. Like a
+// CartItem it keeps the name and unit price seen when it was added.
+type GroupOrderItem struct {
+	ID                  uuid.UUID                `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	GroupOrderID        uuid.UUID                `json:"groupOrderId" gorm:"type:uuid;not null;index"`
+	AddedByID           uuid.UUID                `json:"addedById" gorm:"type:uuid;not null"`
+	MenuItemID          uuid.UUID                `json:"menuItemId" gorm:"type:uuid;not null"`
+	Quantity            int                      `json:"quantity" gorm:"not null"`
+	Selections          []CustomizationSelection `json:"selections" gorm:"serializer:json;type:jsonb"`
+	Components          []ComboSelection         `json:"components,omitempty" gorm:"serializer:json;type:jsonb"`
+	SpecialInstructions string                   `json:"specialInstructions"`
+	Name                string                   `json:"name" gorm:"not null"`
+	UnitPrice           float64                  `json:"unitPrice" gorm:"not null"`
+	CreatedAt           time.Time                `json:"createdAt"`
+	UpdatedAt           time.Time                `json:"updatedAt"`
+
+	// Relationships
+	GroupOrder GroupOrder `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	AddedBy    User       `json:"-" gorm:"foreignKey:AddedByID;constraint:OnDelete:CASCADE"`
+	MenuItem   MenuItem   `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (i *GroupOrderItem) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return
+}
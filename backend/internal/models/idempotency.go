@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// IdempotencyKey stores the response to a request sent with an
+// Idempotency-Key header so retries of the same request can be replayed.
+// While the first request is still running the key is pending, with no
+// status code yet.
+type IdempotencyKey struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID       uuid.UUID `json:"userId" gorm:"type:uuid;not null;uniqueIndex:idx_idempotency_keys_user_key,priority:1"`
+	Key          string    `json:"key" gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_keys_user_key,priority:2"`
+	Method       string    `json:"method" gorm:"type:varchar(10);not null"`
+	Path         string    `json:"path" gorm:"not null"`
+	RequestHash  string    `json:"requestHash" gorm:"type:varchar(64);not null"`
+	StatusCode   int       `json:"statusCode" gorm:"not null"`
+	ContentType  string    `json:"contentType"`
+	ResponseBody string    `json:"responseBody" gorm:"type:text"`
+	ExpiresAt    time.Time `json:"expiresAt" gorm:"not null;index"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func (ik *IdempotencyKey) BeforeCreate(tx *gorm.DB) (err error) {
+	if ik.ID == uuid.Nil {
+		ik.ID = uuid.New()
+	}
+	return
+}
+
+// IsPending reports whether the request that claimed the key hasn't finished.
+func (ik *IdempotencyKey) IsPending() bool {
+	return ik.StatusCode == 0
+}
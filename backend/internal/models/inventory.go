@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Stock is the optional inventory of a menu item or customization option. A
+// nil StockCount isn't tracked and never runs out. DailyStock is what the
+// count goes back to at the restaurant's daily reset. Selling the last one
+// makes the item or option unavailable and sets SoldOutAt, so restocking can
+// make it available again without undoing a manual switch.
+type Stock struct {
+	StockCount        *int       `json:"stockCount,omitempty"`
+	DailyStock        *int       `json:"dailyStock,omitempty"`
+	LowStockThreshold int        `json:"lowStockThreshold,omitempty" gorm:"default:0"`
+	SoldOutAt         *time.Time `json:"soldOutAt,omitempty"`
+}
+
+// IsTracked reports whether stock is counted.
+func (s *Stock) IsTracked() bool {
+	return s.StockCount != nil
+}
+
+// IsLow reports whether tracked stock is at or below its alert threshold.
+func (s *Stock) IsLow() bool {
+	return s.StockCount != nil && *s.StockCount <= s.LowStockThreshold
+}
+
+type StockMovementReason string
+
+const (
+	StockSold     StockMovementReason = "sold"
+	StockRestored StockMovementReason = "restored"
+	StockAdjusted StockMovementReason = "adjusted"
+)
+
+// StockMovement records a change to the stock of a menu item or option, so
+// cancelled orders give back exactly what they took.
+type StockMovement struct {
+	ID           uuid.UUID           `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	RestaurantID uuid.UUID           `json:"restaurantId" gorm:"type:uuid;not null;index"`
+	MenuItemID   *uuid.UUID          `json:"menuItemId,omitempty" gorm:"type:uuid"`
+	OptionID     *uuid.UUID          `json:"optionId,omitempty" gorm:"type:uuid"`
+	OrderID      *uuid.UUID          `json:"orderId,omitempty" gorm:"type:uuid;index"`
+	Reason       StockMovementReason `json:"reason" gorm:"type:varchar(20);not null"`
+	Change       int                 `json:"change" gorm:"not null"`
+	StockAfter   int                 `json:"stockAfter" gorm:"not null"`
+	CreatedAt    time.Time           `json:"createdAt"`
+}
+
+func (sm *StockMovement) BeforeCreate(tx *gorm.DB) (err error) {
+	if sm.ID == uuid.Nil {
+		sm.ID = uuid.New()
+	}
+	return
+}
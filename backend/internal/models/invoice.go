@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Invoice is the customer invoice issued for an order. Sequence numbers run
+// per restaurant without gaps, in the order invoices were issued.
+type Invoice struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	RestaurantID uuid.UUID `json:"restaurantId" gorm:"type:uuid;not null;uniqueIndex:idx_invoices_restaurant_sequence,priority:1"`
+	OrderID      uuid.UUID `json:"orderId" gorm:"type:uuid;not null;uniqueIndex"`
+	Sequence     int       `json:"sequence" gorm:"not null;uniqueIndex:idx_invoices_restaurant_sequence,priority:2"`
+	Number       string    `json:"number" gorm:"type:varchar(20);not null"`
+	IssuedAt     time.Time `json:"issuedAt" gorm:"not null"`
+
+	// Relationships
+	Restaurant Restaurant `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Order      Order      `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (i *Invoice) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return
+}
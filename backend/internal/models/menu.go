@@ -14,12 +14,14 @@ type MenuCategory struct {
 	Description  string    `json:"description"`
 	Order        int       `json:"order" gorm:"default:0"`
 	IsActive     bool      `json:"isActive" gorm:"default:true"`
+	PausedUntil  *time.Time `json:"pausedUntil,omitempty"`
 	CreatedAt    time.Time `json:"createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt"`
 
 	// Relationships
 	Restaurant Restaurant `json:"restaurant" gorm:"constraint:OnDelete:CASCADE"`
 	MenuItems  []MenuItem `json:"menuItems" gorm:"foreignKey:CategoryID"`
+	Schedules  []MenuSchedule `json:"schedules" gorm:"foreignKey:CategoryID;constraint:OnDelete:CASCADE"`
 }
 
 func (mc *MenuCategory) BeforeCreate(tx *gorm.DB) (err error) {
@@ -29,17 +31,30 @@ func (mc *MenuCategory) BeforeCreate(tx *gorm.DB) (err error) {
 	return
 }
 
+// IsPaused reports whether the category is paused at t.
+func (mc *MenuCategory) IsPaused(t time.Time) bool {
+	return mc.PausedUntil != nil && mc.PausedUntil.After(t)
+}
+
 type MenuItem struct {
 	ID              uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	RestaurantID    uuid.UUID `json:"restaurantId" gorm:"type:uuid;not null"`
+	RestaurantID    uuid.UUID `json:"restaurantId" gorm:"type:uuid;not null;uniqueIndex:idx_menu_items_restaurant_sku,priority:1"`
 	CategoryID      uuid.UUID `json:"categoryId" gorm:"type:uuid;not null"`
+	// Position orders the item within its category
+	Position        int       `json:"position" gorm:"default:0"`
+	// SKU is the restaurant's own code for the item; imports match on it
+	SKU             *string   `json:"sku,omitempty" gorm:"type:varchar(64);uniqueIndex:idx_menu_items_restaurant_sku,priority:2"`
 	Name            string    `json:"name" gorm:"not null"`
 	Description     string    `json:"description"`
 	Price           float64   `json:"price" gorm:"not null"`
 	Image           string    `json:"image"`
 	IsAvailable     bool      `json:"isAvailable" gorm:"default:true"`
+	PausedUntil     *time.Time `json:"pausedUntil,omitempty"`
+	Stock
+	// IsCombo items are bundles whose ComboSlots are filled when ordered
+	IsCombo         bool      `json:"isCombo" gorm:"default:false"`
 	PreparationTime int       `json:"preparationTime" gorm:"default:15"`
-	Allergens       string    `json:"allergens" gorm:"type:text"`
+	SpiceLevel      int       `json:"spiceLevel" gorm:"default:0"`
 	Calories        *int      `json:"calories,omitempty"`
 	Protein         *float64  `json:"protein,omitempty"`
 	Carbs           *float64  `json:"carbs,omitempty"`
@@ -53,6 +68,10 @@ type MenuItem struct {
 	Restaurant      Restaurant            `json:"restaurant" gorm:"constraint:OnDelete:CASCADE"`
 	Category        MenuCategory          `json:"category" gorm:"constraint:OnDelete:CASCADE"`
 	Customizations  []MenuCustomization   `json:"customizations" gorm:"foreignKey:MenuItemID"`
+	Allergens       []MenuItemAllergen    `json:"allergens" gorm:"foreignKey:MenuItemID;constraint:OnDelete:CASCADE"`
+	DietaryTags     []MenuItemDietaryTag  `json:"dietaryTags" gorm:"foreignKey:MenuItemID;constraint:OnDelete:CASCADE"`
+	Schedules       []MenuSchedule        `json:"schedules" gorm:"foreignKey:MenuItemID;constraint:OnDelete:CASCADE"`
+	ComboSlots      []ComboSlot           `json:"comboSlots,omitempty" gorm:"foreignKey:ComboID;constraint:OnDelete:CASCADE"`
 }
 
 func (mi *MenuItem) BeforeCreate(tx *gorm.DB) (err error) {
@@ -62,6 +81,12 @@ func (mi *MenuItem) BeforeCreate(tx *gorm.DB) (err error) {
 	return
 }
 
+// IsPaused reports whether the item is paused at t. Pauses end on their own,
+// unlike IsAvailable which the owner switches back.
+func (mi *MenuItem) IsPaused(t time.Time) bool {
+	return mi.PausedUntil != nil && mi.PausedUntil.After(t)
+}
+
 type CustomizationType string
 
 const (
@@ -70,6 +95,10 @@ const (
 	ChoiceCustomization CustomizationType = "choice"
 )
 
+func IsValidCustomizationType(customizationType CustomizationType) bool {
+	return customizationType == SizeCustomization || customizationType == AddonCustomization || customizationType == ChoiceCustomization
+}
+
 type MenuCustomization struct {
 	ID            uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	MenuItemID    uuid.UUID         `json:"menuItemId" gorm:"type:uuid;not null"`
@@ -98,6 +127,7 @@ type CustomizationOption struct {
 	Name            string    `json:"name" gorm:"not null"`
 	PriceModifier   float64   `json:"priceModifier" gorm:"default:0.0"`
 	IsAvailable     bool      `json:"isAvailable" gorm:"default:true"`
+	Stock
 	CreatedAt       time.Time `json:"createdAt"`
 	UpdatedAt       time.Time `json:"updatedAt"`
 
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Weekdays are the day names menu schedules use, indexed by time.Weekday.
+var Weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+func IsValidWeekday(day string) bool {
+	for _, known := range Weekdays {
+		if day == known {
+			return true
+		}
+	}
+	return false
+}
+
+// MenuSchedule is a window when a category or menu item is served, read in
+// the restaurant's time zone. Empty fields don't restrict: no days is every
+// day, no start time is from midnight and no end time is until midnight.
+// Times are HH:MM and dates YYYY-MM-DD; a window ending at or before its
+// start runs past midnight into the next day. Something with no schedules is
+// always served, and one with several is served during any of them.
+type MenuSchedule struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CategoryID *uuid.UUID `json:"-" gorm:"type:uuid;index"`
+	MenuItemID *uuid.UUID `json:"-" gorm:"type:uuid;index"`
+	Days       []string   `json:"days,omitempty" gorm:"serializer:json;type:jsonb"`
+	StartTime  string     `json:"startTime,omitempty" gorm:"type:varchar(5)"`
+	EndTime    string     `json:"endTime,omitempty" gorm:"type:varchar(5)"`
+	StartDate  string     `json:"startDate,omitempty" gorm:"type:varchar(10)"`
+	EndDate    string     `json:"endDate,omitempty" gorm:"type:varchar(10)"`
+	CreatedAt  time.Time  `json:"-"`
+}
+
+func (ms *MenuSchedule) BeforeCreate(tx *gorm.DB) (err error) {
+	if ms.ID == uuid.Nil {
+		ms.ID = uuid.New()
+	}
+	return
+}
+
+// Includes reports whether the window is open at t, which must be in the
+// restaurant's time zone.
+func (ms *MenuSchedule) Includes(t time.Time) bool {
+	clock := t.Format("15:04")
+	start, end := ms.StartTime, ms.EndTime
+	if start == "" {
+		start = "00:00"
+	}
+	if end == "" {
+		end = "24:00"
+	}
+
+	if start < end {
+		return ms.runsOn(t) && clock >= start && clock < end
+	}
+	// Overnight: the evening on the start day, the small hours the day after
+	if clock >= start && ms.runsOn(t) {
+		return true
+	}
+	return clock < end && ms.runsOn(t.AddDate(0, 0, -1))
+}
+
+// runsOn reports whether the window starts on the day of t.
+func (ms *MenuSchedule) runsOn(t time.Time) bool {
+	date := t.Format("2006-01-02")
+	if ms.StartDate != "" && date < ms.StartDate {
+		return false
+	}
+	if ms.EndDate != "" && date > ms.EndDate {
+		return false
+	}
+	if len(ms.Days) == 0 {
+		return true
+	}
+	today := Weekdays[t.Weekday()]
+	for _, day := range ms.Days {
+		if day == today {
+			return true
+		}
+	}
+	return false
+}
+
+// String describes the window for customers, like "monday, friday
+// 07:00-11:00 from 2026-12-01".
+func (ms *MenuSchedule) String() string {
+	parts := []string{"every day"}
+	if len(ms.Days) > 0 {
+		parts[0] = strings.Join(ms.Days, ", ")
+	}
+	if ms.StartTime != "" || ms.EndTime != "" {
+		start, end := ms.StartTime, ms.EndTime
+		if start == "" {
+			start = "00:00"
+		}
+		if end == "" {
+			end = "24:00"
+		}
+		parts = append(parts, start+"-"+end)
+	}
+	if ms.StartDate != "" {
+		parts = append(parts, "from "+ms.StartDate)
+	}
+	if ms.EndDate != "" {
+		parts = append(parts, "until "+ms.EndDate)
+	}
+	return strings.Join(parts, " ")
+}
+
+// IsOnSchedule reports whether any of the schedules is open at t; with none
+// it is always true.
+func IsOnSchedule(schedules []MenuSchedule, t time.Time) bool {
+	if len(schedules) == 0 {
+		return true
+	}
+	for i := range schedules {
+		if schedules[i].Includes(t) {
+			return true
+		}
+	}
+	return false
+}
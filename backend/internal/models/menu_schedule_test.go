@@ -0,0 +1,84 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMenuScheduleIncludes(t *testing.T) {
+	// December 2026: the 4th is a Friday, the 7th a Monday
+	at := func(day int, clock string) time.Time {
+		moment, err := time.Parse("2006-01-02 15:04", fmt.Sprintf("2026-12-%02d %s", day, clock))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return moment
+	}
+
+	breakfast := MenuSchedule{StartTime: "07:00", EndTime: "11:00"}
+	evenings := MenuSchedule{StartTime: "18:00"}
+	mornings := MenuSchedule{EndTime: "11:00"}
+	fridayLate := MenuSchedule{Days: []string{"friday"}, StartTime: "22:00", EndTime: "02:00"}
+	mondays := MenuSchedule{Days: []string{"monday"}}
+	weekend := MenuSchedule{StartDate: "2026-12-05", EndDate: "2026-12-06"}
+	lastNights := MenuSchedule{StartTime: "22:00", EndTime: "02:00", StartDate: "2026-12-05", EndDate: "2026-12-06"}
+
+	tests := []struct {
+		name     string
+		schedule MenuSchedule
+		at       time.Time
+		want     bool
+	}{
+		{"no restrictions", MenuSchedule{}, at(4, "03:15"), true},
+
+		{"before the window", breakfast, at(4, "06:59"), false},
+		{"at the start", breakfast, at(4, "07:00"), true},
+		{"just before the end", breakfast, at(4, "10:59"), true},
+		{"at the end", breakfast, at(4, "11:00"), false},
+
+		{"start only, before", evenings, at(4, "17:59"), false},
+		{"start only, runs to midnight", evenings, at(4, "23:59"), true},
+		{"end only, from midnight", mornings, at(4, "00:00"), true},
+		{"end only, at the end", mornings, at(4, "11:00"), false},
+
+		{"overnight, evening of the start day", fridayLate, at(4, "23:00"), true},
+		{"overnight, small hours of the next day", fridayLate, at(5, "01:00"), true},
+		{"overnight, at the end", fridayLate, at(5, "02:00"), false},
+		{"overnight, small hours of the start day", fridayLate, at(4, "01:00"), false},
+		{"overnight, evening of the next day", fridayLate, at(5, "23:00"), false},
+
+		{"on a listed day", mondays, at(7, "12:00"), true},
+		{"on another day", mondays, at(6, "12:00"), false},
+
+		{"before the start date", weekend, at(4, "23:59"), false},
+		{"on the start date", weekend, at(5, "00:00"), true},
+		{"on the end date", weekend, at(6, "23:59"), true},
+		{"after the end date", weekend, at(7, "00:00"), false},
+
+		{"overnight from the day before the start date", lastNights, at(5, "01:00"), false},
+		{"overnight from the end date", lastNights, at(7, "01:00"), true},
+		{"overnight after the end date", lastNights, at(7, "23:00"), false},
+	}
+	for _, tt := range tests {
+		if got := tt.schedule.Includes(tt.at); got != tt.want {
+			t.Errorf("%s: Includes(%s) = %v, want %v", tt.name, tt.at.Format("Mon 2006-01-02 15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestIsOnSchedule(t *testing.T) {
+	noon := time.Date(2026, 12, 7, 12, 0, 0, 0, time.UTC)
+	breakfast := MenuSchedule{StartTime: "07:00", EndTime: "11:00"}
+	lunch := MenuSchedule{StartTime: "11:30", EndTime: "14:30"}
+
+	if !IsOnSchedule(nil, noon) {
+		t.Error("no schedules should always be on schedule")
+	}
+	if IsOnSchedule([]MenuSchedule{breakfast}, noon) {
+		t.Error("breakfast shouldn't be on at noon")
+	}
+	if !IsOnSchedule([]MenuSchedule{breakfast, lunch}, noon) {
+		t.Error("breakfast or lunch should be on at noon")
+	}
+}
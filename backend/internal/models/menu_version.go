@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MenuVersionStatus string
+
+const (
+	// DraftMenuVersion can be edited without affecting the live menu
+	DraftMenuVersion MenuVersionStatus = "draft"
+	// ScheduledMenuVersion is published by the menu publisher at PublishAt
+	ScheduledMenuVersion MenuVersionStatus = "scheduled"
+	// PublishedMenuVersion is the live menu; a restaurant has at most one
+	PublishedMenuVersion MenuVersionStatus = "published"
+	// ArchivedMenuVersion was published and replaced; it can be rolled back to
+	ArchivedMenuVersion MenuVersionStatus = "archived"
+)
+
+// MenuVersion is a complete menu, stored in the menu import format. Versions
+// are numbered per restaurant; publishing one makes the live menu match it
+// and order items record the number they were priced from.
+type MenuVersion struct {
+	ID           uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	RestaurantID uuid.UUID         `json:"restaurantId" gorm:"type:uuid;not null;uniqueIndex:idx_menu_versions_restaurant_number,priority:1"`
+	Number       int               `json:"number" gorm:"not null;uniqueIndex:idx_menu_versions_restaurant_number,priority:2"`
+	Name         string            `json:"name"`
+	Status       MenuVersionStatus `json:"status" gorm:"type:varchar(20);default:'draft';not null;index"`
+	Menu         string            `json:"-" gorm:"type:jsonb;not null"`
+	PublishAt    *time.Time        `json:"publishAt,omitempty" gorm:"index"`
+	PublishedAt  *time.Time        `json:"publishedAt,omitempty"`
+	// LastError says why a scheduled publish failed and the version went back
+	// to draft
+	LastError string `json:"lastError,omitempty"`
+	// PublishFailedAt is when publishing the scheduled version last failed,
+	// so the publisher moves on to other versions before trying it again
+	PublishFailedAt *time.Time `json:"-"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+
+	// Relationships
+	Restaurant Restaurant `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (mv *MenuVersion) BeforeCreate(tx *gorm.DB) (err error) {
+	if mv.ID == uuid.Nil {
+		mv.ID = uuid.New()
+	}
+	return
+}
+
+// IsEditable reports whether the version is a draft; scheduled versions have
+// to be unscheduled first.
+func (mv *MenuVersion) IsEditable() bool {
+	return mv.Status == DraftMenuVersion
+}
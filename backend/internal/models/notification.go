@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NotificationChannel string
+
+const (
+	EmailChannel NotificationChannel = "email"
+	SMSChannel   NotificationChannel = "sms"
+	PushChannel  NotificationChannel = "push"
+)
+
+type NotificationStatus string
+
+const (
+	NotificationPending NotificationStatus = "pending"
+	NotificationSent    NotificationStatus = "sent"
+	NotificationFailed  NotificationStatus = "failed"
+)
+
+// Notification is a rendered message queued for delivery on one channel.
+type Notification struct {
+	ID            uuid.UUID           `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID        uuid.UUID           `json:"userId" gorm:"type:uuid;not null;index"`
+	OrderID       *uuid.UUID          `json:"orderId,omitempty" gorm:"type:uuid;index"`
+	EventID       uuid.UUID           `json:"eventId" gorm:"type:uuid;not null;uniqueIndex:idx_notifications_event_channel,priority:1"`
+	Channel       NotificationChannel `json:"channel" gorm:"type:varchar(10);not null;uniqueIndex:idx_notifications_event_channel,priority:2"`
+	Template      string              `json:"template" gorm:"not null"`
+	Locale        string              `json:"locale" gorm:"type:varchar(10);not null"`
+	Recipient     string              `json:"recipient" gorm:"not null"`
+	Subject       string              `json:"subject"`
+	Body          string              `json:"body" gorm:"type:text;not null"`
+	Status        NotificationStatus  `json:"status" gorm:"default:'pending';not null;index:idx_notifications_due,priority:1"`
+	Attempts      int                 `json:"attempts" gorm:"default:0"`
+	NextAttemptAt time.Time           `json:"nextAttemptAt" gorm:"not null;index:idx_notifications_due,priority:2"`
+	LastAttemptAt *time.Time          `json:"lastAttemptAt,omitempty"`
+	LastError     string              `json:"lastError"`
+	SentAt        *time.Time          `json:"sentAt,omitempty"`
+	LeaseID       *uuid.UUID          `json:"-" gorm:"type:uuid"`
+	CreatedAt     time.Time           `json:"createdAt"`
+	UpdatedAt     time.Time           `json:"updatedAt"`
+
+	// Relationships
+	User User `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (n *Notification) BeforeCreate(tx *gorm.DB) (err error) {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return
+}
@@ -17,9 +17,50 @@ const (
 	PickedUpStatus      OrderStatus = "picked_up"
 	OnTheWayStatus      OrderStatus = "on_the_way"
 	DeliveredStatus     OrderStatus = "delivered"
+	CollectedStatus     OrderStatus = "collected"
 	CancelledStatus     OrderStatus = "cancelled"
 )
 
+// KitchenStatuses are the statuses of orders the kitchen is working on.
+var KitchenStatuses = []OrderStatus{PendingStatus, ConfirmedStatus, PreparingStatus}
+
+// CompletedStatuses are the statuses of orders that reached the customer.
+var CompletedStatuses = []OrderStatus{DeliveredStatus, CollectedStatus}
+
+type FulfilmentType string
+
+const (
+	DeliveryFulfilment FulfilmentType = "delivery"
+	PickupFulfilment   FulfilmentType = "pickup"
+	DineInFulfilment   FulfilmentType = "dine_in"
+)
+
+// fulfilmentPaths lists the statuses each fulfilment type moves through, in
+// order. Any order that hasn't finished can also be cancelled.
+var fulfilmentPaths = map[FulfilmentType][]OrderStatus{
+	DeliveryFulfilment: {PendingStatus, ConfirmedStatus, PreparingStatus, ReadyForPickupStatus, PickedUpStatus, OnTheWayStatus, DeliveredStatus},
+	PickupFulfilment:   {PendingStatus, ConfirmedStatus, PreparingStatus, ReadyForPickupStatus, CollectedStatus},
+	DineInFulfilment:   {PendingStatus, ConfirmedStatus, PreparingStatus, DeliveredStatus},
+}
+
+func IsValidFulfilmentType(fulfilmentType FulfilmentType) bool {
+	_, ok := fulfilmentPaths[fulfilmentType]
+	return ok
+}
+
+// ItemPrepStatus is how far the kitchen is with one order item.
+type ItemPrepStatus string
+
+const (
+	ItemPrepQueued  ItemPrepStatus = "queued"
+	ItemPrepStarted ItemPrepStatus = "started"
+	ItemPrepDone    ItemPrepStatus = "done"
+)
+
+func IsValidItemPrepStatus(status ItemPrepStatus) bool {
+	return status == ItemPrepQueued || status == ItemPrepStarted || status == ItemPrepDone
+}
+
 type PaymentMethodType string
 
 const (
@@ -38,21 +79,79 @@ type Order struct {
 	DeliveryFee           float64     `json:"deliveryFee" gorm:"default:0.0"`
 	Tax                   float64     `json:"tax" gorm:"default:0.0"`
 	Tip                   float64     `json:"tip" gorm:"default:0.0"`
-	DeliveryAddressID     uuid.UUID   `json:"deliveryAddressId" gorm:"type:uuid;not null"`
+	PromotionID           *uuid.UUID  `json:"promotionId,omitempty" gorm:"type:uuid"`
+	PromoCode             string      `json:"promoCode,omitempty"`
+	DiscountAmount        float64     `json:"discountAmount" gorm:"default:0.0"`
+	PointsRedeemed        int         `json:"pointsRedeemed" gorm:"default:0"`
+	PointsAmount          float64     `json:"pointsAmount" gorm:"default:0.0"`
+	RefundedAmount        float64     `json:"refundedAmount" gorm:"default:0.0"`
+	GroupOrderID          *uuid.UUID  `json:"groupOrderId,omitempty" gorm:"type:uuid"`
+	FulfilmentType        FulfilmentType `json:"fulfilmentType" gorm:"type:varchar(20);default:'delivery';not null"`
+	DeliveryAddressID     *uuid.UUID  `json:"deliveryAddressId,omitempty" gorm:"type:uuid"`
+	PickupCode            string      `json:"pickupCode,omitempty" gorm:"type:varchar(8)"`
+	TableNumber           string      `json:"tableNumber,omitempty"`
 	PaymentMethodType     PaymentMethodType `json:"paymentMethodType" gorm:"not null"`
 	PaymentDetails        string      `json:"paymentDetails" gorm:"type:jsonb"`
 	SpecialInstructions   string      `json:"specialInstructions"`
 	EstimatedDeliveryTime *time.Time  `json:"estimatedDeliveryTime,omitempty"`
 	ActualDeliveryTime    *time.Time  `json:"actualDeliveryTime,omitempty"`
+	AcceptanceReminderSentAt *time.Time `json:"-"`
+	// AutoCancelFailedAt is when cancelling the timed out order last failed,
+	// so the sweep moves on to other orders before trying it again
+	AutoCancelFailedAt    *time.Time  `json:"-"`
 	CreatedAt             time.Time   `json:"createdAt"`
 	UpdatedAt             time.Time   `json:"updatedAt"`
 
 	// Relationships
 	User            User              `json:"user" gorm:"constraint:OnDelete:CASCADE"`
 	Restaurant      Restaurant        `json:"restaurant" gorm:"constraint:OnDelete:CASCADE"`
-	DeliveryAddress Address           `json:"deliveryAddress" gorm:"foreignKey:DeliveryAddressID"`
+	DeliveryAddress *Address          `json:"deliveryAddress,omitempty" gorm:"foreignKey:DeliveryAddressID"`
 	Items           []OrderItem       `json:"items" gorm:"foreignKey:OrderID"`
 	TrackingUpdates []TrackingUpdate  `json:"trackingUpdates" gorm:"foreignKey:OrderID"`
+	Refunds         []Refund          `json:"refunds,omitempty" gorm:"foreignKey:OrderID"`
+}
+
+// GrandTotal is what the customer pays: items, fees, tax and tip less any discount.
+func (o *Order) GrandTotal() float64 {
+	return o.TotalAmount + o.DeliveryFee + o.Tax + o.Tip - o.DiscountAmount
+}
+
+// AmountDue is the part of the grand total paid with the order's payment
+// method, after loyalty points.
+func (o *Order) AmountDue() float64 {
+	return o.GrandTotal() - o.PointsAmount
+}
+
+// IsCompleted reports whether the order was delivered, served or collected.
+func (o *Order) IsCompleted() bool {
+	return o.Status == DeliveredStatus || o.Status == CollectedStatus
+}
+
+// CanMoveTo reports whether the order can go from its current status to next
+// on its fulfilment path. Statuses only move forward, and finished orders
+// can't change.
+func (o *Order) CanMoveTo(next OrderStatus) bool {
+	if o.IsCompleted() || o.Status == CancelledStatus {
+		return false
+	}
+	if next == CancelledStatus {
+		return true
+	}
+
+	path, ok := fulfilmentPaths[o.FulfilmentType]
+	if !ok {
+		path = fulfilmentPaths[DeliveryFulfilment]
+	}
+	current := -1
+	for i, status := range path {
+		if status == o.Status {
+			current = i
+		}
+		if status == next {
+			return current >= 0 && i > current
+		}
+	}
+	return false
 }
 
 func (o *Order) BeforeCreate(tx *gorm.DB) (err error) {
@@ -66,11 +165,19 @@ type OrderItem struct {
 	ID                  uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	OrderID             uuid.UUID `json:"orderId" gorm:"type:uuid;not null"`
 	MenuItemID          uuid.UUID `json:"menuItemId" gorm:"type:uuid;not null"`
+	AddedByID           *uuid.UUID `json:"addedById,omitempty" gorm:"type:uuid"`
 	Name                string    `json:"name" gorm:"not null"`
 	Price               float64   `json:"price" gorm:"not null"`
+	// MenuVersion is the published menu version the item was priced from
+	MenuVersion         int       `json:"menuVersion" gorm:"default:0"`
 	Quantity            int       `json:"quantity" gorm:"not null"`
 	CustomizationsData  string    `json:"customizationsData" gorm:"type:jsonb"`
+	// Components are what filled the slots of a combo
+	Components          []OrderItemComponent `json:"components,omitempty" gorm:"serializer:json;type:jsonb"`
 	SpecialInstructions string    `json:"specialInstructions"`
+	PrepStatus          ItemPrepStatus `json:"prepStatus" gorm:"type:varchar(10);default:'queued';not null"`
+	PrepStartedAt       *time.Time `json:"prepStartedAt,omitempty"`
+	PrepDoneAt          *time.Time `json:"prepDoneAt,omitempty"`
 	CreatedAt           time.Time `json:"createdAt"`
 	UpdatedAt           time.Time `json:"updatedAt"`
 
@@ -0,0 +1,47 @@
+package models
+
+import "testing"
+
+func TestOrderCanMoveTo(t *testing.T) {
+	tests := []struct {
+		fulfilment FulfilmentType
+		from       OrderStatus
+		to         OrderStatus
+		want       bool
+	}{
+		{DeliveryFulfilment, PendingStatus, ConfirmedStatus, true},
+		{DeliveryFulfilment, PendingStatus, PreparingStatus, true},
+		{DeliveryFulfilment, PreparingStatus, ReadyForPickupStatus, true},
+		{DeliveryFulfilment, ReadyForPickupStatus, PickedUpStatus, true},
+		{DeliveryFulfilment, OnTheWayStatus, DeliveredStatus, true},
+		{DeliveryFulfilment, ReadyForPickupStatus, CollectedStatus, false},
+		{DeliveryFulfilment, PreparingStatus, ConfirmedStatus, false},
+		{DeliveryFulfilment, ConfirmedStatus, ConfirmedStatus, false},
+		{DeliveryFulfilment, OnTheWayStatus, CancelledStatus, true},
+		{DeliveryFulfilment, DeliveredStatus, CancelledStatus, false},
+		{DeliveryFulfilment, CancelledStatus, ConfirmedStatus, false},
+
+		{PickupFulfilment, ReadyForPickupStatus, CollectedStatus, true},
+		{PickupFulfilment, ReadyForPickupStatus, PickedUpStatus, false},
+		{PickupFulfilment, PreparingStatus, DeliveredStatus, false},
+		{PickupFulfilment, CollectedStatus, CancelledStatus, false},
+
+		{DineInFulfilment, PreparingStatus, DeliveredStatus, true},
+		{DineInFulfilment, PreparingStatus, ReadyForPickupStatus, false},
+		{DineInFulfilment, ConfirmedStatus, OnTheWayStatus, false},
+		{DineInFulfilment, PendingStatus, CancelledStatus, true},
+
+		// Orders from before fulfilment types follow the delivery path.
+		{"", PreparingStatus, ReadyForPickupStatus, true},
+		{"", ReadyForPickupStatus, CollectedStatus, false},
+
+		{DeliveryFulfilment, PendingStatus, "unknown", false},
+		{DeliveryFulfilment, CollectedStatus, DeliveredStatus, false},
+	}
+	for _, tt := range tests {
+		order := &Order{FulfilmentType: tt.fulfilment, Status: tt.from}
+		if got := order.CanMoveTo(tt.to); got != tt.want {
+			t.Errorf("%s order: CanMoveTo(%s -> %s) = %v, want %v", tt.fulfilment, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
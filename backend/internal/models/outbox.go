@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OutboxEvent is a domain event written in the same transaction as the change
+// it describes. The outbox relay hands it to in-process consumers after commit.
+type OutboxEvent struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	AggregateType string     `json:"aggregateType" gorm:"not null"`
+	AggregateID   uuid.UUID  `json:"aggregateId" gorm:"type:uuid;not null;index"`
+	EventType     EventType  `json:"eventType" gorm:"not null"`
+	Payload       string     `json:"payload" gorm:"type:jsonb;not null"`
+	Attempts      int        `json:"attempts" gorm:"default:0"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt" gorm:"not null;index:idx_outbox_events_pending,priority:2"`
+	LastError     string     `json:"lastError"`
+	ProcessedAt   *time.Time `json:"processedAt,omitempty" gorm:"index:idx_outbox_events_pending,priority:1"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+func (oe *OutboxEvent) BeforeCreate(tx *gorm.DB) (err error) {
+	if oe.ID == uuid.Nil {
+		oe.ID = uuid.New()
+	}
+	return
+}
+
+// OutboxConsumption records that a consumer has handled an event. It is
+// written in the consumer's transaction, which makes redelivery a no-op.
+type OutboxConsumption struct {
+	EventID    uuid.UUID `json:"eventId" gorm:"type:uuid;primaryKey"`
+	Consumer   string    `json:"consumer" gorm:"primaryKey"`
+	ConsumedAt time.Time `json:"consumedAt" gorm:"not null"`
+}
+
+// RestaurantDailyStat holds per-day counters maintained by the analytics consumer.
+type RestaurantDailyStat struct {
+	RestaurantID    uuid.UUID `json:"restaurantId" gorm:"type:uuid;primaryKey"`
+	Date            time.Time `json:"date" gorm:"type:date;primaryKey"`
+	OrdersPlaced    int       `json:"ordersPlaced" gorm:"default:0"`
+	OrdersDelivered int       `json:"ordersDelivered" gorm:"default:0"`
+	OrdersCancelled int       `json:"ordersCancelled" gorm:"default:0"`
+	GrossRevenue    float64   `json:"grossRevenue" gorm:"default:0.0"`
+	RefundedAmount  float64   `json:"refundedAmount" gorm:"default:0.0"`
+	ReviewsCreated  int       `json:"reviewsCreated" gorm:"default:0"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PromotionType string
+
+const (
+	PercentagePromotion   PromotionType = "percentage"
+	FixedPromotion        PromotionType = "fixed"
+	FreeDeliveryPromotion PromotionType = "free_delivery"
+)
+
+// Promotion is a promo code. Codes without a RestaurantID are platform-wide.
+type Promotion struct {
+	ID                    uuid.UUID     `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	RestaurantID          *uuid.UUID    `json:"restaurantId,omitempty" gorm:"type:uuid;index"`
+	Code                  string        `json:"code" gorm:"type:varchar(32);uniqueIndex;not null"`
+	Description           string        `json:"description"`
+	Type                  PromotionType `json:"type" gorm:"type:varchar(20);not null"`
+	Value                 float64       `json:"value" gorm:"default:0.0"`
+	MaxDiscount           *float64      `json:"maxDiscount,omitempty"`
+	MinOrderAmount        float64       `json:"minOrderAmount" gorm:"default:0.0"`
+	MaxRedemptions        *int          `json:"maxRedemptions,omitempty"`
+	MaxRedemptionsPerUser *int          `json:"maxRedemptionsPerUser,omitempty"`
+	RedemptionCount       int           `json:"redemptionCount" gorm:"default:0"`
+	StartsAt              *time.Time    `json:"startsAt,omitempty"`
+	EndsAt                *time.Time    `json:"endsAt,omitempty"`
+	FirstOrderOnly        bool          `json:"firstOrderOnly" gorm:"default:false"`
+	IsActive              bool          `json:"isActive" gorm:"default:true"`
+	CreatedByID           uuid.UUID     `json:"createdById" gorm:"type:uuid;not null"`
+	CreatedAt             time.Time     `json:"createdAt"`
+	UpdatedAt             time.Time     `json:"updatedAt"`
+
+	// Relationships
+	Restaurant *Restaurant `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (p *Promotion) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return
+}
+
+// IsRunning reports whether the code is active and inside its validity window.
+func (p *Promotion) IsRunning(now time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// Discount returns the amount taken off an order with the given item subtotal
+// and delivery fee. Item discounts never exceed the subtotal.
+func (p *Promotion) Discount(subtotal, deliveryFee float64) float64 {
+	var discount float64
+	switch p.Type {
+	case PercentagePromotion:
+		discount = subtotal * p.Value / 100
+		if p.MaxDiscount != nil && discount > *p.MaxDiscount {
+			discount = *p.MaxDiscount
+		}
+	case FixedPromotion:
+		discount = p.Value
+	case FreeDeliveryPromotion:
+		return deliveryFee
+	}
+
+	if discount > subtotal {
+		discount = subtotal
+	}
+	return math.Round(discount*100) / 100
+}
+
+// PromotionRedemption records a promo code used on an order. Redemptions of
+// cancelled orders are voided and no longer count towards usage limits.
+type PromotionRedemption struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PromotionID    uuid.UUID  `json:"promotionId" gorm:"type:uuid;not null;index"`
+	OrderID        uuid.UUID  `json:"orderId" gorm:"type:uuid;not null;uniqueIndex"`
+	UserID         uuid.UUID  `json:"userId" gorm:"type:uuid;not null;index"`
+	RestaurantID   uuid.UUID  `json:"restaurantId" gorm:"type:uuid;not null"`
+	Code           string     `json:"code" gorm:"not null"`
+	DiscountAmount float64    `json:"discountAmount" gorm:"not null"`
+	OrderSubtotal  float64    `json:"orderSubtotal" gorm:"not null"`
+	VoidedAt       *time.Time `json:"voidedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+
+	// Relationships
+	Promotion Promotion `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Order     Order     `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (pr *PromotionRedemption) BeforeCreate(tx *gorm.DB) (err error) {
+	if pr.ID == uuid.Nil {
+		pr.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RefundStatus string
+
+const (
+	RefundRequested RefundStatus = "requested"
+	RefundApproved  RefundStatus = "approved"
+	RefundDenied    RefundStatus = "denied"
+)
+
+// RefundMethod is how an approved refund is paid out.
+type RefundMethod string
+
+const (
+	RefundToOriginalPayment RefundMethod = "original_payment"
+	RefundToWallet          RefundMethod = "wallet_credit"
+)
+
+func IsValidRefundMethod(method RefundMethod) bool {
+	return method == RefundToOriginalPayment || method == RefundToWallet
+}
+
+// Refund is a customer's request to get money back for items of a completed
+// order. RequestedAmount is worked out from the items; Amount is what was
+// actually paid out once approved.
+type Refund struct {
+	ID               uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	OrderID          uuid.UUID    `json:"orderId" gorm:"type:uuid;not null;index"`
+	UserID           uuid.UUID    `json:"userId" gorm:"type:uuid;not null;index"`
+	RestaurantID     uuid.UUID    `json:"restaurantId" gorm:"type:uuid;not null;index"`
+	Status           RefundStatus `json:"status" gorm:"type:varchar(20);default:'requested';not null"`
+	Reason           string       `json:"reason" gorm:"not null"`
+	Photos           []string     `json:"photos" gorm:"serializer:json;type:jsonb"`
+	Method           RefundMethod `json:"method" gorm:"type:varchar(20);not null"`
+	RequestedAmount  float64      `json:"requestedAmount" gorm:"not null"`
+	Amount           float64      `json:"amount" gorm:"default:0.0"`
+	PointsCredited   int          `json:"pointsCredited" gorm:"default:0"`
+	PaymentReference string       `json:"paymentReference,omitempty"`
+	DecisionNote     string       `json:"decisionNote,omitempty"`
+	DecidedByID      *uuid.UUID   `json:"decidedById,omitempty" gorm:"type:uuid"`
+	DecidedAt        *time.Time   `json:"decidedAt,omitempty"`
+	CreatedAt        time.Time    `json:"createdAt"`
+	UpdatedAt        time.Time    `json:"updatedAt"`
+
+	// Relationships
+	Order Order        `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Items []RefundItem `json:"items" gorm:"foreignKey:RefundID"`
+}
+
+func (r *Refund) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return
+}
+
+// RefundItem is a quantity of one order item included in a refund.
+type RefundItem struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	RefundID    uuid.UUID `json:"refundId" gorm:"type:uuid;not null;index"`
+	OrderItemID uuid.UUID `json:"orderItemId" gorm:"type:uuid;not null;index"`
+	Name        string    `json:"name"`
+	Quantity    int       `json:"quantity" gorm:"not null"`
+	Amount      float64   `json:"amount" gorm:"not null"`
+
+	// Relationships
+	Refund    Refund    `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	OrderItem OrderItem `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (ri *RefundItem) BeforeCreate(tx *gorm.DB) (err error) {
+	if ri.ID == uuid.Nil {
+		ri.ID = uuid.New()
+	}
+	return
+}
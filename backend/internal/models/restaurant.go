@@ -22,7 +22,23 @@ type Restaurant struct {
 	DeliveryFee float64   `json:"deliveryFee" gorm:"default:0.0"`
 	MinDeliveryTime int   `json:"minDeliveryTime" gorm:"default:30"`
 	MaxDeliveryTime int   `json:"maxDeliveryTime" gorm:"default:60"`
+	OffersPickup bool     `json:"offersPickup" gorm:"default:false"`
+	OffersDineIn bool     `json:"offersDineIn" gorm:"default:false"`
 	IsOpen      bool      `json:"isOpen" gorm:"default:true"`
+	// TimeZone is the IANA zone menu schedules are read in
+	TimeZone    string    `json:"timeZone" gorm:"type:varchar(64);default:'UTC';not null"`
+	// StockResetTime is when, in TimeZone, daily stock goes back to its
+	// level; empty doesn't reset
+	StockResetTime string `json:"stockResetTime" gorm:"type:varchar(5)"`
+	StockResetAt *time.Time `json:"-"`
+	MissedOrders int      `json:"missedOrders" gorm:"default:0"`
+	BusyMode     bool     `json:"busyMode" gorm:"default:false"`
+	BusyExtraPrepMinutes int `json:"busyExtraPrepMinutes" gorm:"default:0"`
+	BusyMaxActiveOrders  int `json:"busyMaxActiveOrders" gorm:"default:0"`
+	BusyUntil    *time.Time `json:"busyUntil,omitempty"`
+	LastInvoiceSequence int `json:"-" gorm:"default:0"`
+	MenuVersion  int      `json:"menuVersion" gorm:"default:0"`
+	LastMenuVersion int   `json:"-" gorm:"default:0"`
 	IsActive    bool      `json:"isActive" gorm:"default:true"`
 	Image       string    `json:"image"`
 	CreatedAt   time.Time `json:"createdAt"`
@@ -45,6 +61,24 @@ func (r *Restaurant) BeforeCreate(tx *gorm.DB) (err error) {
 	return
 }
 
+// Location returns the restaurant's time zone, UTC if it isn't set or known.
+func (r *Restaurant) Location() *time.Location {
+	if r.TimeZone == "" {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(r.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// IsBusy reports whether busy mode is on at t. Busy mode with an end time
+// switches itself off once that time has passed.
+func (r *Restaurant) IsBusy(t time.Time) bool {
+	return r.BusyMode && (r.BusyUntil == nil || r.BusyUntil.After(t))
+}
+
 type OpeningHours struct {
 	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	RestaurantID uuid.UUID `json:"restaurantId" gorm:"type:uuid;not null"`
@@ -24,6 +24,8 @@ type User struct {
 	Phone     string    `json:"phone" gorm:"not null"`
 	Role      UserRole  `json:"role" gorm:"type:varchar(20);default:'customer';not null"`
 	IsActive  bool      `json:"isActive" gorm:"default:true"`
+	Locale    string    `json:"locale" gorm:"type:varchar(10);default:'en';not null"`
+	NotificationPreferences NotificationPreferences `json:"notificationPreferences" gorm:"embedded;embeddedPrefix:notify_"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 
@@ -33,6 +35,27 @@ type User struct {
 	Reviews     []Review     `json:"reviews" gorm:"foreignKey:UserID"`
 	Favorites   []Favorite   `json:"favorites" gorm:"foreignKey:UserID"`
 	Restaurant  *Restaurant  `json:"restaurant" gorm:"foreignKey:OwnerID"`
+	Allergens   []UserAllergen `json:"allergens" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// NotificationPreferences controls which channels a user is notified on.
+type NotificationPreferences struct {
+	Email bool `json:"email" gorm:"default:true"`
+	SMS   bool `json:"sms" gorm:"default:true"`
+	Push  bool `json:"push" gorm:"default:true"`
+}
+
+// Allows reports whether the user wants notifications on the given channel.
+func (np NotificationPreferences) Allows(channel NotificationChannel) bool {
+	switch channel {
+	case EmailChannel:
+		return np.Email
+	case SMSChannel:
+		return np.SMS
+	case PushChannel:
+		return np.Push
+	}
+	return false
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
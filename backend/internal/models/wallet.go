@@ -0,0 +1,57 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WalletEntryType string
+
+const (
+	WalletEarn           WalletEntryType = "earn"
+	WalletEarnReversal   WalletEntryType = "earn_reversal"
+	WalletRedeem         WalletEntryType = "redeem"
+	WalletRedeemReversal WalletEntryType = "redeem_reversal"
+	WalletAdjustment     WalletEntryType = "adjustment"
+	WalletRefundCredit   WalletEntryType = "refund_credit"
+)
+
+// ErrWalletEntryImmutable is returned when code tries to change the ledger.
+var ErrWalletEntryImmutable = errors.New("wallet entries are append-only")
+
+// WalletEntry is one line of a user's loyalty points ledger. Entries are never
+// updated or deleted; a user's balance is the sum of their entries. Order
+// entries are unique per order and type, and refund entries per refund and
+// type, so event redelivery can't repeat them.
+type WalletEntry struct {
+	ID          uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID      uuid.UUID       `json:"userId" gorm:"type:uuid;not null;index"`
+	Type        WalletEntryType `json:"type" gorm:"type:varchar(20);not null;uniqueIndex:idx_wallet_entries_order_type,priority:2,where:order_id IS NOT NULL AND refund_id IS NULL;uniqueIndex:idx_wallet_entries_refund_type,priority:2,where:refund_id IS NOT NULL"`
+	Points      int             `json:"points" gorm:"not null"`
+	OrderID     *uuid.UUID      `json:"orderId,omitempty" gorm:"type:uuid;uniqueIndex:idx_wallet_entries_order_type,priority:1,where:order_id IS NOT NULL AND refund_id IS NULL"`
+	RefundID    *uuid.UUID      `json:"refundId,omitempty" gorm:"type:uuid;uniqueIndex:idx_wallet_entries_refund_type,priority:1,where:refund_id IS NOT NULL"`
+	Reason      string          `json:"reason"`
+	CreatedByID *uuid.UUID      `json:"createdById,omitempty" gorm:"type:uuid"`
+	CreatedAt   time.Time       `json:"createdAt"`
+
+	// Relationships
+	User User `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (we *WalletEntry) BeforeCreate(tx *gorm.DB) (err error) {
+	if we.ID == uuid.Nil {
+		we.ID = uuid.New()
+	}
+	return
+}
+
+func (we *WalletEntry) BeforeUpdate(tx *gorm.DB) (err error) {
+	return ErrWalletEntryImmutable
+}
+
+func (we *WalletEntry) BeforeDelete(tx *gorm.DB) (err error) {
+	return ErrWalletEntryImmutable
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WebhookSubscription struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	RestaurantID uuid.UUID `json:"restaurantId" gorm:"type:uuid;not null;index"`
+	URL          string    `json:"url" gorm:"not null"`
+	Secret       string    `json:"-" gorm:"not null"`
+	EventTypes   []string  `json:"eventTypes" gorm:"serializer:json;type:jsonb;not null"`
+	Description  string    `json:"description"`
+	IsActive     bool      `json:"isActive" gorm:"default:true"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+
+	// Relationships
+	Restaurant Restaurant `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (ws *WebhookSubscription) BeforeCreate(tx *gorm.DB) (err error) {
+	if ws.ID == uuid.Nil {
+		ws.ID = uuid.New()
+	}
+	return
+}
+
+// Subscribes reports whether the subscription wants events of the given type.
+func (ws *WebhookSubscription) Subscribes(eventType EventType) bool {
+	for _, t := range ws.EventTypes {
+		if EventType(t) == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryDead      WebhookDeliveryStatus = "dead"
+)
+
+type WebhookDelivery struct {
+	ID               uuid.UUID             `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SubscriptionID   uuid.UUID             `json:"subscriptionId" gorm:"type:uuid;not null;index"`
+	RestaurantID     uuid.UUID             `json:"restaurantId" gorm:"type:uuid;not null;index"`
+	EventID          uuid.UUID             `json:"eventId" gorm:"type:uuid;not null"`
+	EventType        EventType             `json:"eventType" gorm:"not null"`
+	Payload          string                `json:"payload" gorm:"type:jsonb;not null"`
+	Status           WebhookDeliveryStatus `json:"status" gorm:"default:'pending';not null;index:idx_webhook_deliveries_due,priority:1"`
+	Attempts         int                   `json:"attempts" gorm:"default:0"`
+	NextAttemptAt    time.Time             `json:"nextAttemptAt" gorm:"not null;index:idx_webhook_deliveries_due,priority:2"`
+	LastAttemptAt    *time.Time            `json:"lastAttemptAt,omitempty"`
+	LastResponseCode int                   `json:"lastResponseCode"`
+	LastError        string                `json:"lastError"`
+	DeliveredAt      *time.Time            `json:"deliveredAt,omitempty"`
+	CreatedAt        time.Time             `json:"createdAt"`
+	UpdatedAt        time.Time             `json:"updatedAt"`
+
+	// Relationships
+	Subscription WebhookSubscription `json:"-" gorm:"foreignKey:SubscriptionID;constraint:OnDelete:CASCADE"`
+}
+
+func (wd *WebhookDelivery) BeforeCreate(tx *gorm.DB) (err error) {
+	if wd.ID == uuid.Nil {
+		wd.ID = uuid.New()
+	}
+	return
+}
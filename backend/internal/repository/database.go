@@ -34,7 +34,7 @@ func NewDatabase(cfg *config.DatabaseConfig) *Database {
 }
 
 func (d *Database) AutoMigrate() error {
-	return d.DB.AutoMigrate(
+	err := d.DB.AutoMigrate(
 		&models.User{},
 		&models.Address{},
 		&models.Restaurant{},
@@ -49,7 +49,40 @@ func (d *Database) AutoMigrate() error {
 		&models.TrackingUpdate{},
 		&models.Review{},
 		&models.Favorite{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+		&models.OutboxEvent{},
+		&models.OutboxConsumption{},
+		&models.RestaurantDailyStat{},
+		&models.Notification{},
+		&models.IdempotencyKey{},
+		&models.Cart{},
+		&models.CartItem{},
+		&models.Promotion{},
+		&models.PromotionRedemption{},
+		&models.WalletEntry{},
+		&models.Refund{},
+		&models.RefundItem{},
+		&models.GroupOrder{},
+		&models.GroupOrderParticipant{},
+		&models.GroupOrderItem{},
+		&models.Invoice{},
+		&models.MenuItemAllergen{},
+		&models.MenuItemDietaryTag{},
+		&models.UserAllergen{},
+		&models.MenuVersion{},
+		&models.MenuSchedule{},
+		&models.StockMovement{},
+		&models.ComboSlot{},
+		&models.ComboSlotOption{},
 	)
+	if err != nil {
+		return err
+	}
+	if err := d.migrateAllergenText(); err != nil {
+		return err
+	}
+	return d.migrateSearch()
 }
 
 func (d *Database) Close() error {
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"restaurantapp/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// migrateAllergenText converts the free-text allergens column menu items used
+// to have into allergen rows, then drops it. It does nothing once the column
+// is gone.
+func (d *Database) migrateAllergenText() error {
+	if !d.DB.Migrator().HasColumn("menu_items", "allergens") {
+		return nil
+	}
+
+	return d.DB.Transaction(func(tx *gorm.DB) error {
+		var notes []struct {
+			ID        uuid.UUID
+			Allergens string
+		}
+		if err := tx.Raw("SELECT id, allergens FROM menu_items WHERE allergens <> ''").Scan(&notes).Error; err != nil {
+			return err
+		}
+
+		var rows []models.MenuItemAllergen
+		for _, note := range notes {
+			for _, allergen := range models.AllergensInText(note.Allergens) {
+				rows = append(rows, models.MenuItemAllergen{MenuItemID: note.ID, Allergen: allergen})
+			}
+		}
+		if len(rows) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(rows, 500).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Exec("ALTER TABLE menu_items DROP COLUMN allergens").Error
+	})
+}
@@ -0,0 +1,33 @@
+package repository
+
+// searchMigrations add the full-text search columns and indexes AutoMigrate
+// can't express. The search vectors are generated columns, so Postgres keeps
+// them in sync on every insert and update. Each statement is safe to rerun.
+var searchMigrations = []string{
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+	`ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (
+			setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+			setweight(to_tsvector('english', coalesce(cuisine_type, '')), 'B') ||
+			setweight(to_tsvector('english', coalesce(description, '')), 'C')
+		) STORED`,
+	`ALTER TABLE menu_items ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (
+			setweight(to_tsvector('english', coalesce(name, '')), 'B') ||
+			setweight(to_tsvector('english', coalesce(description, '')), 'D')
+		) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_restaurants_search_vector ON restaurants USING GIN (search_vector)`,
+	`CREATE INDEX IF NOT EXISTS idx_menu_items_search_vector ON menu_items USING GIN (search_vector)`,
+	`CREATE INDEX IF NOT EXISTS idx_restaurants_name_trgm ON restaurants USING GIN (name gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_restaurants_cuisine_type_trgm ON restaurants USING GIN (cuisine_type gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_menu_items_name_trgm ON menu_items USING GIN (name gin_trgm_ops)`,
+}
+
+func (d *Database) migrateSearch() error {
+	for _, statement := range searchMigrations {
+		if err := d.DB.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const acceptanceTimeoutMessage = "Order cancelled: the restaurant did not accept it in time"
+
+// acceptanceRetryAfter is how long an order whose automatic cancellation
+// failed waits before it is tried again.
+const acceptanceRetryAfter = 5 * time.Minute
+
+// AcceptanceWatcher looks after orders the restaurant hasn't accepted yet. It
+// reminds the restaurant after ReminderAfter and cancels the order after
+// Timeout, voiding its payment. Restaurants that let PauseAfter orders in a
+// row time out are closed until the owner opens them again.
+type AcceptanceWatcher struct {
+	db       *repository.Database
+	cfg      *config.AcceptanceConfig
+	payments PaymentGateway
+}
+
+func NewAcceptanceWatcher(db *repository.Database, cfg *config.Config, payments PaymentGateway) *AcceptanceWatcher {
+	return &AcceptanceWatcher{
+		db:       db,
+		cfg:      &cfg.Acceptance,
+		payments: payments,
+	}
+}
+
+// Start sweeps pending orders until ctx is cancelled.
+func (w *AcceptanceWatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		w.sendReminders()
+		w.cancelTimedOut(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendReminders publishes one reminder per pending order that has waited
+// longer than ReminderAfter.
+func (w *AcceptanceWatcher) sendReminders() {
+	err := w.db.DB.Transaction(func(tx *gorm.DB) error {
+		var orders []models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND acceptance_reminder_sent_at IS NULL AND created_at <= ?", models.PendingStatus, time.Now().Add(-w.cfg.ReminderAfter)).
+			Order("created_at").
+			Limit(w.cfg.BatchSize).
+			Find(&orders).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for i := range orders {
+			order := &orders[i]
+			if err := tx.Model(order).Update("acceptance_reminder_sent_at", now).Error; err != nil {
+				return err
+			}
+			if err := PublishEvent(tx, "order", order.ID, models.OrderAcceptanceReminderEvent, models.NewOrderEventPayload(order, "", "Order is waiting to be accepted")); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("acceptance: failed to send reminders: %v", err)
+	}
+}
+
+// cancelTimedOut cancels pending orders that have waited longer than Timeout,
+// one transaction per order. An order that fails to cancel, say because its
+// void keeps failing, is set aside for acceptanceRetryAfter and the sweep
+// carries on with the rest, so it only holds back itself.
+func (w *AcceptanceWatcher) cancelTimedOut(ctx context.Context) {
+	for i := 0; i < w.cfg.BatchSize; i++ {
+		order, err := w.cancelNext(ctx)
+		if err != nil {
+			if order == nil {
+				log.Printf("acceptance: failed to find timed out orders: %v", err)
+				return
+			}
+			log.Printf("acceptance: failed to cancel timed out order %s: %v", order.ID, err)
+			if err := w.db.DB.Model(order).Update("auto_cancel_failed_at", time.Now()).Error; err != nil {
+				log.Printf("acceptance: failed to set aside order %s: %v", order.ID, err)
+				return
+			}
+			continue
+		}
+		if order == nil {
+			return
+		}
+	}
+}
+
+// cancelNext cancels the longest waiting timed out order. It returns the
+// order it picked, nil when there is none left.
+func (w *AcceptanceWatcher) cancelNext(ctx context.Context) (*models.Order, error) {
+	var picked *models.Order
+	err := w.db.DB.Transaction(func(tx *gorm.DB) error {
+		var order models.Order
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND created_at <= ?", models.PendingStatus, now.Add(-w.cfg.Timeout)).
+			Where("auto_cancel_failed_at IS NULL OR auto_cancel_failed_at <= ?", now.Add(-acceptanceRetryAfter)).
+			Order("created_at").
+			First(&order).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil
+			}
+			return err
+		}
+		picked = &models.Order{ID: order.ID}
+		if err := tx.Where("order_id = ?", order.ID).Find(&order.Items).Error; err != nil {
+			return err
+		}
+
+		previousStatus := order.Status
+		if err := tx.Model(&order).Update("status", models.CancelledStatus).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.TrackingUpdate{
+			OrderID: order.ID,
+			Status:  models.CancelledStatus,
+			Message: acceptanceTimeoutMessage,
+		}).Error; err != nil {
+			return err
+		}
+		if err := PublishEvent(tx, "order", order.ID, models.OrderStatusChangedEvent, models.NewOrderEventPayload(&order, previousStatus, acceptanceTimeoutMessage)); err != nil {
+			return err
+		}
+
+		if err := w.recordMissedOrder(tx, &order); err != nil {
+			return err
+		}
+
+		// The gateway goes last so a failed void rolls the cancellation back
+		// and the order is retried after acceptanceRetryAfter.
+		if IsGatewayPayment(order.PaymentMethodType) && order.AmountDue() > 0 {
+			if _, err := w.payments.Void(ctx, &order, "order_"+order.ID.String()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return picked, err
+}
+
+// recordMissedOrder counts a timed out order against its restaurant and closes
+// the restaurant once it has missed PauseAfter orders in a row.
+func (w *AcceptanceWatcher) recordMissedOrder(tx *gorm.DB, order *models.Order) error {
+	var restaurant models.Restaurant
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", order.RestaurantID).
+		First(&restaurant).Error; err != nil {
+		return err
+	}
+
+	restaurant.MissedOrders++
+	updates := map[string]interface{}{"missed_orders": restaurant.MissedOrders}
+	pause := w.cfg.PauseAfter > 0 && restaurant.MissedOrders >= w.cfg.PauseAfter && restaurant.IsOpen
+	if pause {
+		updates["is_open"] = false
+	}
+	if err := tx.Model(&restaurant).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	if !pause {
+		return nil
+	}
+	return PublishEvent(tx, "restaurant", restaurant.ID, models.RestaurantPausedEvent,
+		models.NewRestaurantEventPayload(&restaurant, "Closed after orders were not accepted in time"))
+}
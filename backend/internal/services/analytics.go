@@ -0,0 +1,95 @@
+package services
+
+import (
+	"time"
+
+	"restaurantapp/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AnalyticsConsumer maintains RestaurantDailyStat counters from domain events.
+// Increments are not idempotent on their own; the outbox consumption record
+// written in the same transaction guarantees each event is counted once.
+type AnalyticsConsumer struct{}
+
+func NewAnalyticsConsumer() *AnalyticsConsumer {
+	return &AnalyticsConsumer{}
+}
+
+func (ac *AnalyticsConsumer) Name() string {
+	return "analytics"
+}
+
+func (ac *AnalyticsConsumer) Handle(tx *gorm.DB, event *models.OutboxEvent) error {
+	switch event.EventType {
+	case models.OrderCreatedEvent:
+		payload, err := decodeOrderEvent(event)
+		if err != nil {
+			return err
+		}
+		return incrementDailyStat(tx, payload.RestaurantID, payload.OccurredAt, models.RestaurantDailyStat{
+			OrdersPlaced: 1,
+		})
+
+	case models.OrderStatusChangedEvent:
+		payload, err := decodeOrderEvent(event)
+		if err != nil {
+			return err
+		}
+		switch payload.Status {
+		case models.DeliveredStatus, models.CollectedStatus:
+			return incrementDailyStat(tx, payload.RestaurantID, payload.OccurredAt, models.RestaurantDailyStat{
+				OrdersDelivered: 1,
+				GrossRevenue:    payload.TotalAmount + payload.DeliveryFee + payload.Tax + payload.Tip - payload.DiscountAmount,
+			})
+		case models.CancelledStatus:
+			return incrementDailyStat(tx, payload.RestaurantID, payload.OccurredAt, models.RestaurantDailyStat{
+				OrdersCancelled: 1,
+			})
+		}
+
+	case models.RefundApprovedEvent:
+		payload, err := decodeRefundEvent(event)
+		if err != nil {
+			return err
+		}
+		return incrementDailyStat(tx, payload.RestaurantID, payload.OccurredAt, models.RestaurantDailyStat{
+			RefundedAmount: payload.Amount,
+		})
+
+	case models.ReviewCreatedEvent:
+		payload, err := decodeReviewEvent(event)
+		if err != nil {
+			return err
+		}
+		return incrementDailyStat(tx, payload.RestaurantID, payload.OccurredAt, models.RestaurantDailyStat{
+			ReviewsCreated: 1,
+		})
+	}
+
+	return nil
+}
+
+// incrementDailyStat upserts the restaurant's row for the day of occurredAt,
+// adding the counters set on delta to the stored ones.
+func incrementDailyStat(tx *gorm.DB, restaurantID uuid.UUID, occurredAt time.Time, delta models.RestaurantDailyStat) error {
+	delta.RestaurantID = restaurantID
+	delta.Date = occurredAt.UTC().Truncate(24 * time.Hour)
+	delta.UpdatedAt = time.Now()
+
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "restaurant_id"}, {Name: "date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"orders_placed":    gorm.Expr("restaurant_daily_stats.orders_placed + ?", delta.OrdersPlaced),
+			"orders_delivered": gorm.Expr("restaurant_daily_stats.orders_delivered + ?", delta.OrdersDelivered),
+			"orders_cancelled": gorm.Expr("restaurant_daily_stats.orders_cancelled + ?", delta.OrdersCancelled),
+			"gross_revenue":    gorm.Expr("restaurant_daily_stats.gross_revenue + ?", delta.GrossRevenue),
+			"refunded_amount":  gorm.Expr("restaurant_daily_stats.refunded_amount + ?", delta.RefundedAmount),
+			"reviews_created":  gorm.Expr("restaurant_daily_stats.reviews_created + ?", delta.ReviewsCreated),
+			"updated_at":       delta.UpdatedAt,
+		}),
+	}).Create(&delta).Error
+}
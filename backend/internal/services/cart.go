@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+)
+
+// CartJanitor deletes carts that have passed their expiry.
+type CartJanitor struct {
+	db  *repository.Database
+	cfg *config.CartConfig
+}
+
+func NewCartJanitor(db *repository.Database, cfg *config.Config) *CartJanitor {
+	return &CartJanitor{
+		db:  db,
+		cfg: &cfg.Cart,
+	}
+}
+
+// Start sweeps expired carts until ctx is cancelled.
+func (j *CartJanitor) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.cfg.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		result := j.db.DB.Where("expires_at <= ?", time.Now()).Delete(&models.Cart{})
+		if result.Error != nil {
+			log.Printf("carts: failed to delete expired carts: %v", result.Error)
+		} else if result.RowsAffected > 0 {
+			log.Printf("carts: deleted %d expired carts", result.RowsAffected)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
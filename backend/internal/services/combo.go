@@ -0,0 +1,132 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"restaurantapp/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ComboError reports combo slots that can't be set up as given. Its message
+// is safe to show.
+type ComboError struct {
+	Message string
+}
+
+func (e *ComboError) Error() string {
+	return e.Message
+}
+
+// MaxComboSlots is the most slots a combo can have.
+const MaxComboSlots = 10
+
+// SetComboSlots replaces the slots of a restaurant's menu item, making it a
+// combo, or a plain item again when slots is empty. Slot options must be
+// other menu items of the restaurant that aren't combos themselves, and a
+// menu item that fills a slot of another combo can't become one. It returns
+// gorm.ErrRecordNotFound if the menu item isn't the restaurant's.
+func SetComboSlots(tx *gorm.DB, restaurantID, comboID uuid.UUID, slots []models.ComboSlot) error {
+	var combo models.MenuItem
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND restaurant_id = ?", comboID, restaurantID).
+		First(&combo).Error; err != nil {
+		return err
+	}
+
+	if len(slots) > MaxComboSlots {
+		return &ComboError{Message: fmt.Sprintf("A combo can have at most %d slots", MaxComboSlots)}
+	}
+
+	if len(slots) > 0 {
+		var filling int64
+		if err := tx.Model(&models.ComboSlotOption{}).
+			Where("menu_item_id = ?", comboID).
+			Count(&filling).Error; err != nil {
+			return err
+		}
+		if filling > 0 {
+			return &ComboError{Message: combo.Name + " fills a slot of another combo, so it can't be a combo itself"}
+		}
+	}
+
+	names := make(map[string]bool, len(slots))
+	var optionIDs []uuid.UUID
+	for i := range slots {
+		slot := &slots[i]
+		slot.Name = strings.TrimSpace(slot.Name)
+		if slot.Name == "" {
+			return &ComboError{Message: "Every combo slot needs a name"}
+		}
+		key := strings.ToLower(slot.Name)
+		if names[key] {
+			return &ComboError{Message: fmt.Sprintf("Slot %s appears more than once", slot.Name)}
+		}
+		names[key] = true
+		if len(slot.Options) == 0 {
+			return &ComboError{Message: fmt.Sprintf("%s: add at least one menu item", slot.Name)}
+		}
+
+		seen := make(map[uuid.UUID]bool, len(slot.Options))
+		for _, option := range slot.Options {
+			if seen[option.MenuItemID] {
+				return &ComboError{Message: fmt.Sprintf("%s: menu item listed more than once", slot.Name)}
+			}
+			seen[option.MenuItemID] = true
+			if option.MenuItemID == comboID {
+				return &ComboError{Message: fmt.Sprintf("%s: a combo can't contain itself", slot.Name)}
+			}
+			if option.PriceDelta < 0 {
+				return &ComboError{Message: fmt.Sprintf("%s: price deltas can't be negative", slot.Name)}
+			}
+			optionIDs = append(optionIDs, option.MenuItemID)
+		}
+	}
+
+	if len(optionIDs) > 0 {
+		var items []models.MenuItem
+		if err := tx.Select("id", "name", "is_combo").
+			Where("id IN ? AND restaurant_id = ?", optionIDs, restaurantID).
+			Find(&items).Error; err != nil {
+			return err
+		}
+		found := make(map[uuid.UUID]bool, len(items))
+		for _, item := range items {
+			if item.IsCombo {
+				return &ComboError{Message: item.Name + " is a combo and can't fill a slot"}
+			}
+			found[item.ID] = true
+		}
+		for _, id := range optionIDs {
+			if !found[id] {
+				return &ComboError{Message: "Menu item " + id.String() + " not found"}
+			}
+		}
+	}
+
+	if err := tx.Where("slot_id IN (?)", tx.Model(&models.ComboSlot{}).Select("id").Where("combo_id = ?", comboID)).
+		Delete(&models.ComboSlotOption{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("combo_id = ?", comboID).Delete(&models.ComboSlot{}).Error; err != nil {
+		return err
+	}
+	for i := range slots {
+		slots[i].ID = uuid.Nil
+		slots[i].ComboID = comboID
+		slots[i].Position = i
+		for j := range slots[i].Options {
+			slots[i].Options[j].ID = uuid.Nil
+		}
+	}
+	if len(slots) > 0 {
+		if err := tx.Create(&slots).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(&combo).Update("is_combo", len(slots) > 0).Error
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"restaurantapp/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LabelError reports allergens or dietary tags outside the vocabulary or at
+// odds with each other. Its message is safe to show to the owner; Field names
+// the request field at fault.
+type LabelError struct {
+	Field   string
+	Message string
+}
+
+func (e *LabelError) Error() string {
+	return e.Message
+}
+
+// impliedAllergens are allergens that come with another: wheat contains
+// gluten.
+var impliedAllergens = map[models.Allergen][]models.Allergen{
+	models.WheatAllergen: {models.GlutenAllergen},
+}
+
+// tagConflicts are the allergens an item with each dietary tag can't
+// contain.
+var tagConflicts = map[models.DietaryTag][]models.Allergen{
+	models.VeganTag:      {models.MilkAllergen, models.EggsAllergen, models.FishAllergen, models.CrustaceansAllergen, models.MolluscsAllergen},
+	models.VegetarianTag: {models.FishAllergen, models.CrustaceansAllergen, models.MolluscsAllergen},
+	models.GlutenFreeTag: {models.GlutenAllergen},
+}
+
+// MenuItemLabels are the allergens, dietary tags and spice level of a menu
+// item.
+type MenuItemLabels struct {
+	Allergens   []models.Allergen
+	DietaryTags []models.DietaryTag
+	SpiceLevel  int
+}
+
+// ParseMenuItemLabels validates the labels of a menu item against the
+// vocabulary, dropping duplicates. Wheat adds gluten and vegan adds
+// vegetarian; tags that contradict an allergen are rejected.
+func ParseMenuItemLabels(allergens, tags []string, spiceLevel int) (MenuItemLabels, error) {
+	labels := MenuItemLabels{SpiceLevel: spiceLevel}
+	if spiceLevel < 0 || spiceLevel > models.MaxSpiceLevel {
+		return labels, &LabelError{Field: "spiceLevel", Message: fmt.Sprintf("Spice level must be between 0 and %d", models.MaxSpiceLevel)}
+	}
+
+	allergenSet := map[models.Allergen]bool{}
+	for _, name := range allergens {
+		allergen := models.Allergen(name)
+		if !models.IsValidAllergen(allergen) {
+			return labels, &LabelError{Field: "allergens", Message: fmt.Sprintf("Unknown allergen %q", name)}
+		}
+		allergenSet[allergen] = true
+		for _, implied := range impliedAllergens[allergen] {
+			allergenSet[implied] = true
+		}
+	}
+
+	tagSet := map[models.DietaryTag]bool{}
+	for _, name := range tags {
+		tag := models.DietaryTag(name)
+		if !models.IsValidDietaryTag(tag) {
+			return labels, &LabelError{Field: "dietaryTags", Message: fmt.Sprintf("Unknown dietary tag %q", name)}
+		}
+		tagSet[tag] = true
+	}
+	if tagSet[models.VeganTag] {
+		tagSet[models.VegetarianTag] = true
+	}
+
+	for _, tag := range models.DietaryTags {
+		if !tagSet[tag] {
+			continue
+		}
+		for _, allergen := range tagConflicts[tag] {
+			if allergenSet[allergen] {
+				return labels, &LabelError{Field: "dietaryTags", Message: fmt.Sprintf("Items containing %s can't be tagged %s", allergen, tag)}
+			}
+		}
+		labels.DietaryTags = append(labels.DietaryTags, tag)
+	}
+	for _, allergen := range models.Allergens {
+		if allergenSet[allergen] {
+			labels.Allergens = append(labels.Allergens, allergen)
+		}
+	}
+	return labels, nil
+}
+
+// SetMenuItemLabels replaces a menu item's allergens and dietary tags.
+func SetMenuItemLabels(tx *gorm.DB, menuItemID uuid.UUID, labels MenuItemLabels) error {
+	if err := tx.Where("menu_item_id = ?", menuItemID).Delete(&models.MenuItemAllergen{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("menu_item_id = ?", menuItemID).Delete(&models.MenuItemDietaryTag{}).Error; err != nil {
+		return err
+	}
+
+	if len(labels.Allergens) > 0 {
+		rows := make([]models.MenuItemAllergen, len(labels.Allergens))
+		for i, allergen := range labels.Allergens {
+			rows[i] = models.MenuItemAllergen{MenuItemID: menuItemID, Allergen: allergen}
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+	}
+	if len(labels.DietaryTags) > 0 {
+		rows := make([]models.MenuItemDietaryTag, len(labels.DietaryTags))
+		for i, tag := range labels.DietaryTags {
+			rows[i] = models.MenuItemDietaryTag{MenuItemID: menuItemID, Tag: tag}
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MenuItemFilters narrow a menu to the items a diner can eat. Zero values
+// don't filter.
+type MenuItemFilters struct {
+	ExcludeAllergens []models.Allergen
+	DietaryTags      []models.DietaryTag
+	MaxSpiceLevel    *int
+}
+
+// Apply adds the filters to a menu_items query. An item must contain none of
+// the excluded allergens and carry every dietary tag.
+func (f MenuItemFilters) Apply(db *gorm.DB) *gorm.DB {
+	if len(f.ExcludeAllergens) > 0 {
+		db = db.Where("NOT EXISTS (SELECT 1 FROM menu_item_allergens a WHERE a.menu_item_id = menu_items.id AND a.allergen IN ?)", f.ExcludeAllergens)
+	}
+	for _, tag := range f.DietaryTags {
+		db = db.Where("EXISTS (SELECT 1 FROM menu_item_dietary_tags t WHERE t.menu_item_id = menu_items.id AND t.tag = ?)", tag)
+	}
+	if f.MaxSpiceLevel != nil {
+		db = db.Where("menu_items.spice_level <= ?", *f.MaxSpiceLevel)
+	}
+	return db
+}
+
+// AllergenWarning flags a menu item that contains allergens the user avoids.
+type AllergenWarning struct {
+	MenuItemID uuid.UUID         `json:"menuItemId"`
+	Name       string            `json:"name"`
+	Allergens  []models.Allergen `json:"allergens"`
+}
+
+// AllergenWarnings checks menu items against the allergens saved on the
+// user's profile. Warnings don't stop checkout; they're for the customer to
+// confirm.
+func AllergenWarnings(db *gorm.DB, userID uuid.UUID, menuItemIDs []uuid.UUID) ([]AllergenWarning, error) {
+	warnings := []AllergenWarning{}
+	if len(menuItemIDs) == 0 {
+		return warnings, nil
+	}
+
+	var matches []struct {
+		MenuItemID uuid.UUID
+		Name       string
+		Allergen   models.Allergen
+	}
+	if err := db.Table("menu_item_allergens a").
+		Select("a.menu_item_id, mi.name, a.allergen").
+		Joins("JOIN menu_items mi ON mi.id = a.menu_item_id").
+		Joins("JOIN user_allergens ua ON ua.allergen = a.allergen AND ua.user_id = ?", userID).
+		Where("a.menu_item_id IN ?", menuItemIDs).
+		Order("mi.name, a.menu_item_id, a.allergen").
+		Scan(&matches).Error; err != nil {
+		return nil, err
+	}
+
+	for _, match := range matches {
+		if n := len(warnings); n > 0 && warnings[n-1].MenuItemID == match.MenuItemID {
+			warnings[n-1].Allergens = append(warnings[n-1].Allergens, match.Allergen)
+			continue
+		}
+		warnings = append(warnings, AllergenWarning{
+			MenuItemID: match.MenuItemID,
+			Name:       match.Name,
+			Allergens:  []models.Allergen{match.Allergen},
+		})
+	}
+	return warnings, nil
+}
+
+// UserAllergens returns the allergens saved on a user's profile.
+func UserAllergens(db *gorm.DB, userID uuid.UUID) ([]models.Allergen, error) {
+	var allergens []models.Allergen
+	err := db.Model(&models.UserAllergen{}).Where("user_id = ?", userID).Order("allergen").Pluck("allergen", &allergens).Error
+	return allergens, err
+}
+
+// SetUserAllergens replaces the allergens saved on a user's profile.
+func SetUserAllergens(tx *gorm.DB, userID uuid.UUID, names []string) ([]models.Allergen, error) {
+	set := map[models.Allergen]bool{}
+	for _, name := range names {
+		allergen := models.Allergen(name)
+		if !models.IsValidAllergen(allergen) {
+			return nil, &LabelError{Field: "allergens", Message: fmt.Sprintf("Unknown allergen %q", name)}
+		}
+		set[allergen] = true
+	}
+
+	allergens := make([]models.Allergen, 0, len(set))
+	for allergen := range set {
+		allergens = append(allergens, allergen)
+	}
+	sort.Slice(allergens, func(i, j int) bool { return allergens[i] < allergens[j] })
+
+	if err := tx.Where("user_id = ?", userID).Delete(&models.UserAllergen{}).Error; err != nil {
+		return nil, err
+	}
+	if len(allergens) > 0 {
+		rows := make([]models.UserAllergen, len(allergens))
+		for i, allergen := range allergens {
+			rows[i] = models.UserAllergen{UserID: userID, Allergen: allergen}
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			return nil, err
+		}
+	}
+	return allergens, nil
+}
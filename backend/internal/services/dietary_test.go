@@ -0,0 +1,76 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"restaurantapp/internal/models"
+)
+
+func TestParseMenuItemLabels(t *testing.T) {
+	tests := []struct {
+		name       string
+		allergens  []string
+		tags       []string
+		spiceLevel int
+		want       MenuItemLabels
+		errField   string
+	}{
+		{name: "no labels", want: MenuItemLabels{}},
+		{
+			name:       "sorted and deduplicated",
+			allergens:  []string{"sesame", "eggs", "sesame"},
+			tags:       []string{"halal", "vegetarian", "halal"},
+			spiceLevel: 2,
+			want: MenuItemLabels{
+				Allergens:   []models.Allergen{models.EggsAllergen, models.SesameAllergen},
+				DietaryTags: []models.DietaryTag{models.VegetarianTag, models.HalalTag},
+				SpiceLevel:  2,
+			},
+		},
+		{
+			name:      "wheat adds gluten",
+			allergens: []string{"wheat"},
+			want:      MenuItemLabels{Allergens: []models.Allergen{models.GlutenAllergen, models.WheatAllergen}},
+		},
+		{
+			name: "vegan adds vegetarian",
+			tags: []string{"vegan"},
+			want: MenuItemLabels{DietaryTags: []models.DietaryTag{models.VeganTag, models.VegetarianTag}},
+		},
+		{
+			name:      "vegan with an allergen it allows",
+			allergens: []string{"soy"},
+			tags:      []string{"vegan"},
+			want: MenuItemLabels{
+				Allergens:   []models.Allergen{models.SoyAllergen},
+				DietaryTags: []models.DietaryTag{models.VeganTag, models.VegetarianTag},
+			},
+		},
+		{name: "unknown allergen", allergens: []string{"nuts"}, errField: "allergens"},
+		{name: "unknown tag", tags: []string{"keto"}, errField: "dietaryTags"},
+		{name: "vegan with milk", allergens: []string{"milk"}, tags: []string{"vegan"}, errField: "dietaryTags"},
+		{name: "vegan with fish via vegetarian", allergens: []string{"fish"}, tags: []string{"vegan"}, errField: "dietaryTags"},
+		{name: "gluten free with wheat", allergens: []string{"wheat"}, tags: []string{"gluten_free"}, errField: "dietaryTags"},
+		{name: "spice too hot", spiceLevel: models.MaxSpiceLevel + 1, errField: "spiceLevel"},
+		{name: "negative spice", spiceLevel: -1, errField: "spiceLevel"},
+	}
+	for _, tt := range tests {
+		got, err := ParseMenuItemLabels(tt.allergens, tt.tags, tt.spiceLevel)
+		if tt.errField != "" {
+			var labelErr *LabelError
+			if !errors.As(err, &labelErr) || labelErr.Field != tt.errField {
+				t.Errorf("%s: err = %v, want a LabelError on %s", tt.name, err, tt.errField)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
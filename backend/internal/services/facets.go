@@ -0,0 +1,224 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"restaurantapp/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Search facets, named after the filter each one counts the options of.
+const (
+	CuisineFacet     = "cuisine"
+	PriceFacet       = "maxPrice"
+	RatingFacet      = "minRating"
+	DeliveryFeeFacet = "deliveryFee"
+	OpenFacet        = "isOpen"
+)
+
+// RatingBuckets and DeliveryFeeBuckets are the thresholds the rating and
+// delivery-fee facets count restaurants at or beyond, matching the minRating
+// and maxDeliveryFee filters.
+var (
+	RatingBuckets      = []float64{4.5, 4, 3.5, 3}
+	DeliveryFeeBuckets = []float64{0, 2, 5}
+)
+
+// DietaryAllergens maps each allergen-free dietary filter to the allergens a
+// dish must not contain to suit it.
+var DietaryAllergens = map[string][]models.Allergen{
+	"gluten_free":    {models.GlutenAllergen, models.WheatAllergen},
+	"dairy_free":     {models.MilkAllergen},
+	"egg_free":       {models.EggsAllergen},
+	"nut_free":       {models.PeanutsAllergen, models.TreeNutsAllergen},
+	"soy_free":       {models.SoyAllergen},
+	"fish_free":      {models.FishAllergen},
+	"shellfish_free": {models.CrustaceansAllergen, models.MolluscsAllergen},
+}
+
+// DietaryTagFilters maps the other dietary filters to the tag a dish must
+// carry.
+var DietaryTagFilters = map[string]models.DietaryTag{
+	"vegan":      models.VeganTag,
+	"vegetarian": models.VegetarianTag,
+	"halal":      models.HalalTag,
+}
+
+// IsValidDietaryFilter reports whether name is one of DietaryAllergens or
+// DietaryTagFilters.
+func IsValidDietaryFilter(name string) bool {
+	_, ok := DietaryAllergens[name]
+	if !ok {
+		_, ok = DietaryTagFilters[name]
+	}
+	return ok
+}
+
+// DietaryFilters lists the dietary filter names in alphabetical order.
+func DietaryFilters() []string {
+	names := make([]string, 0, len(DietaryAllergens)+len(DietaryTagFilters))
+	for name := range DietaryAllergens {
+		names = append(names, name)
+	}
+	for name := range DietaryTagFilters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RestaurantFilters are the search filters besides the text query. Zero
+// values don't filter.
+type RestaurantFilters struct {
+	Cuisines       []string
+	MinRating      float64
+	MaxPrice       int
+	MaxDeliveryFee *float64
+	IsOpen         *bool
+	Dietary        []string
+}
+
+// Apply adds every filter except the one for the except facet to a
+// restaurants query; an empty except applies them all.
+func (f RestaurantFilters) Apply(db *gorm.DB, except string) *gorm.DB {
+	// Cuisines are multi-select: a restaurant matches any of them. They match
+	// whole cuisines, ignoring case, as the cuisine facet counts them
+	if len(f.Cuisines) > 0 && except != CuisineFacet {
+		cuisines := make([]string, len(f.Cuisines))
+		for i, cuisine := range f.Cuisines {
+			cuisines[i] = strings.ToLower(cuisine)
+		}
+		db = db.Where("LOWER(cuisine_type) IN ?", cuisines)
+	}
+	if f.MinRating > 0 && except != RatingFacet {
+		db = db.Where("rating >= ?", f.MinRating)
+	}
+	if f.MaxPrice > 0 && except != PriceFacet {
+		db = db.Where("price_range <= ?", f.MaxPrice)
+	}
+	if f.MaxDeliveryFee != nil && except != DeliveryFeeFacet {
+		db = db.Where("delivery_fee <= ?", *f.MaxDeliveryFee)
+	}
+	if f.IsOpen != nil && except != OpenFacet {
+		db = db.Where("is_open = ?", *f.IsOpen)
+	}
+
+	// Dietary filters need one available dish that suits all of them
+	if len(f.Dietary) > 0 {
+		var excluded []models.Allergen
+		conditions := []string{"mi.restaurant_id = restaurants.id", "mi.is_available"}
+		var args []interface{}
+		for _, name := range f.Dietary {
+			excluded = append(excluded, DietaryAllergens[name]...)
+			if tag, ok := DietaryTagFilters[name]; ok {
+				conditions = append(conditions, "EXISTS (SELECT 1 FROM menu_item_dietary_tags t WHERE t.menu_item_id = mi.id AND t.tag = ?)")
+				args = append(args, tag)
+			}
+		}
+		if len(excluded) > 0 {
+			conditions = append(conditions, "NOT EXISTS (SELECT 1 FROM menu_item_allergens a WHERE a.menu_item_id = mi.id AND a.allergen IN ?)")
+			args = append(args, excluded)
+		}
+		db = db.Where("EXISTS (SELECT 1 FROM menu_items mi WHERE "+strings.Join(conditions, " AND ")+")", args...)
+	}
+	return db
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// SearchFacets counts the restaurants each filter option would return. Every
+// facet is counted with the other filters applied but not its own, so
+// selecting an option shows what switching to another would give. Rating and
+// delivery-fee counts are cumulative, like the filters.
+type SearchFacets struct {
+	Cuisine     []FacetCount `json:"cuisine"`
+	PriceRange  []FacetCount `json:"priceRange"`
+	Rating      []FacetCount `json:"rating"`
+	DeliveryFee []FacetCount `json:"deliveryFee"`
+	IsOpen      []FacetCount `json:"isOpen"`
+}
+
+// CountSearchFacets counts the facets of a search. base returns a new
+// restaurants query with the conditions every facet shares, such as the text
+// query.
+func CountSearchFacets(base func() *gorm.DB, filters RestaurantFilters) (SearchFacets, error) {
+	facets := SearchFacets{}
+
+	if err := filters.Apply(base(), CuisineFacet).
+		Select("MIN(cuisine_type) AS value, COUNT(*) AS count").
+		Group("LOWER(cuisine_type)").
+		Order("count DESC, value").
+		Scan(&facets.Cuisine).Error; err != nil {
+		return facets, err
+	}
+
+	if err := filters.Apply(base(), PriceFacet).
+		Select("CAST(price_range AS text) AS value, COUNT(*) AS count").
+		Group("price_range").
+		Order("price_range").
+		Scan(&facets.PriceRange).Error; err != nil {
+		return facets, err
+	}
+
+	ratingConditions := make([]string, len(RatingBuckets))
+	for i, rating := range RatingBuckets {
+		ratingConditions[i] = fmt.Sprintf("rating >= %g", rating)
+	}
+	counts, err := countEach(filters.Apply(base(), RatingFacet), ratingConditions)
+	if err != nil {
+		return facets, err
+	}
+	for i, rating := range RatingBuckets {
+		facets.Rating = append(facets.Rating, FacetCount{Value: fmt.Sprintf("%g", rating), Count: counts[i]})
+	}
+
+	feeConditions := make([]string, len(DeliveryFeeBuckets))
+	for i, fee := range DeliveryFeeBuckets {
+		feeConditions[i] = fmt.Sprintf("delivery_fee <= %g", fee)
+	}
+	counts, err = countEach(filters.Apply(base(), DeliveryFeeFacet), feeConditions)
+	if err != nil {
+		return facets, err
+	}
+	for i, fee := range DeliveryFeeBuckets {
+		facets.DeliveryFee = append(facets.DeliveryFee, FacetCount{Value: fmt.Sprintf("%g", fee), Count: counts[i]})
+	}
+
+	counts, err = countEach(filters.Apply(base(), OpenFacet), []string{"is_open", "NOT is_open"})
+	if err != nil {
+		return facets, err
+	}
+	facets.IsOpen = []FacetCount{
+		{Value: "true", Count: counts[0]},
+		{Value: "false", Count: counts[1]},
+	}
+
+	if facets.Cuisine == nil {
+		facets.Cuisine = []FacetCount{}
+	}
+	if facets.PriceRange == nil {
+		facets.PriceRange = []FacetCount{}
+	}
+	return facets, nil
+}
+
+// countEach counts the rows of a query matching each condition, in one scan.
+func countEach(db *gorm.DB, conditions []string) ([]int64, error) {
+	columns := make([]string, len(conditions))
+	counts := make([]int64, len(conditions))
+	dest := make([]interface{}, len(conditions))
+	for i, condition := range conditions {
+		columns[i] = "COUNT(*) FILTER (WHERE " + condition + ")"
+		dest[i] = &counts[i]
+	}
+	if err := db.Select(strings.Join(columns, ", ")).Row().Scan(dest...); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
@@ -0,0 +1,17 @@
+package services
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// NewPickupCode returns the four-digit code a customer shows at the counter
+// to collect a pickup order.
+func NewPickupCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(10000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%04d", n.Int64()), nil
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"math"
+	"sort"
+	"strings"
+
+	"restaurantapp/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// NewInviteCode returns a random, URL-safe code for a group order invite link.
+func NewInviteCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf)), nil
+}
+
+// CostShare is what one participant owes for a group order. Points is their
+// part of what the host paid in loyalty points.
+type CostShare struct {
+	UserID      uuid.UUID `json:"userId"`
+	Name        string    `json:"name"`
+	Subtotal    float64   `json:"subtotal"`
+	Discount    float64   `json:"discount"`
+	DeliveryFee float64   `json:"deliveryFee"`
+	Tax         float64   `json:"tax"`
+	Tip         float64   `json:"tip"`
+	Points      float64   `json:"points"`
+	Total       float64   `json:"total"`
+}
+
+// SplitOrderCost divides a group order between the participants who added its
+// items. Each pays for their own items; discount, delivery fee, tax and tip
+// are shared in proportion to item subtotals, and so is what the host paid in
+// loyalty points, so the totals add up to the amount charged. Amounts are in
+// whole cents and add up exactly to the order's figures. Items without a
+// participant are charged to the host.
+func SplitOrderCost(order *models.Order, hostID uuid.UUID, names map[uuid.UUID]string) []CostShare {
+	subtotals := make(map[uuid.UUID]float64)
+	for _, item := range order.Items {
+		userID := hostID
+		if item.AddedByID != nil {
+			userID = *item.AddedByID
+		}
+		subtotals[userID] += item.Price * float64(item.Quantity)
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(subtotals))
+	for userID := range subtotals {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool {
+		if userIDs[i] == hostID || userIDs[j] == hostID {
+			return userIDs[i] == hostID
+		}
+		return names[userIDs[i]] < names[userIDs[j]]
+	})
+
+	weights := make([]float64, len(userIDs))
+	for i, userID := range userIDs {
+		weights[i] = subtotals[userID]
+	}
+
+	itemShares := allocateCents(order.TotalAmount, weights)
+	discounts := allocateCents(order.DiscountAmount, weights)
+	fees := allocateCents(order.DeliveryFee, weights)
+	taxes := allocateCents(order.Tax, weights)
+	tips := allocateCents(order.Tip, weights)
+	points := allocateCents(order.PointsAmount, weights)
+
+	shares := make([]CostShare, len(userIDs))
+	for i, userID := range userIDs {
+		shares[i] = CostShare{
+			UserID:      userID,
+			Name:        names[userID],
+			Subtotal:    itemShares[i],
+			Discount:    discounts[i],
+			DeliveryFee: fees[i],
+			Tax:         taxes[i],
+			Tip:         tips[i],
+			Points:      points[i],
+		}
+		shares[i].Total = roundAmount(itemShares[i] - discounts[i] + fees[i] + taxes[i] + tips[i] - points[i])
+	}
+	return shares
+}
+
+// allocateCents splits amount in proportion to weights, rounding to cents with
+// the largest-remainder method so the parts add up to the rounded amount.
+func allocateCents(amount float64, weights []float64) []float64 {
+	parts := make([]float64, len(weights))
+	if len(weights) == 0 {
+		return parts
+	}
+
+	var totalWeight float64
+	for _, w := range weights {
+		totalWeight += w
+	}
+
+	cents := int64(math.Round(amount * 100))
+	raw := make([]float64, len(weights))
+	allocated := make([]int64, len(weights))
+	var sum int64
+	for i, w := range weights {
+		if totalWeight > 0 {
+			raw[i] = float64(cents) * w / totalWeight
+		} else {
+			raw[i] = float64(cents) / float64(len(weights))
+		}
+		allocated[i] = int64(math.Floor(raw[i]))
+		sum += allocated[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return raw[order[a]]-float64(allocated[order[a]]) > raw[order[b]]-float64(allocated[order[b]])
+	})
+	for i := 0; sum < cents; i++ {
+		allocated[order[i%len(order)]]++
+		sum++
+	}
+
+	for i := range parts {
+		parts[i] = float64(allocated[i]) / 100
+	}
+	return parts
+}
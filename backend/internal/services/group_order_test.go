@@ -0,0 +1,99 @@
+package services
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"restaurantapp/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestAllocateCents(t *testing.T) {
+	tests := []struct {
+		amount  float64
+		weights []float64
+		want    []float64
+	}{
+		{10, nil, []float64{}},
+		{10, []float64{1}, []float64{10}},
+		{10, []float64{1, 1}, []float64{5, 5}},
+		{10, []float64{1, 1, 1}, []float64{3.34, 3.33, 3.33}},
+		{0.05, []float64{1, 1, 1}, []float64{0.02, 0.02, 0.01}},
+		{1, []float64{2, 1}, []float64{0.67, 0.33}},
+		{1, []float64{1, 2}, []float64{0.33, 0.67}},
+		{7.5, []float64{12.99, 7.01}, []float64{4.87, 2.63}},
+		{6, []float64{0, 0}, []float64{3, 3}},
+		{0, []float64{1, 2}, []float64{0, 0}},
+	}
+	for _, tt := range tests {
+		got := allocateCents(tt.amount, tt.weights)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("allocateCents(%v, %v) = %v, want %v", tt.amount, tt.weights, got, tt.want)
+		}
+	}
+}
+
+func TestSplitOrderCost(t *testing.T) {
+	host, ana, ben := uuid.New(), uuid.New(), uuid.New()
+	names := map[uuid.UUID]string{host: "Host", ana: "Ana", ben: "Ben"}
+
+	order := &models.Order{
+		Items: []models.OrderItem{
+			{Price: 5, Quantity: 2},
+			{Price: 10, Quantity: 1, AddedByID: &ana},
+			{Price: 3.33, Quantity: 3, AddedByID: &ben},
+		},
+		TotalAmount:    29.99,
+		DiscountAmount: 3,
+		DeliveryFee:    2.99,
+		Tax:            2.4,
+		Tip:            1,
+		PointsAmount:   5,
+	}
+
+	shares := SplitOrderCost(order, host, names)
+	if len(shares) != 3 {
+		t.Fatalf("got %d shares, want 3", len(shares))
+	}
+	for i, want := range []uuid.UUID{host, ana, ben} {
+		if shares[i].UserID != want || shares[i].Name != names[want] {
+			t.Errorf("share %d is %s, want %s first, then by name", i, shares[i].Name, names[want])
+		}
+	}
+
+	for _, share := range shares {
+		if want := 10.0; share.UserID == host && share.Subtotal != want {
+			t.Errorf("host subtotal = %v, want %v", share.Subtotal, want)
+		}
+		if want := 9.99; share.UserID == ben && share.Subtotal != want {
+			t.Errorf("Ben subtotal = %v, want %v", share.Subtotal, want)
+		}
+	}
+
+	sums := map[string]float64{}
+	for _, share := range shares {
+		sums["subtotal"] += share.Subtotal
+		sums["discount"] += share.Discount
+		sums["deliveryFee"] += share.DeliveryFee
+		sums["tax"] += share.Tax
+		sums["tip"] += share.Tip
+		sums["points"] += share.Points
+		sums["total"] += share.Total
+	}
+	wants := map[string]float64{
+		"subtotal":    order.TotalAmount,
+		"discount":    order.DiscountAmount,
+		"deliveryFee": order.DeliveryFee,
+		"tax":         order.Tax,
+		"tip":         order.Tip,
+		"points":      order.PointsAmount,
+		"total":       order.AmountDue(),
+	}
+	for field, want := range wants {
+		if math.Abs(sums[field]-want) > 0.001 {
+			t.Errorf("shares' %s add up to %v, want %v", field, sums[field], want)
+		}
+	}
+}
@@ -0,0 +1,552 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StockError reports an order for more than is left in stock, or a stock
+// level that doesn't make sense. Its message is safe to show.
+type StockError struct {
+	Message string
+}
+
+func (e *StockError) Error() string {
+	return e.Message
+}
+
+type stockLine struct {
+	menuItemID uuid.UUID
+	name       string
+	quantity   int
+}
+
+// StockTake totals what an order takes from stock, per menu item and per
+// customization option.
+type StockTake struct {
+	items   map[uuid.UUID]*stockLine
+	options map[uuid.UUID]*stockLine
+}
+
+func NewStockTake() *StockTake {
+	return &StockTake{
+		items:   map[uuid.UUID]*stockLine{},
+		options: map[uuid.UUID]*stockLine{},
+	}
+}
+
+// Add counts quantity of a priced item and each option selected on it, and
+// of the components of a combo.
+func (st *StockTake) Add(priced *PricedItem, quantity int) {
+	for _, component := range priced.Components {
+		st.Add(component.Item, quantity)
+	}
+
+	item := priced.MenuItem
+	if st.items[item.ID] == nil {
+		st.items[item.ID] = &stockLine{menuItemID: item.ID, name: item.Name}
+	}
+	st.items[item.ID].quantity += quantity
+
+	for _, customization := range priced.Customizations {
+		for _, option := range customization.Options {
+			if st.options[option.ID] == nil {
+				st.options[option.ID] = &stockLine{menuItemID: item.ID, name: item.Name + ": " + option.Name}
+			}
+			st.options[option.ID].quantity += quantity
+		}
+	}
+}
+
+func sortedStockIDs(lines map[uuid.UUID]*stockLine) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(lines))
+	for id := range lines {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+	return ids
+}
+
+// TakeStock takes an order's items and options from their tracked stock. Rows
+// are locked items first, then options, each in ID order, so concurrent
+// orders wait for each other instead of overselling or deadlocking. What runs
+// out becomes unavailable, and crossing the low-stock threshold alerts the
+// restaurant.
+func TakeStock(tx *gorm.DB, order *models.Order, take *StockTake) error {
+	if ids := sortedStockIDs(take.items); len(ids) > 0 {
+		var items []models.MenuItem
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND stock_count IS NOT NULL", ids).
+			Order("id").
+			Find(&items).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			line := take.items[items[i].ID]
+			movement := models.StockMovement{MenuItemID: &items[i].ID}
+			if err := takeFromStock(tx, &models.MenuItem{}, items[i].ID, &items[i].Stock, order, line, nil, movement); err != nil {
+				return err
+			}
+		}
+	}
+
+	if ids := sortedStockIDs(take.options); len(ids) > 0 {
+		var options []models.CustomizationOption
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND stock_count IS NOT NULL", ids).
+			Order("id").
+			Find(&options).Error; err != nil {
+			return err
+		}
+		for i := range options {
+			line := take.options[options[i].ID]
+			movement := models.StockMovement{OptionID: &options[i].ID}
+			if err := takeFromStock(tx, &models.CustomizationOption{}, options[i].ID, &options[i].Stock, order, line, &options[i].ID, movement); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// takeFromStock decrements one locked row of model and records the movement.
+func takeFromStock(tx *gorm.DB, model interface{}, id uuid.UUID, stock *models.Stock, order *models.Order, line *stockLine, optionID *uuid.UUID, movement models.StockMovement) error {
+	before := *stock.StockCount
+	if before < line.quantity {
+		if before == 0 {
+			return &StockError{Message: line.name + " is sold out"}
+		}
+		return &StockError{Message: fmt.Sprintf("Only %d left of %s", before, line.name)}
+	}
+
+	after := before - line.quantity
+	updates := map[string]interface{}{"stock_count": after}
+	if after == 0 {
+		updates["is_available"] = false
+		updates["sold_out_at"] = time.Now()
+	}
+	if err := tx.Model(model).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	movement.RestaurantID = order.RestaurantID
+	movement.OrderID = &order.ID
+	movement.Reason = models.StockSold
+	movement.Change = -line.quantity
+	movement.StockAfter = after
+	if err := tx.Create(&movement).Error; err != nil {
+		return err
+	}
+
+	if before <= stock.LowStockThreshold || after > stock.LowStockThreshold {
+		return nil
+	}
+	return PublishEvent(tx, "restaurant", order.RestaurantID, models.MenuLowStockEvent, models.StockEventPayload{
+		RestaurantID:      order.RestaurantID,
+		MenuItemID:        line.menuItemID,
+		OptionID:          optionID,
+		Name:              line.name,
+		StockCount:        after,
+		LowStockThreshold: stock.LowStockThreshold,
+		OccurredAt:        time.Now().UTC(),
+	})
+}
+
+// RestoreStock gives back what a cancelled order took from stock. Stock taken
+// before the restaurant's last daily reset isn't given back, as the reset
+// already refilled it, and neither is stock that is no longer tracked.
+// Restoring twice does nothing.
+func RestoreStock(tx *gorm.DB, orderID uuid.UUID) error {
+	var movements []models.StockMovement
+	if err := tx.Where("order_id = ?", orderID).
+		Order("menu_item_id NULLS LAST, option_id").
+		Find(&movements).Error; err != nil {
+		return err
+	}
+	for _, movement := range movements {
+		if movement.Reason == models.StockRestored {
+			return nil
+		}
+	}
+	if len(movements) == 0 {
+		return nil
+	}
+
+	var restaurant models.Restaurant
+	if err := tx.Select("id", "stock_reset_at").Where("id = ?", movements[0].RestaurantID).First(&restaurant).Error; err != nil {
+		return err
+	}
+
+	for _, movement := range movements {
+		if movement.Reason != models.StockSold {
+			continue
+		}
+		if restaurant.StockResetAt != nil && movement.CreatedAt.Before(*restaurant.StockResetAt) {
+			continue
+		}
+
+		var model interface{}
+		var stock *models.Stock
+		var id uuid.UUID
+		if movement.MenuItemID != nil {
+			var item models.MenuItem
+			model, stock, id = &item, &item.Stock, *movement.MenuItemID
+		} else {
+			var option models.CustomizationOption
+			model, stock, id = &option, &option.Stock, *movement.OptionID
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(model).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				continue
+			}
+			return err
+		}
+		if !stock.IsTracked() {
+			continue
+		}
+
+		after := *stock.StockCount - movement.Change
+		updates := map[string]interface{}{"stock_count": after}
+		if stock.SoldOutAt != nil {
+			updates["is_available"] = true
+			updates["sold_out_at"] = nil
+		}
+		if err := tx.Model(model).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.StockMovement{
+			RestaurantID: movement.RestaurantID,
+			MenuItemID:   movement.MenuItemID,
+			OptionID:     movement.OptionID,
+			OrderID:      movement.OrderID,
+			Reason:       models.StockRestored,
+			Change:       -movement.Change,
+			StockAfter:   after,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StockLevel is what a restaurant sets the stock of an item or option to. A
+// nil StockCount stops tracking it; a nil DailyStock doesn't reset it.
+type StockLevel struct {
+	StockCount        *int
+	DailyStock        *int
+	LowStockThreshold int
+}
+
+// SetMenuItemStock sets the stock of one of the restaurant's menu items.
+func SetMenuItemStock(tx *gorm.DB, restaurantID, menuItemID uuid.UUID, level StockLevel) (*InventoryEntry, error) {
+	var item models.MenuItem
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND restaurant_id = ?", menuItemID, restaurantID).
+		First(&item).Error; err != nil {
+		return nil, err
+	}
+	movement := models.StockMovement{RestaurantID: restaurantID, MenuItemID: &item.ID}
+	if err := setStock(tx, &item, &item.Stock, &item.IsAvailable, level, movement); err != nil {
+		return nil, err
+	}
+	return &InventoryEntry{
+		MenuItemID:  item.ID,
+		Name:        item.Name,
+		IsAvailable: item.IsAvailable,
+		Stock:       item.Stock,
+		IsLow:       item.IsLow(),
+	}, nil
+}
+
+// SetOptionStock sets the stock of a customization option on one of the
+// restaurant's menu items.
+func SetOptionStock(tx *gorm.DB, restaurantID, optionID uuid.UUID, level StockLevel) (*InventoryEntry, error) {
+	var option models.CustomizationOption
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "customization_options"}}).
+		Joins("JOIN menu_customizations ON menu_customizations.id = customization_options.customization_id").
+		Joins("JOIN menu_items ON menu_items.id = menu_customizations.menu_item_id").
+		Where("customization_options.id = ? AND menu_items.restaurant_id = ?", optionID, restaurantID).
+		First(&option).Error; err != nil {
+		return nil, err
+	}
+	movement := models.StockMovement{RestaurantID: restaurantID, OptionID: &option.ID}
+	if err := setStock(tx, &option, &option.Stock, &option.IsAvailable, level, movement); err != nil {
+		return nil, err
+	}
+
+	var item models.MenuItem
+	if err := tx.Select("menu_items.id", "menu_items.name").
+		Joins("JOIN menu_customizations ON menu_customizations.menu_item_id = menu_items.id").
+		Where("menu_customizations.id = ?", option.CustomizationID).
+		First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &InventoryEntry{
+		MenuItemID:  item.ID,
+		OptionID:    &option.ID,
+		Name:        item.Name + ": " + option.Name,
+		IsAvailable: option.IsAvailable,
+		Stock:       option.Stock,
+		IsLow:       option.IsLow(),
+	}, nil
+}
+
+// setStock applies a stock level to a locked row of model, recording the
+// change. Running out or restocking switches availability the same way
+// orders do.
+func setStock(tx *gorm.DB, model interface{}, stock *models.Stock, isAvailable *bool, level StockLevel, movement models.StockMovement) error {
+	if level.DailyStock != nil && level.StockCount == nil {
+		return &StockError{Message: "Daily stock needs a stock count"}
+	}
+
+	before := 0
+	if stock.StockCount != nil {
+		before = *stock.StockCount
+	}
+	updates := map[string]interface{}{
+		"stock_count":         level.StockCount,
+		"daily_stock":         level.DailyStock,
+		"low_stock_threshold": level.LowStockThreshold,
+	}
+	stock.StockCount, stock.DailyStock, stock.LowStockThreshold = level.StockCount, level.DailyStock, level.LowStockThreshold
+
+	switch {
+	case level.StockCount != nil && *level.StockCount == 0 && *isAvailable:
+		now := time.Now()
+		updates["is_available"] = false
+		updates["sold_out_at"] = now
+		*isAvailable, stock.SoldOutAt = false, &now
+	case (level.StockCount == nil || *level.StockCount > 0) && stock.SoldOutAt != nil:
+		updates["is_available"] = true
+		updates["sold_out_at"] = nil
+		*isAvailable, stock.SoldOutAt = true, nil
+	}
+	if err := tx.Model(model).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	if level.StockCount == nil || *level.StockCount == before {
+		return nil
+	}
+	movement.Reason = models.StockAdjusted
+	movement.Change = *level.StockCount - before
+	movement.StockAfter = *level.StockCount
+	return tx.Create(&movement).Error
+}
+
+// InventoryEntry is the stock of a tracked menu item or option.
+type InventoryEntry struct {
+	MenuItemID  uuid.UUID  `json:"menuItemId"`
+	OptionID    *uuid.UUID `json:"optionId,omitempty"`
+	Name        string     `json:"name"`
+	IsAvailable bool       `json:"isAvailable"`
+	models.Stock
+	IsLow bool `json:"isLow"`
+}
+
+// ListInventory returns the restaurant's tracked items, then its tracked
+// options, by name; with lowOnly just those at or below their threshold.
+func ListInventory(db *gorm.DB, restaurantID uuid.UUID, lowOnly bool) ([]InventoryEntry, error) {
+	var items []models.MenuItem
+	if err := db.Where("restaurant_id = ? AND stock_count IS NOT NULL", restaurantID).
+		Order("name, id").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	var options []struct {
+		models.CustomizationOption
+		MenuItemID uuid.UUID
+		ItemName   string
+	}
+	if err := db.Model(&models.CustomizationOption{}).
+		Select("customization_options.*, menu_items.id AS menu_item_id, menu_items.name AS item_name").
+		Joins("JOIN menu_customizations ON menu_customizations.id = customization_options.customization_id").
+		Joins("JOIN menu_items ON menu_items.id = menu_customizations.menu_item_id").
+		Where("menu_items.restaurant_id = ? AND customization_options.stock_count IS NOT NULL", restaurantID).
+		Order("menu_items.name, customization_options.name, customization_options.id").
+		Scan(&options).Error; err != nil {
+		return nil, err
+	}
+
+	entries := []InventoryEntry{}
+	for i := range items {
+		if lowOnly && !items[i].IsLow() {
+			continue
+		}
+		entries = append(entries, InventoryEntry{
+			MenuItemID:  items[i].ID,
+			Name:        items[i].Name,
+			IsAvailable: items[i].IsAvailable,
+			Stock:       items[i].Stock,
+			IsLow:       items[i].IsLow(),
+		})
+	}
+	for i := range options {
+		if lowOnly && !options[i].IsLow() {
+			continue
+		}
+		entries = append(entries, InventoryEntry{
+			MenuItemID:  options[i].MenuItemID,
+			OptionID:    &options[i].ID,
+			Name:        options[i].ItemName + ": " + options[i].Name,
+			IsAvailable: options[i].IsAvailable,
+			Stock:       options[i].Stock,
+			IsLow:       options[i].IsLow(),
+		})
+	}
+	return entries, nil
+}
+
+// ResetDailyStock puts every item and option with a daily stock back to it,
+// making the ones that sold out available again.
+func ResetDailyStock(tx *gorm.DB, restaurantID uuid.UUID, now time.Time) error {
+	updates := map[string]interface{}{
+		"stock_count":  gorm.Expr("daily_stock"),
+		"is_available": gorm.Expr("is_available OR sold_out_at IS NOT NULL"),
+		"sold_out_at":  nil,
+	}
+	if err := tx.Model(&models.MenuItem{}).
+		Where("restaurant_id = ? AND daily_stock IS NOT NULL", restaurantID).
+		Updates(updates).Error; err != nil {
+		return err
+	}
+
+	customizations := tx.Model(&models.MenuCustomization{}).
+		Select("menu_customizations.id").
+		Joins("JOIN menu_items ON menu_items.id = menu_customizations.menu_item_id").
+		Where("menu_items.restaurant_id = ?", restaurantID)
+	if err := tx.Model(&models.CustomizationOption{}).
+		Where("daily_stock IS NOT NULL AND customization_id IN (?)", customizations).
+		Updates(updates).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&models.Restaurant{}).Where("id = ?", restaurantID).Update("stock_reset_at", now).Error
+}
+
+// stockResetDue reports whether the restaurant's daily stock reset time has
+// passed today, in its time zone, without a reset since.
+func stockResetDue(restaurant *models.Restaurant, now time.Time) bool {
+	if restaurant.StockResetTime == "" {
+		return false
+	}
+	local := now.In(restaurant.Location())
+	if local.Format("15:04") < restaurant.StockResetTime {
+		return false
+	}
+	if restaurant.StockResetAt == nil {
+		return true
+	}
+	return restaurant.StockResetAt.In(restaurant.Location()).Format("2006-01-02") < local.Format("2006-01-02")
+}
+
+// InventoryConsumer gives back the stock of cancelled orders.
+type InventoryConsumer struct{}
+
+func NewInventoryConsumer() *InventoryConsumer {
+	return &InventoryConsumer{}
+}
+
+func (ic *InventoryConsumer) Name() string {
+	return "inventory"
+}
+
+func (ic *InventoryConsumer) Handle(tx *gorm.DB, event *models.OutboxEvent) error {
+	if event.EventType != models.OrderStatusChangedEvent {
+		return nil
+	}
+
+	payload, err := decodeOrderEvent(event)
+	if err != nil {
+		return err
+	}
+	if payload.Status != models.CancelledStatus {
+		return nil
+	}
+	return RestoreStock(tx, payload.OrderID)
+}
+
+// StockResetter resets daily stock once a day at each restaurant's
+// StockResetTime.
+type StockResetter struct {
+	db  *repository.Database
+	cfg *config.MenuConfig
+}
+
+func NewStockResetter(db *repository.Database, cfg *config.Config) *StockResetter {
+	return &StockResetter{
+		db:  db,
+		cfg: &cfg.Menu,
+	}
+}
+
+// Start resets due stock until ctx is cancelled.
+func (r *StockResetter) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.StockResetInterval)
+	defer ticker.Stop()
+
+	for {
+		r.resetDue()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *StockResetter) resetDue() {
+	var restaurants []models.Restaurant
+	if err := r.db.DB.Select("id", "time_zone", "stock_reset_time", "stock_reset_at").
+		Where("stock_reset_time <> ''").
+		Find(&restaurants).Error; err != nil {
+		log.Printf("inventory: failed to find restaurants to reset: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for i := range restaurants {
+		if !stockResetDue(&restaurants[i], now) {
+			continue
+		}
+		if err := r.reset(restaurants[i].ID, now); err != nil {
+			log.Printf("inventory: failed to reset stock of restaurant %s: %v", restaurants[i].ID, err)
+		}
+	}
+}
+
+// reset resets one restaurant in its own transaction, checking again under
+// the lock in case another instance got there first.
+func (r *StockResetter) reset(restaurantID uuid.UUID, now time.Time) error {
+	return r.db.DB.Transaction(func(tx *gorm.DB) error {
+		var restaurant models.Restaurant
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Select("id", "time_zone", "stock_reset_time", "stock_reset_at").
+			Where("id = ?", restaurantID).
+			First(&restaurant).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil
+			}
+			return err
+		}
+		if !stockResetDue(&restaurant, now) {
+			return nil
+		}
+		return ResetDailyStock(tx, restaurant.ID, now)
+	})
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"restaurantapp/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// KitchenStages are the order statuses shown on the kitchen display, in the
+// order the tickets move through them.
+var KitchenStages = []models.OrderStatus{
+	models.PendingStatus,
+	models.ConfirmedStatus,
+	models.PreparingStatus,
+	models.ReadyForPickupStatus,
+}
+
+type KitchenTicketItem struct {
+	ID             uuid.UUID               `json:"id"`
+	MenuItemID     uuid.UUID               `json:"menuItemId"`
+	Name           string                  `json:"name"`
+	Quantity       int                     `json:"quantity"`
+	Customizations []SelectedCustomization `json:"customizations"`
+	// Components are what to cook for a combo
+	Components          []models.OrderItemComponent `json:"components,omitempty"`
+	SpecialInstructions string                      `json:"specialInstructions,omitempty"`
+	PrepStatus          models.ItemPrepStatus       `json:"prepStatus"`
+	PrepStartedAt       *time.Time                  `json:"prepStartedAt,omitempty"`
+	PrepDoneAt          *time.Time                  `json:"prepDoneAt,omitempty"`
+}
+
+// KitchenTicket is one order on the kitchen display.
+type KitchenTicket struct {
+	OrderID             uuid.UUID             `json:"orderId"`
+	OrderNumber         string                `json:"orderNumber"`
+	Status              models.OrderStatus    `json:"status"`
+	FulfilmentType      models.FulfilmentType `json:"fulfilmentType"`
+	TableNumber         string                `json:"tableNumber,omitempty"`
+	SpecialInstructions string                `json:"specialInstructions,omitempty"`
+	PlacedAt            time.Time             `json:"placedAt"`
+	PromisedAt          time.Time             `json:"promisedAt"`
+	MinutesLeft         int                   `json:"minutesLeft"`
+	AtRisk              bool                  `json:"atRisk"`
+	ItemsDone           int                   `json:"itemsDone"`
+	Items               []KitchenTicketItem   `json:"items"`
+}
+
+type KitchenStage struct {
+	Status  models.OrderStatus `json:"status"`
+	Count   int                `json:"count"`
+	Tickets []KitchenTicket    `json:"tickets"`
+}
+
+// KitchenItemCount is how many of one menu item are still to be cooked
+// across all open tickets.
+type KitchenItemCount struct {
+	MenuItemID uuid.UUID `json:"menuItemId"`
+	Name       string    `json:"name"`
+	Queued     int       `json:"queued"`
+	Started    int       `json:"started"`
+	Total      int       `json:"total"`
+}
+
+type KitchenQueue struct {
+	Stages      []KitchenStage     `json:"stages"`
+	ItemCounts  []KitchenItemCount `json:"itemCounts"`
+	AtRisk      int                `json:"atRisk"`
+	GeneratedAt time.Time          `json:"generatedAt"`
+}
+
+// BuildKitchenQueue groups a restaurant's active orders by stage, soonest
+// promised first. Orders need their Items with MenuItem loaded. A ticket is
+// at risk when the preparation left on it, plus margin, runs past its
+// promised time.
+func BuildKitchenQueue(restaurant *models.Restaurant, orders []models.Order, margin time.Duration, now time.Time) KitchenQueue {
+	queue := KitchenQueue{
+		Stages:      make([]KitchenStage, len(KitchenStages)),
+		ItemCounts:  []KitchenItemCount{},
+		GeneratedAt: now.UTC(),
+	}
+	stageIndex := make(map[models.OrderStatus]int, len(KitchenStages))
+	for i, status := range KitchenStages {
+		queue.Stages[i] = KitchenStage{Status: status, Tickets: []KitchenTicket{}}
+		stageIndex[status] = i
+	}
+
+	counts := make(map[uuid.UUID]*KitchenItemCount)
+	for i := range orders {
+		order := &orders[i]
+		index, ok := stageIndex[order.Status]
+		if !ok {
+			continue
+		}
+
+		ticket := newKitchenTicket(restaurant, order, margin, now)
+		if ticket.AtRisk {
+			queue.AtRisk++
+		}
+		queue.Stages[index].Tickets = append(queue.Stages[index].Tickets, ticket)
+
+		for _, item := range ticket.Items {
+			if item.PrepStatus == models.ItemPrepDone {
+				continue
+			}
+			// A combo is counted by what goes into it
+			cooked := []KitchenItemCount{{MenuItemID: item.MenuItemID, Name: item.Name}}
+			if len(item.Components) > 0 {
+				cooked = cooked[:0]
+				for _, component := range item.Components {
+					cooked = append(cooked, KitchenItemCount{MenuItemID: component.MenuItemID, Name: component.Name})
+				}
+			}
+			for _, c := range cooked {
+				count, ok := counts[c.MenuItemID]
+				if !ok {
+					count = &KitchenItemCount{MenuItemID: c.MenuItemID, Name: c.Name}
+					counts[c.MenuItemID] = count
+				}
+				if item.PrepStatus == models.ItemPrepStarted {
+					count.Started += item.Quantity
+				} else {
+					count.Queued += item.Quantity
+				}
+				count.Total += item.Quantity
+			}
+		}
+	}
+
+	for i := range queue.Stages {
+		tickets := queue.Stages[i].Tickets
+		sort.SliceStable(tickets, func(a, b int) bool {
+			if !tickets[a].PromisedAt.Equal(tickets[b].PromisedAt) {
+				return tickets[a].PromisedAt.Before(tickets[b].PromisedAt)
+			}
+			return tickets[a].PlacedAt.Before(tickets[b].PlacedAt)
+		})
+		queue.Stages[i].Count = len(tickets)
+	}
+
+	for _, count := range counts {
+		queue.ItemCounts = append(queue.ItemCounts, *count)
+	}
+	sort.Slice(queue.ItemCounts, func(a, b int) bool {
+		if queue.ItemCounts[a].Total != queue.ItemCounts[b].Total {
+			return queue.ItemCounts[a].Total > queue.ItemCounts[b].Total
+		}
+		return queue.ItemCounts[a].Name < queue.ItemCounts[b].Name
+	})
+
+	return queue
+}
+
+func newKitchenTicket(restaurant *models.Restaurant, order *models.Order, margin time.Duration, now time.Time) KitchenTicket {
+	promisedAt := order.CreatedAt.Add(time.Duration(restaurant.MaxDeliveryTime) * time.Minute)
+	if order.EstimatedDeliveryTime != nil {
+		promisedAt = *order.EstimatedDeliveryTime
+	}
+
+	ticket := KitchenTicket{
+		OrderID:             order.ID,
+		OrderNumber:         orderNumber(order),
+		Status:              order.Status,
+		FulfilmentType:      order.FulfilmentType,
+		TableNumber:         order.TableNumber,
+		SpecialInstructions: order.SpecialInstructions,
+		PlacedAt:            order.CreatedAt,
+		PromisedAt:          promisedAt,
+		MinutesLeft:         int(promisedAt.Sub(now).Minutes()),
+		Items:               []KitchenTicketItem{},
+	}
+
+	// The longest preparation still to do; started items have used some of it
+	var remaining time.Duration
+	for _, item := range order.Items {
+		ticket.Items = append(ticket.Items, NewKitchenTicketItem(&item))
+
+		if item.PrepStatus == models.ItemPrepDone {
+			ticket.ItemsDone++
+			continue
+		}
+		prep := time.Duration(item.MenuItem.PreparationTime) * time.Minute
+		if item.PrepStatus == models.ItemPrepStarted && item.PrepStartedAt != nil {
+			prep -= now.Sub(*item.PrepStartedAt)
+		}
+		if prep > remaining {
+			remaining = prep
+		}
+	}
+	if order.Status == models.ReadyForPickupStatus {
+		remaining = 0
+	}
+
+	ticket.AtRisk = now.Add(remaining + margin).After(promisedAt)
+	return ticket
+}
+
+func NewKitchenTicketItem(item *models.OrderItem) KitchenTicketItem {
+	return KitchenTicketItem{
+		ID:                  item.ID,
+		MenuItemID:          item.MenuItemID,
+		Name:                item.Name,
+		Quantity:            item.Quantity,
+		Customizations:      decodeCustomizations(item.CustomizationsData),
+		Components:          item.Components,
+		SpecialInstructions: item.SpecialInstructions,
+		PrepStatus:          item.PrepStatus,
+		PrepStartedAt:       item.PrepStartedAt,
+		PrepDoneAt:          item.PrepDoneAt,
+	}
+}
+
+// decodeCustomizations reads the resolved selections stored on an order item.
+// Free-form customization data that doesn't match is left out.
+func decodeCustomizations(data string) []SelectedCustomization {
+	customizations := []SelectedCustomization{}
+	if data == "" {
+		return customizations
+	}
+	if err := json.Unmarshal([]byte(data), &customizations); err != nil || customizations == nil {
+		return []SelectedCustomization{}
+	}
+	return customizations
+}
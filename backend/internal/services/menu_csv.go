@@ -0,0 +1,389 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"restaurantapp/internal/models"
+)
+
+// MenuCSVColumns are the columns of a menu CSV, one row per item. Only
+// category, name and price are required. A row with only category columns
+// adds an empty category. Lists (allergens, dietary tags) are separated by
+// commas or semicolons, and customizations are written
+//
+//	Size (size, required, max 1): Small=0 | Large=2.5; Extras (addon, max 2): Cheese=1 | Bacon=1.5
+var MenuCSVColumns = []string{
+	"category", "category_description", "category_order",
+	"sku", "name", "description", "price", "image", "preparation_time", "available",
+	"allergens", "dietary_tags", "spice_level",
+	"calories", "protein", "carbs", "fat", "fiber", "sodium",
+	"customizations",
+}
+
+var requiredMenuCSVColumns = []string{"category", "name", "price"}
+
+// itemCSVColumns are the columns that make a row an item rather than just a
+// category.
+var itemCSVColumns = MenuCSVColumns[3:]
+
+// ParseMenuCSV converts a menu CSV to an import. Rows are numbered by line,
+// the header being line 1. Values that don't parse are reported per row.
+func ParseMenuCSV(r io.Reader) (*MenuImport, []MenuImportError) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, []MenuImportError{{Line: 1, Message: "The file is empty"}}
+	}
+	if err != nil {
+		return nil, []MenuImportError{csvError(err)}
+	}
+
+	var errs []MenuImportError
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, duplicate := columns[name]; duplicate {
+			errs = append(errs, MenuImportError{Line: 1, Field: name, Message: "Column " + name + " appears more than once"})
+		}
+		columns[name] = i
+		if !isMenuCSVColumn(name) {
+			errs = append(errs, MenuImportError{Line: 1, Field: name, Message: "Unknown column " + name})
+		}
+	}
+	for _, name := range requiredMenuCSVColumns {
+		if _, ok := columns[name]; !ok {
+			errs = append(errs, MenuImportError{Line: 1, Field: name, Message: "Column " + name + " is required"})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	menu := &MenuImport{}
+	categoryIndex := map[string]int{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, append(errs, csvError(err))
+		}
+		line, _ := reader.FieldPos(0)
+
+		row := csvRow{record: record, columns: columns, line: line}
+		if row.blank(MenuCSVColumns) {
+			continue
+		}
+
+		categoryName := strings.TrimSpace(row.get("category"))
+		ci, seen := categoryIndex[categoryName]
+		if !seen || categoryName == "" {
+			category := ImportCategory{
+				Name:        categoryName,
+				Description: row.get("category_description"),
+				line:        line,
+			}
+			if value := row.get("category_order"); value != "" {
+				order, err := strconv.Atoi(value)
+				if err != nil {
+					row.fail("category_order", "Category order must be a whole number")
+				}
+				category.Order = &order
+			}
+			menu.Categories = append(menu.Categories, category)
+			ci = len(menu.Categories) - 1
+			categoryIndex[categoryName] = ci
+		}
+
+		if !row.blank(itemCSVColumns) {
+			item := row.item()
+			menu.Categories[ci].Items = append(menu.Categories[ci].Items, item)
+		}
+		errs = append(errs, row.errs...)
+	}
+
+	if len(menu.Categories) == 0 {
+		errs = append(errs, MenuImportError{Line: 2, Message: "The file has no rows"})
+	}
+	return menu, errs
+}
+
+func isMenuCSVColumn(name string) bool {
+	for _, column := range MenuCSVColumns {
+		if name == column {
+			return true
+		}
+	}
+	return false
+}
+
+func csvError(err error) MenuImportError {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return MenuImportError{Line: parseErr.Line, Message: parseErr.Err.Error()}
+	}
+	return MenuImportError{Message: err.Error()}
+}
+
+// csvRow reads the values of one CSV row, collecting the ones that don't
+// parse.
+type csvRow struct {
+	record  []string
+	columns map[string]int
+	line    int
+	errs    []MenuImportError
+}
+
+func (r *csvRow) has(column string) bool {
+	_, ok := r.columns[column]
+	return ok
+}
+
+// get returns the trimmed value of a column; short rows read as empty.
+func (r *csvRow) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+func (r *csvRow) blank(columns []string) bool {
+	for _, column := range columns {
+		if r.get(column) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *csvRow) fail(column, message string) {
+	r.errs = append(r.errs, MenuImportError{Line: r.line, Field: column, Message: message})
+}
+
+func (r *csvRow) float(column string) *float64 {
+	value := r.get(column)
+	if value == "" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		r.fail(column, "Not a number: "+value)
+		return nil
+	}
+	return &f
+}
+
+func (r *csvRow) int(column string) *int {
+	value := r.get(column)
+	if value == "" {
+		return nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		r.fail(column, "Not a whole number: "+value)
+		return nil
+	}
+	return &i
+}
+
+func (r *csvRow) list(column string) []string {
+	var values []string
+	for _, value := range strings.FieldsFunc(r.get(column), func(c rune) bool { return c == ',' || c == ';' }) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, strings.ToLower(value))
+		}
+	}
+	return values
+}
+
+func (r *csvRow) item() ImportItem {
+	item := ImportItem{
+		SKU:         r.get("sku"),
+		Name:        r.get("name"),
+		Description: r.get("description"),
+		Image:       r.get("image"),
+		Allergens:   r.list("allergens"),
+		DietaryTags: r.list("dietary_tags"),
+		Calories:    r.int("calories"),
+		Protein:     r.float("protein"),
+		Carbs:       r.float("carbs"),
+		Fat:         r.float("fat"),
+		Fiber:       r.float("fiber"),
+		Sodium:      r.float("sodium"),
+		line:        r.line,
+	}
+	if price := r.float("price"); price != nil {
+		item.Price = *price
+	}
+	if preparationTime := r.int("preparation_time"); preparationTime != nil {
+		item.PreparationTime = *preparationTime
+	}
+	if spiceLevel := r.int("spice_level"); spiceLevel != nil {
+		item.SpiceLevel = *spiceLevel
+	}
+	if value := r.get("available"); value != "" {
+		available, err := strconv.ParseBool(strings.ToLower(value))
+		if err != nil {
+			r.fail("available", "Available must be true or false")
+		}
+		item.IsAvailable = &available
+	}
+
+	// Without the column, customizations stay as they are; an empty cell
+	// removes them
+	if r.has("customizations") {
+		customizations, err := parseCustomizations(r.get("customizations"))
+		if err != nil {
+			r.fail("customizations", err.Error())
+		}
+		item.Customizations = customizations
+	}
+	return item
+}
+
+// parseCustomizations reads the customizations cell of a menu CSV.
+func parseCustomizations(cell string) ([]ImportCustomization, error) {
+	customizations := []ImportCustomization{}
+	for _, group := range strings.Split(cell, ";") {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			continue
+		}
+
+		colon := strings.Index(group, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("%s: list the options after a colon", group)
+		}
+		head, body := strings.TrimSpace(group[:colon]), group[colon+1:]
+		open := strings.LastIndex(head, "(")
+		if open < 0 || !strings.HasSuffix(head, ")") {
+			return nil, fmt.Errorf("%s: give the type in brackets after the name, like Size (size)", head)
+		}
+
+		customization := ImportCustomization{Name: strings.TrimSpace(head[:open])}
+		for _, flag := range strings.Split(head[open+1:len(head)-1], ",") {
+			flag = strings.ToLower(strings.TrimSpace(flag))
+			switch {
+			case models.IsValidCustomizationType(models.CustomizationType(flag)):
+				customization.Type = models.CustomizationType(flag)
+			case flag == "required":
+				customization.Required = true
+			case strings.HasPrefix(flag, "max"):
+				max, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(flag, "max")))
+				if err != nil {
+					return nil, fmt.Errorf("%s: max needs a number, like max 2", customization.Name)
+				}
+				customization.MaxSelections = max
+			default:
+				return nil, fmt.Errorf("%s: unknown setting %q", customization.Name, flag)
+			}
+		}
+
+		for _, option := range strings.Split(body, "|") {
+			option = strings.TrimSpace(option)
+			if option == "" {
+				continue
+			}
+			imported := ImportOption{Name: option}
+			if eq := strings.LastIndex(option, "="); eq >= 0 {
+				imported.Name = strings.TrimSpace(option[:eq])
+				price, err := strconv.ParseFloat(strings.TrimSpace(option[eq+1:]), 64)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %s needs a price modifier, like %s=1.50", customization.Name, imported.Name, imported.Name)
+				}
+				imported.PriceModifier = price
+			}
+			customization.Options = append(customization.Options, imported)
+		}
+		customizations = append(customizations, customization)
+	}
+	return customizations, nil
+}
+
+// formatCustomizations writes customizations the way parseCustomizations
+// reads them.
+func formatCustomizations(customizations []ImportCustomization) string {
+	groups := make([]string, len(customizations))
+	for i, customization := range customizations {
+		flags := []string{string(customization.Type)}
+		if customization.Required {
+			flags = append(flags, "required")
+		}
+		flags = append(flags, fmt.Sprintf("max %d", customization.MaxSelections))
+
+		options := make([]string, len(customization.Options))
+		for j, option := range customization.Options {
+			options[j] = option.Name + "=" + formatNumber(option.PriceModifier)
+		}
+		groups[i] = fmt.Sprintf("%s (%s): %s", customization.Name, strings.Join(flags, ", "), strings.Join(options, " | "))
+	}
+	return strings.Join(groups, "; ")
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func formatOptionalNumber(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return formatNumber(*f)
+}
+
+// WriteMenuCSV writes a menu in the format ParseMenuCSV reads.
+func WriteMenuCSV(w io.Writer, menu *MenuImport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(MenuCSVColumns); err != nil {
+		return err
+	}
+
+	for _, category := range menu.Categories {
+		order := ""
+		if category.Order != nil {
+			order = strconv.Itoa(*category.Order)
+		}
+		if len(category.Items) == 0 {
+			row := make([]string, len(MenuCSVColumns))
+			row[0], row[1], row[2] = category.Name, category.Description, order
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, item := range category.Items {
+			available, calories := "", ""
+			if item.IsAvailable != nil {
+				available = strconv.FormatBool(*item.IsAvailable)
+			}
+			if item.Calories != nil {
+				calories = strconv.Itoa(*item.Calories)
+			}
+			if err := writer.Write([]string{
+				category.Name, category.Description, order,
+				item.SKU, item.Name, item.Description, formatNumber(item.Price), item.Image,
+				strconv.Itoa(item.PreparationTime), available,
+				strings.Join(item.Allergens, ", "), strings.Join(item.DietaryTags, ", "), strconv.Itoa(item.SpiceLevel),
+				calories, formatOptionalNumber(item.Protein), formatOptionalNumber(item.Carbs),
+				formatOptionalNumber(item.Fat), formatOptionalNumber(item.Fiber), formatOptionalNumber(item.Sodium),
+				formatCustomizations(item.Customizations),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
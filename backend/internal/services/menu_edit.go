@@ -0,0 +1,352 @@
+package services
+
+import (
+	"fmt"
+	"math"
+
+	"restaurantapp/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MenuEditError reports a reorder, category deletion or bulk change that
+// can't be made as asked. Its message is safe to show.
+type MenuEditError struct {
+	Message string
+}
+
+func (e *MenuEditError) Error() string {
+	return e.Message
+}
+
+// NextMenuItemPosition is the position after the last item of a category,
+// where new and moved items go.
+func NextMenuItemPosition(tx *gorm.DB, categoryID uuid.UUID) (int, error) {
+	var next int
+	if err := tx.Model(&models.MenuItem{}).
+		Select("COALESCE(MAX(position) + 1, 0)").
+		Where("category_id = ?", categoryID).
+		Scan(&next).Error; err != nil {
+		return 0, err
+	}
+	return next, nil
+}
+
+// ReorderCategories puts the restaurant's categories in the order of ids,
+// which must list every one of them exactly once. The categories are locked
+// so concurrent reorders apply one after the other.
+func ReorderCategories(tx *gorm.DB, restaurantID uuid.UUID, ids []uuid.UUID) error {
+	var categories []models.MenuCategory
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("restaurant_id = ?", restaurantID).
+		Order("id").
+		Find(&categories).Error; err != nil {
+		return err
+	}
+	current := make([]uuid.UUID, len(categories))
+	for i, category := range categories {
+		current[i] = category.ID
+	}
+	if err := checkSameIDs(current, ids, "category"); err != nil {
+		return err
+	}
+
+	for i, id := range ids {
+		if err := tx.Model(&models.MenuCategory{}).Where("id = ?", id).Update("order", i).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReorderMenuItems puts the items of a restaurant's category in the order of
+// ids, which must list every item in it exactly once. It returns
+// gorm.ErrRecordNotFound if the category isn't the restaurant's.
+func ReorderMenuItems(tx *gorm.DB, restaurantID, categoryID uuid.UUID, ids []uuid.UUID) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND restaurant_id = ?", categoryID, restaurantID).
+		First(&models.MenuCategory{}).Error; err != nil {
+		return err
+	}
+
+	var items []models.MenuItem
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("category_id = ?", categoryID).
+		Order("id").
+		Find(&items).Error; err != nil {
+		return err
+	}
+	current := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		current[i] = item.ID
+	}
+	if err := checkSameIDs(current, ids, "menu item"); err != nil {
+		return err
+	}
+
+	for i, id := range ids {
+		if err := tx.Model(&models.MenuItem{}).Where("id = ?", id).Update("position", i).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSameIDs makes sure a reorder lists exactly what is there, so a
+// client working from a stale menu can't drop or invent entries.
+func checkSameIDs(current, ids []uuid.UUID, what string) error {
+	listed := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if listed[id] {
+			return &MenuEditError{Message: fmt.Sprintf("Each %s can only be listed once", what)}
+		}
+		listed[id] = true
+	}
+	for _, id := range current {
+		if !listed[id] {
+			return &MenuEditError{Message: fmt.Sprintf("Every %s has to be listed; reload the menu and try again", what)}
+		}
+	}
+	if len(ids) != len(current) {
+		return &MenuEditError{Message: fmt.Sprintf("Unknown %s listed; reload the menu and try again", what)}
+	}
+	return nil
+}
+
+// removedItemsCategoryName names the inactive category that keeps the items
+// of categories deleted along with them.
+const removedItemsCategoryName = "Removed items"
+
+// DeleteCategory deletes a restaurant's category. A category with items
+// needs somewhere for them to go: moveTo re-homes them at the end of another
+// of the restaurant's categories, while cascade takes them off the menu with
+// it. Those items are never deleted, since past orders refer to them; they
+// are made unavailable and kept in the restaurant's inactive "Removed items"
+// category, from where they can be moved back. It returns how many items were
+// moved or removed, and gorm.ErrRecordNotFound if the category isn't the
+// restaurant's.
+func DeleteCategory(tx *gorm.DB, restaurantID, categoryID uuid.UUID, moveTo *uuid.UUID, cascade bool) (int, error) {
+	var category models.MenuCategory
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND restaurant_id = ?", categoryID, restaurantID).
+		First(&category).Error; err != nil {
+		return 0, err
+	}
+	if moveTo != nil && cascade {
+		return 0, &MenuEditError{Message: "Either move the items or remove them, not both"}
+	}
+
+	var items []models.MenuItem
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("category_id = ?", categoryID).
+		Order("position, name, id").
+		Find(&items).Error; err != nil {
+		return 0, err
+	}
+
+	switch {
+	case len(items) == 0:
+	case moveTo != nil:
+		if *moveTo == categoryID {
+			return 0, &MenuEditError{Message: "Items can't be moved to the category being deleted"}
+		}
+		if err := moveMenuItems(tx, restaurantID, items, *moveTo); err != nil {
+			return 0, err
+		}
+	case cascade:
+		removedID, err := removedItemsCategory(tx, restaurantID, categoryID)
+		if err != nil {
+			return 0, err
+		}
+		if err := moveMenuItems(tx, restaurantID, items, removedID); err != nil {
+			return 0, err
+		}
+		ids := make([]uuid.UUID, len(items))
+		for i, item := range items {
+			ids[i] = item.ID
+		}
+		if err := tx.Model(&models.MenuItem{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{"is_available": false, "sold_out_at": nil}).Error; err != nil {
+			return 0, err
+		}
+	default:
+		return 0, &MenuEditError{Message: fmt.Sprintf("%s has %d items; move them to another category or remove them with it", category.Name, len(items))}
+	}
+
+	if err := tx.Delete(&category).Error; err != nil {
+		return 0, err
+	}
+	return len(items), nil
+}
+
+// removedItemsCategory returns the restaurant's inactive "Removed items"
+// category, creating it at the end of the menu if needed. except is the
+// category being deleted, which can't keep the items itself.
+func removedItemsCategory(tx *gorm.DB, restaurantID, except uuid.UUID) (uuid.UUID, error) {
+	var category models.MenuCategory
+	err := tx.Select("id").
+		Where("restaurant_id = ? AND name = ? AND is_active = ? AND id <> ?", restaurantID, removedItemsCategoryName, false, except).
+		First(&category).Error
+	if err == nil {
+		return category.ID, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return uuid.Nil, err
+	}
+
+	var order int
+	if err := tx.Model(&models.MenuCategory{}).
+		Select(`COALESCE(MAX("order") + 1, 0)`).
+		Where("restaurant_id = ?", restaurantID).
+		Scan(&order).Error; err != nil {
+		return uuid.Nil, err
+	}
+	category = models.MenuCategory{
+		RestaurantID: restaurantID,
+		Name:         removedItemsCategoryName,
+		Description:  "Items of deleted categories, off the menu",
+		Order:        order,
+	}
+	if err := tx.Create(&category).Error; err != nil {
+		return uuid.Nil, err
+	}
+	// IsActive defaults to true, so a false one isn't written by Create
+	if err := tx.Model(&category).Update("is_active", false).Error; err != nil {
+		return uuid.Nil, err
+	}
+	return category.ID, nil
+}
+
+// MaxBulkMenuItems is the most items one bulk change can touch.
+const MaxBulkMenuItems = 500
+
+// lockMenuItems loads and locks the restaurant's menu items with the given
+// IDs, in the order asked for.
+func lockMenuItems(tx *gorm.DB, restaurantID uuid.UUID, ids []uuid.UUID) ([]models.MenuItem, error) {
+	if len(ids) == 0 {
+		return nil, &MenuEditError{Message: "Choose at least one menu item"}
+	}
+	if len(ids) > MaxBulkMenuItems {
+		return nil, &MenuEditError{Message: fmt.Sprintf("At most %d menu items can be changed at once", MaxBulkMenuItems)}
+	}
+
+	var items []models.MenuItem
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ? AND restaurant_id = ?", ids, restaurantID).
+		Order("id").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]models.MenuItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	ordered := make([]models.MenuItem, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return nil, &MenuEditError{Message: "Each menu item can only be listed once"}
+		}
+		seen[id] = true
+		item, ok := byID[id]
+		if !ok {
+			return nil, &MenuEditError{Message: "Menu item " + id.String() + " not found"}
+		}
+		ordered = append(ordered, item)
+	}
+	return ordered, nil
+}
+
+// ChangeMenuPrices raises or lowers the prices of a restaurant's menu items
+// by percent, rounded to the cent and never below one cent. It returns the
+// updated items.
+func ChangeMenuPrices(tx *gorm.DB, restaurantID uuid.UUID, ids []uuid.UUID, percent float64) ([]models.MenuItem, error) {
+	if percent <= -100 {
+		return nil, &MenuEditError{Message: "Prices can't go down by 100% or more"}
+	}
+	items, err := lockMenuItems(tx, restaurantID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range items {
+		price := math.Round(items[i].Price*(100+percent)) / 100
+		if price < 0.01 {
+			price = 0.01
+		}
+		if err := tx.Model(&items[i]).Update("price", price).Error; err != nil {
+			return nil, err
+		}
+		items[i].Price = price
+	}
+	return items, nil
+}
+
+// MoveMenuItems moves a restaurant's menu items to the end of another of its
+// categories, keeping their order. It returns the updated items.
+func MoveMenuItems(tx *gorm.DB, restaurantID uuid.UUID, ids []uuid.UUID, categoryID uuid.UUID) ([]models.MenuItem, error) {
+	items, err := lockMenuItems(tx, restaurantID, ids)
+	if err != nil {
+		return nil, err
+	}
+	if err := moveMenuItems(tx, restaurantID, items, categoryID); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func moveMenuItems(tx *gorm.DB, restaurantID uuid.UUID, items []models.MenuItem, categoryID uuid.UUID) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND restaurant_id = ?", categoryID, restaurantID).
+		First(&models.MenuCategory{}).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &MenuEditError{Message: "Category to move to not found"}
+		}
+		return err
+	}
+
+	position, err := NextMenuItemPosition(tx, categoryID)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		if err := tx.Model(&items[i]).Updates(map[string]interface{}{
+			"category_id": categoryID,
+			"position":    position,
+		}).Error; err != nil {
+			return err
+		}
+		items[i].CategoryID = categoryID
+		items[i].Position = position
+		position++
+	}
+	return nil
+}
+
+// SetMenuItemsAvailability switches a restaurant's menu items on or off, as
+// toggling each one would. It returns the updated items.
+func SetMenuItemsAvailability(tx *gorm.DB, restaurantID uuid.UUID, ids []uuid.UUID, available bool) ([]models.MenuItem, error) {
+	items, err := lockMenuItems(tx, restaurantID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range items {
+		if err := tx.Model(&items[i]).Updates(map[string]interface{}{
+			"is_available": available,
+			"sold_out_at":  nil,
+		}).Error; err != nil {
+			return nil, err
+		}
+		items[i].IsAvailable = available
+		items[i].SoldOutAt = nil
+	}
+	return items, nil
+}
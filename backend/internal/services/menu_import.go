@@ -0,0 +1,638 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"restaurantapp/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MaxSKULength is the longest SKU a menu item can have.
+const MaxSKULength = 64
+
+// MenuImport is a menu in the structured format imports take and exports
+// produce. CSV imports are converted to it.
+type MenuImport struct {
+	Categories []ImportCategory `json:"categories"`
+}
+
+// ImportCategory is a category and its items, in menu order. Categories are
+// matched by name.
+type ImportCategory struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Order       *int         `json:"order,omitempty"`
+	Items       []ImportItem `json:"items"`
+
+	// line is the CSV line the category was first seen on
+	line int
+}
+
+// ImportItem is a menu item. Items are matched by SKU, then by name within
+// their category among items without a SKU. IsAvailable and Customizations
+// are left as they are when not given; every other field is set from the
+// import.
+type ImportItem struct {
+	SKU             string                `json:"sku,omitempty"`
+	Name            string                `json:"name"`
+	Description     string                `json:"description"`
+	Price           float64               `json:"price"`
+	Image           string                `json:"image,omitempty"`
+	PreparationTime int                   `json:"preparationTime,omitempty"`
+	IsAvailable     *bool                 `json:"isAvailable,omitempty"`
+	Allergens       []string              `json:"allergens,omitempty"`
+	DietaryTags     []string              `json:"dietaryTags,omitempty"`
+	SpiceLevel      int                   `json:"spiceLevel,omitempty"`
+	Calories        *int                  `json:"calories,omitempty"`
+	Protein         *float64              `json:"protein,omitempty"`
+	Carbs           *float64              `json:"carbs,omitempty"`
+	Fat             *float64              `json:"fat,omitempty"`
+	Fiber           *float64              `json:"fiber,omitempty"`
+	Sodium          *float64              `json:"sodium,omitempty"`
+	Customizations  []ImportCustomization `json:"customizations"`
+
+	// line is the CSV line of the item
+	line int
+}
+
+// ImportCustomization is a customization of an item and its options, matched
+// by name so carts referring to them keep working.
+type ImportCustomization struct {
+	Name          string                   `json:"name"`
+	Type          models.CustomizationType `json:"type"`
+	Required      bool                     `json:"required"`
+	MaxSelections int                      `json:"maxSelections"`
+	Options       []ImportOption           `json:"options"`
+}
+
+type ImportOption struct {
+	Name          string  `json:"name"`
+	PriceModifier float64 `json:"priceModifier"`
+}
+
+// MenuImportError is a problem with one row of an import: a CSV line, or the
+// path of the category or item in a JSON import.
+type MenuImportError struct {
+	Line    int    `json:"line,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// MenuImportSummary counts what an import creates and updates, and what
+// publishing a menu version hides.
+type MenuImportSummary struct {
+	CategoriesCreated int `json:"categoriesCreated"`
+	CategoriesUpdated int `json:"categoriesUpdated"`
+	CategoriesHidden  int `json:"categoriesHidden,omitempty"`
+	ItemsCreated      int `json:"itemsCreated"`
+	ItemsUpdated      int `json:"itemsUpdated"`
+	ItemsHidden       int `json:"itemsHidden,omitempty"`
+}
+
+func categoryError(ci int, category *ImportCategory, field, message string) MenuImportError {
+	err := MenuImportError{Field: field, Message: message}
+	if category.line > 0 {
+		err.Line = category.line
+	} else {
+		err.Path = fmt.Sprintf("categories[%d]", ci)
+	}
+	return err
+}
+
+func itemError(ci, ii int, item *ImportItem, field, message string) MenuImportError {
+	err := MenuImportError{Field: field, Message: message}
+	if item.line > 0 {
+		err.Line = item.line
+	} else {
+		err.Path = fmt.Sprintf("categories[%d].items[%d]", ci, ii)
+	}
+	return err
+}
+
+// ValidateMenuImport checks an import without looking at the current menu.
+func ValidateMenuImport(menu *MenuImport) []MenuImportError {
+	var errs []MenuImportError
+	categoryNames := map[string]bool{}
+	skus := map[string]bool{}
+
+	for ci := range menu.Categories {
+		category := &menu.Categories[ci]
+		category.Name = strings.TrimSpace(category.Name)
+		if category.Name == "" {
+			errs = append(errs, categoryError(ci, category, "category", "Category name is required"))
+		} else if categoryNames[category.Name] {
+			errs = append(errs, categoryError(ci, category, "category", "Category "+category.Name+" appears more than once"))
+		}
+		categoryNames[category.Name] = true
+
+		// Items without a SKU are matched by name, so their names can't repeat
+		unnamed := map[string]bool{}
+		for ii := range category.Items {
+			item := &category.Items[ii]
+			item.SKU = strings.TrimSpace(item.SKU)
+			item.Name = strings.TrimSpace(item.Name)
+
+			if item.Name == "" {
+				errs = append(errs, itemError(ci, ii, item, "name", "Name is required"))
+			}
+			if item.Price <= 0 {
+				errs = append(errs, itemError(ci, ii, item, "price", "Price must be greater than 0"))
+			}
+			if item.PreparationTime < 0 {
+				errs = append(errs, itemError(ci, ii, item, "preparationTime", "Preparation time can't be negative"))
+			}
+			switch {
+			case len(item.SKU) > MaxSKULength:
+				errs = append(errs, itemError(ci, ii, item, "sku", fmt.Sprintf("SKU can be at most %d characters", MaxSKULength)))
+			case item.SKU != "" && skus[item.SKU]:
+				errs = append(errs, itemError(ci, ii, item, "sku", "SKU "+item.SKU+" appears more than once"))
+			case item.SKU == "" && item.Name != "" && unnamed[item.Name]:
+				errs = append(errs, itemError(ci, ii, item, "name", "Items without a SKU need distinct names; "+item.Name+" appears more than once"))
+			}
+			if item.SKU != "" {
+				skus[item.SKU] = true
+			} else {
+				unnamed[item.Name] = true
+			}
+
+			if _, err := ParseMenuItemLabels(item.Allergens, item.DietaryTags, item.SpiceLevel); err != nil {
+				labelErr := err.(*LabelError)
+				errs = append(errs, itemError(ci, ii, item, labelErr.Field, labelErr.Message))
+			}
+			if item.Calories != nil && *item.Calories < 0 {
+				errs = append(errs, itemError(ci, ii, item, "calories", "Nutrition values can't be negative"))
+			}
+			nutrition := []struct {
+				field string
+				value *float64
+			}{{"protein", item.Protein}, {"carbs", item.Carbs}, {"fat", item.Fat}, {"fiber", item.Fiber}, {"sodium", item.Sodium}}
+			for _, n := range nutrition {
+				if n.value != nil && *n.value < 0 {
+					errs = append(errs, itemError(ci, ii, item, n.field, "Nutrition values can't be negative"))
+				}
+			}
+
+			for _, message := range validateCustomizations(item.Customizations) {
+				errs = append(errs, itemError(ci, ii, item, "customizations", message))
+			}
+		}
+	}
+	return errs
+}
+
+func validateCustomizations(customizations []ImportCustomization) []string {
+	var messages []string
+	names := map[string]bool{}
+	for i := range customizations {
+		customization := &customizations[i]
+		customization.Name = strings.TrimSpace(customization.Name)
+		label := customization.Name
+		if label == "" {
+			label = fmt.Sprintf("Customization %d", i+1)
+			messages = append(messages, label+": name is required")
+		} else if names[label] {
+			messages = append(messages, label+": appears more than once")
+		}
+		names[label] = true
+
+		if !models.IsValidCustomizationType(customization.Type) {
+			messages = append(messages, label+": type must be size, addon or choice")
+		}
+		if len(customization.Options) == 0 {
+			messages = append(messages, label+": needs at least one option")
+		}
+		if customization.MaxSelections < 0 || customization.MaxSelections > len(customization.Options) {
+			messages = append(messages, fmt.Sprintf("%s: maxSelections must be between 1 and the number of options", label))
+		}
+
+		options := map[string]bool{}
+		for j := range customization.Options {
+			option := &customization.Options[j]
+			option.Name = strings.TrimSpace(option.Name)
+			if option.Name == "" {
+				messages = append(messages, label+": option names are required")
+			} else if options[option.Name] {
+				messages = append(messages, label+": option "+option.Name+" appears more than once")
+			}
+			options[option.Name] = true
+		}
+	}
+	return messages
+}
+
+// ApplyMenuImport creates and updates the restaurant's categories and items
+// from a validated import. Nothing is deleted except customizations and
+// options left out of an item's list. It returns row errors for items that
+// can't be matched unambiguously; the caller must then roll back.
+func ApplyMenuImport(tx *gorm.DB, restaurantID uuid.UUID, menu *MenuImport) (MenuImportSummary, []MenuImportError, error) {
+	applied, errs, err := applyMenu(tx, restaurantID, menu)
+	return applied.summary, errs, err
+}
+
+// appliedMenu is what applying a menu did: the counts, and the categories
+// and items the menu lists.
+type appliedMenu struct {
+	summary     MenuImportSummary
+	categoryIDs []uuid.UUID
+	itemIDs     []uuid.UUID
+}
+
+func applyMenu(tx *gorm.DB, restaurantID uuid.UUID, menu *MenuImport) (appliedMenu, []MenuImportError, error) {
+	var applied appliedMenu
+	summary := &applied.summary
+	var errs []MenuImportError
+
+	// One import at a time per restaurant
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", restaurantID).
+		First(&models.Restaurant{}).Error; err != nil {
+		return applied, nil, err
+	}
+
+	var categories []models.MenuCategory
+	if err := tx.Where("restaurant_id = ?", restaurantID).Find(&categories).Error; err != nil {
+		return applied, nil, err
+	}
+	categoriesByName := make(map[string]*models.MenuCategory, len(categories))
+	for i := range categories {
+		categoriesByName[categories[i].Name] = &categories[i]
+	}
+
+	var items []models.MenuItem
+	if err := tx.Preload("Customizations.Options").Where("restaurant_id = ?", restaurantID).Find(&items).Error; err != nil {
+		return applied, nil, err
+	}
+	itemsBySKU := map[string]*models.MenuItem{}
+	unnamedItems := map[string][]*models.MenuItem{}
+	for i := range items {
+		if items[i].SKU != nil {
+			itemsBySKU[*items[i].SKU] = &items[i]
+		} else {
+			key := items[i].CategoryID.String() + "/" + items[i].Name
+			unnamedItems[key] = append(unnamedItems[key], &items[i])
+		}
+	}
+	matched := map[uuid.UUID]bool{}
+
+	for ci := range menu.Categories {
+		imported := &menu.Categories[ci]
+		category, exists := categoriesByName[imported.Name]
+		if exists {
+			updates := map[string]interface{}{"description": imported.Description}
+			if imported.Order != nil {
+				updates["order"] = *imported.Order
+			}
+			if err := tx.Model(category).Updates(updates).Error; err != nil {
+				return applied, nil, err
+			}
+			summary.CategoriesUpdated++
+		} else {
+			category = &models.MenuCategory{
+				RestaurantID: restaurantID,
+				Name:         imported.Name,
+				Description:  imported.Description,
+				IsActive:     true,
+			}
+			if imported.Order != nil {
+				category.Order = *imported.Order
+			}
+			if err := tx.Create(category).Error; err != nil {
+				return applied, nil, err
+			}
+			categoriesByName[category.Name] = category
+			summary.CategoriesCreated++
+		}
+		applied.categoryIDs = append(applied.categoryIDs, category.ID)
+
+		for ii := range imported.Items {
+			item := &imported.Items[ii]
+
+			var target *models.MenuItem
+			if item.SKU != "" {
+				target = itemsBySKU[item.SKU]
+			}
+			if target == nil {
+				candidates := unnamedItems[category.ID.String()+"/"+item.Name]
+				if len(candidates) > 1 {
+					errs = append(errs, itemError(ci, ii, item, "name", "Several items in "+category.Name+" are called "+item.Name+"; give them SKUs"))
+					continue
+				}
+				if len(candidates) == 1 {
+					target = candidates[0]
+				}
+			}
+			if target != nil && matched[target.ID] {
+				errs = append(errs, itemError(ci, ii, item, "sku", "Matches the same existing item as an earlier row"))
+				continue
+			}
+
+			labels, err := ParseMenuItemLabels(item.Allergens, item.DietaryTags, item.SpiceLevel)
+			if err != nil {
+				return applied, nil, err
+			}
+
+			if target == nil {
+				created, err := createImportedItem(tx, restaurantID, category.ID, ii, item, labels)
+				if err != nil {
+					return applied, nil, err
+				}
+				matched[created.ID] = true
+				applied.itemIDs = append(applied.itemIDs, created.ID)
+				summary.ItemsCreated++
+				continue
+			}
+
+			matched[target.ID] = true
+			applied.itemIDs = append(applied.itemIDs, target.ID)
+			if err := updateImportedItem(tx, target, category.ID, ii, item, labels); err != nil {
+				return applied, nil, err
+			}
+			summary.ItemsUpdated++
+		}
+	}
+	return applied, errs, nil
+}
+
+func createImportedItem(tx *gorm.DB, restaurantID, categoryID uuid.UUID, position int, item *ImportItem, labels MenuItemLabels) (*models.MenuItem, error) {
+	menuItem := models.MenuItem{
+		RestaurantID:    restaurantID,
+		CategoryID:      categoryID,
+		Position:        position,
+		Name:            item.Name,
+		Description:     item.Description,
+		Price:           item.Price,
+		Image:           item.Image,
+		IsAvailable:     true,
+		PreparationTime: item.PreparationTime,
+		SpiceLevel:      labels.SpiceLevel,
+		Calories:        item.Calories,
+		Protein:         item.Protein,
+		Carbs:           item.Carbs,
+		Fat:             item.Fat,
+		Fiber:           item.Fiber,
+		Sodium:          item.Sodium,
+	}
+	if item.SKU != "" {
+		menuItem.SKU = &item.SKU
+	}
+	if err := tx.Create(&menuItem).Error; err != nil {
+		return nil, err
+	}
+	// The column defaults to available, so false has to be set after
+	if item.IsAvailable != nil && !*item.IsAvailable {
+		if err := tx.Model(&menuItem).Update("is_available", false).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := SetMenuItemLabels(tx, menuItem.ID, labels); err != nil {
+		return nil, err
+	}
+	if err := syncCustomizations(tx, menuItem.ID, nil, item.Customizations); err != nil {
+		return nil, err
+	}
+	return &menuItem, nil
+}
+
+func updateImportedItem(tx *gorm.DB, menuItem *models.MenuItem, categoryID uuid.UUID, position int, item *ImportItem, labels MenuItemLabels) error {
+	preparationTime := item.PreparationTime
+	if preparationTime == 0 {
+		preparationTime = 15
+	}
+	updates := map[string]interface{}{
+		"category_id":      categoryID,
+		"position":         position,
+		"name":             item.Name,
+		"description":      item.Description,
+		"price":            item.Price,
+		"image":            item.Image,
+		"preparation_time": preparationTime,
+		"spice_level":      labels.SpiceLevel,
+		"calories":         item.Calories,
+		"protein":          item.Protein,
+		"carbs":            item.Carbs,
+		"fat":              item.Fat,
+		"fiber":            item.Fiber,
+		"sodium":           item.Sodium,
+	}
+	if item.SKU != "" {
+		updates["sku"] = item.SKU
+	}
+	// Setting availability by hand overrides running out of stock
+	if item.IsAvailable != nil {
+		updates["is_available"] = *item.IsAvailable
+		updates["sold_out_at"] = nil
+	}
+	if err := tx.Model(&models.MenuItem{}).Where("id = ?", menuItem.ID).Updates(updates).Error; err != nil {
+		return err
+	}
+	if err := SetMenuItemLabels(tx, menuItem.ID, labels); err != nil {
+		return err
+	}
+	if item.Customizations == nil {
+		return nil
+	}
+	return syncCustomizations(tx, menuItem.ID, menuItem.Customizations, item.Customizations)
+}
+
+// syncCustomizations makes an item's customizations match the import,
+// updating the ones with the same name in place so their IDs don't change.
+func syncCustomizations(tx *gorm.DB, menuItemID uuid.UUID, existing []models.MenuCustomization, imported []ImportCustomization) error {
+	byName := make(map[string]*models.MenuCustomization, len(existing))
+	for i := range existing {
+		byName[existing[i].Name] = &existing[i]
+	}
+
+	for _, customization := range imported {
+		maxSelections := customization.MaxSelections
+		if maxSelections < 1 {
+			maxSelections = 1
+		}
+
+		current, ok := byName[customization.Name]
+		if !ok {
+			created := models.MenuCustomization{
+				MenuItemID:    menuItemID,
+				Name:          customization.Name,
+				Type:          customization.Type,
+				Required:      customization.Required,
+				MaxSelections: maxSelections,
+			}
+			for _, option := range customization.Options {
+				created.Options = append(created.Options, models.CustomizationOption{
+					Name:          option.Name,
+					PriceModifier: option.PriceModifier,
+					IsAvailable:   true,
+				})
+			}
+			if err := tx.Create(&created).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		delete(byName, customization.Name)
+
+		if err := tx.Model(current).Updates(map[string]interface{}{
+			"type":           customization.Type,
+			"required":       customization.Required,
+			"max_selections": maxSelections,
+		}).Error; err != nil {
+			return err
+		}
+		if err := syncOptions(tx, current, customization.Options); err != nil {
+			return err
+		}
+	}
+
+	// Whatever is left wasn't in the import
+	if len(byName) > 0 {
+		ids := make([]uuid.UUID, 0, len(byName))
+		for _, customization := range byName {
+			ids = append(ids, customization.ID)
+		}
+		if err := tx.Where("customization_id IN ?", ids).Delete(&models.CustomizationOption{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id IN ?", ids).Delete(&models.MenuCustomization{}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncOptions does the same for the options of a customization. Options kept
+// keep their availability; new ones are available.
+func syncOptions(tx *gorm.DB, customization *models.MenuCustomization, imported []ImportOption) error {
+	byName := make(map[string]*models.CustomizationOption, len(customization.Options))
+	for i := range customization.Options {
+		byName[customization.Options[i].Name] = &customization.Options[i]
+	}
+
+	for _, option := range imported {
+		current, ok := byName[option.Name]
+		if !ok {
+			if err := tx.Create(&models.CustomizationOption{
+				CustomizationID: customization.ID,
+				Name:            option.Name,
+				PriceModifier:   option.PriceModifier,
+				IsAvailable:     true,
+			}).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		delete(byName, option.Name)
+
+		if err := tx.Model(current).Update("price_modifier", option.PriceModifier).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(byName) > 0 {
+		ids := make([]uuid.UUID, 0, len(byName))
+		for _, option := range byName {
+			ids = append(ids, option.ID)
+		}
+		if err := tx.Where("id IN ?", ids).Delete(&models.CustomizationOption{}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportMenu returns the restaurant's whole menu in the import format, so an
+// export imported again changes nothing.
+func ExportMenu(db *gorm.DB, restaurantID uuid.UUID) (*MenuImport, error) {
+	return exportMenu(db.Where("restaurant_id = ?", restaurantID))
+}
+
+// LiveMenu returns the menu customers see, in the import format: the active
+// categories and all their items, available or not.
+func LiveMenu(db *gorm.DB, restaurantID uuid.UUID) (*MenuImport, error) {
+	return exportMenu(db.Where("restaurant_id = ? AND is_active = ?", restaurantID, true))
+}
+
+func exportMenu(query *gorm.DB) (*MenuImport, error) {
+	var categories []models.MenuCategory
+	if err := query.
+		Preload("MenuItems", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position, name, id")
+		}).
+		Preload("MenuItems.Allergens", func(db *gorm.DB) *gorm.DB {
+			return db.Order("allergen")
+		}).
+		Preload("MenuItems.DietaryTags", func(db *gorm.DB) *gorm.DB {
+			return db.Order("tag")
+		}).
+		Preload("MenuItems.Customizations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at, id")
+		}).
+		Preload("MenuItems.Customizations.Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at, id")
+		}).
+		Order("\"order\", name").
+		Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	menu := &MenuImport{Categories: make([]ImportCategory, len(categories))}
+	for ci, category := range categories {
+		order := category.Order
+		exported := ImportCategory{
+			Name:        category.Name,
+			Description: category.Description,
+			Order:       &order,
+			Items:       make([]ImportItem, len(category.MenuItems)),
+		}
+
+		for ii, menuItem := range category.MenuItems {
+			available := menuItem.IsAvailable
+			item := ImportItem{
+				Name:            menuItem.Name,
+				Description:     menuItem.Description,
+				Price:           menuItem.Price,
+				Image:           menuItem.Image,
+				PreparationTime: menuItem.PreparationTime,
+				IsAvailable:     &available,
+				SpiceLevel:      menuItem.SpiceLevel,
+				Calories:        menuItem.Calories,
+				Protein:         menuItem.Protein,
+				Carbs:           menuItem.Carbs,
+				Fat:             menuItem.Fat,
+				Fiber:           menuItem.Fiber,
+				Sodium:          menuItem.Sodium,
+				Customizations:  make([]ImportCustomization, len(menuItem.Customizations)),
+			}
+			if menuItem.SKU != nil {
+				item.SKU = *menuItem.SKU
+			}
+			for _, allergen := range menuItem.Allergens {
+				item.Allergens = append(item.Allergens, string(allergen.Allergen))
+			}
+			for _, tag := range menuItem.DietaryTags {
+				item.DietaryTags = append(item.DietaryTags, string(tag.Tag))
+			}
+			for i, customization := range menuItem.Customizations {
+				exportedCustomization := ImportCustomization{
+					Name:          customization.Name,
+					Type:          customization.Type,
+					Required:      customization.Required,
+					MaxSelections: customization.MaxSelections,
+					Options:       make([]ImportOption, len(customization.Options)),
+				}
+				for j, option := range customization.Options {
+					exportedCustomization.Options[j] = ImportOption{Name: option.Name, PriceModifier: option.PriceModifier}
+				}
+				item.Customizations[i] = exportedCustomization
+			}
+			exported.Items[ii] = item
+		}
+		menu.Categories[ci] = exported
+	}
+	return menu, nil
+}
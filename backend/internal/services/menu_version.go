@@ -0,0 +1,657 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// baselineMenuVersionName names the version that records the live menu as it
+// was before a restaurant's first draft.
+const baselineMenuVersionName = "Menu before versioning"
+
+// MenuVersionError reports a menu version that can't be changed the way
+// asked, usually because of its status. Its message is safe to show to the
+// owner.
+type MenuVersionError struct {
+	Message string
+}
+
+func (e *MenuVersionError) Error() string {
+	return e.Message
+}
+
+// lockRestaurantMenu locks the restaurant row so only one menu version is
+// numbered or published at a time.
+func lockRestaurantMenu(tx *gorm.DB, restaurantID uuid.UUID) (*models.Restaurant, error) {
+	var restaurant models.Restaurant
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "menu_version", "last_menu_version").
+		Where("id = ?", restaurantID).
+		First(&restaurant).Error; err != nil {
+		return nil, err
+	}
+	return &restaurant, nil
+}
+
+// CurrentMenuVersion returns the number of the restaurant's published menu
+// version, 0 before its first. The row is share-locked so a publish can't
+// change the menu between reading the number and pricing against it.
+func CurrentMenuVersion(tx *gorm.DB, restaurantID uuid.UUID) (int, error) {
+	var restaurant models.Restaurant
+	if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+		Select("id", "menu_version").
+		Where("id = ?", restaurantID).
+		First(&restaurant).Error; err != nil {
+		return 0, err
+	}
+	return restaurant.MenuVersion, nil
+}
+
+// VersionMenu decodes the menu a version holds.
+func VersionMenu(version *models.MenuVersion) (*MenuImport, error) {
+	var menu MenuImport
+	if err := json.Unmarshal([]byte(version.Menu), &menu); err != nil {
+		return nil, err
+	}
+	return &menu, nil
+}
+
+// completeVersionMenu fills in what an import may leave out, since a version
+// is a whole menu: items are available and have no customizations unless
+// they say otherwise, and categories are ordered as listed.
+func completeVersionMenu(menu *MenuImport) {
+	for ci := range menu.Categories {
+		category := &menu.Categories[ci]
+		if category.Order == nil {
+			order := ci
+			category.Order = &order
+		}
+		if category.Items == nil {
+			category.Items = []ImportItem{}
+		}
+		for ii := range category.Items {
+			item := &category.Items[ii]
+			if item.IsAvailable == nil {
+				available := true
+				item.IsAvailable = &available
+			}
+			if item.Customizations == nil {
+				item.Customizations = []ImportCustomization{}
+			}
+		}
+	}
+}
+
+func newMenuVersion(tx *gorm.DB, restaurant *models.Restaurant, version *models.MenuVersion, menu *MenuImport) error {
+	completeVersionMenu(menu)
+	data, err := json.Marshal(menu)
+	if err != nil {
+		return err
+	}
+
+	restaurant.LastMenuVersion++
+	if err := tx.Model(restaurant).Update("last_menu_version", restaurant.LastMenuVersion).Error; err != nil {
+		return err
+	}
+	version.RestaurantID = restaurant.ID
+	version.Number = restaurant.LastMenuVersion
+	version.Menu = string(data)
+	return tx.Create(version).Error
+}
+
+// CreateMenuVersion saves a draft menu version; without a menu the draft
+// starts as a copy of the live menu. A restaurant's first draft also saves
+// the live menu as its first published version, so there is always one to
+// roll back to.
+func CreateMenuVersion(tx *gorm.DB, restaurantID uuid.UUID, name string, menu *MenuImport) (*models.MenuVersion, error) {
+	restaurant, err := lockRestaurantMenu(tx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if restaurant.MenuVersion == 0 {
+		live, err := LiveMenu(tx, restaurantID)
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		baseline := models.MenuVersion{
+			Name:        baselineMenuVersionName,
+			Status:      models.PublishedMenuVersion,
+			PublishedAt: &now,
+		}
+		if err := newMenuVersion(tx, restaurant, &baseline, live); err != nil {
+			return nil, err
+		}
+		if err := tx.Model(restaurant).Update("menu_version", baseline.Number).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if menu == nil {
+		if menu, err = LiveMenu(tx, restaurantID); err != nil {
+			return nil, err
+		}
+	}
+	version := models.MenuVersion{Name: name, Status: models.DraftMenuVersion}
+	if err := newMenuVersion(tx, restaurant, &version, menu); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// lockMenuVersion loads one of the restaurant's versions for update.
+func lockMenuVersion(tx *gorm.DB, restaurantID, versionID uuid.UUID) (*models.MenuVersion, error) {
+	var version models.MenuVersion
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND restaurant_id = ?", versionID, restaurantID).
+		First(&version).Error; err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// UpdateMenuVersion renames a draft or replaces its menu; a nil name or menu
+// is left as it is.
+func UpdateMenuVersion(tx *gorm.DB, restaurantID, versionID uuid.UUID, name *string, menu *MenuImport) (*models.MenuVersion, error) {
+	version, err := lockMenuVersion(tx, restaurantID, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if !version.IsEditable() {
+		return nil, &MenuVersionError{Message: "Only drafts can be edited; unschedule the version first"}
+	}
+
+	updates := map[string]interface{}{}
+	if name != nil {
+		updates["name"] = *name
+	}
+	if menu != nil {
+		completeVersionMenu(menu)
+		data, err := json.Marshal(menu)
+		if err != nil {
+			return nil, err
+		}
+		updates["menu"] = string(data)
+	}
+	if len(updates) > 0 {
+		if err := tx.Model(version).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+	if name != nil {
+		version.Name = *name
+	}
+	if menu, ok := updates["menu"].(string); ok {
+		version.Menu = menu
+	}
+	return version, nil
+}
+
+// DeleteMenuVersion deletes a draft or scheduled version. Versions that have
+// been published are kept, since orders refer to them.
+func DeleteMenuVersion(tx *gorm.DB, restaurantID, versionID uuid.UUID) error {
+	version, err := lockMenuVersion(tx, restaurantID, versionID)
+	if err != nil {
+		return err
+	}
+	if version.Status != models.DraftMenuVersion && version.Status != models.ScheduledMenuVersion {
+		return &MenuVersionError{Message: "Published versions can't be deleted"}
+	}
+	return tx.Delete(version).Error
+}
+
+// ScheduleMenuVersion sets a draft, or a scheduled version, to be published
+// by the menu publisher at publishAt.
+func ScheduleMenuVersion(tx *gorm.DB, restaurantID, versionID uuid.UUID, publishAt time.Time) (*models.MenuVersion, error) {
+	version, err := lockMenuVersion(tx, restaurantID, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if version.Status != models.DraftMenuVersion && version.Status != models.ScheduledMenuVersion {
+		return nil, &MenuVersionError{Message: "Only drafts can be scheduled"}
+	}
+
+	if err := tx.Model(version).Updates(map[string]interface{}{
+		"status":            models.ScheduledMenuVersion,
+		"publish_at":        publishAt,
+		"last_error":        "",
+		"publish_failed_at": nil,
+	}).Error; err != nil {
+		return nil, err
+	}
+	version.Status = models.ScheduledMenuVersion
+	version.PublishAt = &publishAt
+	version.LastError = ""
+	version.PublishFailedAt = nil
+	return version, nil
+}
+
+// UnscheduleMenuVersion turns a scheduled version back into a draft.
+func UnscheduleMenuVersion(tx *gorm.DB, restaurantID, versionID uuid.UUID) (*models.MenuVersion, error) {
+	version, err := lockMenuVersion(tx, restaurantID, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if version.Status != models.ScheduledMenuVersion {
+		return nil, &MenuVersionError{Message: "Version is not scheduled"}
+	}
+
+	if err := tx.Model(version).Updates(map[string]interface{}{
+		"status":     models.DraftMenuVersion,
+		"publish_at": nil,
+	}).Error; err != nil {
+		return nil, err
+	}
+	version.Status = models.DraftMenuVersion
+	version.PublishAt = nil
+	return version, nil
+}
+
+// PublishMenuVersion publishes a draft or scheduled version now. See
+// publishMenuVersion.
+func PublishMenuVersion(tx *gorm.DB, restaurantID, versionID uuid.UUID) (*models.MenuVersion, MenuImportSummary, []MenuImportError, error) {
+	return publishMenuVersion(tx, restaurantID, versionID, "Only drafts and scheduled versions can be published; roll back to earlier ones",
+		models.DraftMenuVersion, models.ScheduledMenuVersion)
+}
+
+// RollbackMenuVersion publishes a version that was published before.
+func RollbackMenuVersion(tx *gorm.DB, restaurantID, versionID uuid.UUID) (*models.MenuVersion, MenuImportSummary, []MenuImportError, error) {
+	return publishMenuVersion(tx, restaurantID, versionID, "Only versions that were published before can be rolled back to",
+		models.ArchivedMenuVersion)
+}
+
+// publishMenuVersion makes the live menu match a version. Categories and
+// items it lists are created or updated as an import would; the ones it
+// leaves out are hidden rather than deleted, so past orders keep their items.
+// The version becomes the published one and the one it replaces is archived.
+// Row errors mean the version no longer fits the live menu; the caller must
+// roll back.
+func publishMenuVersion(tx *gorm.DB, restaurantID, versionID uuid.UUID, statusMessage string, statuses ...models.MenuVersionStatus) (*models.MenuVersion, MenuImportSummary, []MenuImportError, error) {
+	var summary MenuImportSummary
+	restaurant, err := lockRestaurantMenu(tx, restaurantID)
+	if err != nil {
+		return nil, summary, nil, err
+	}
+	version, err := lockMenuVersion(tx, restaurantID, versionID)
+	if err != nil {
+		return nil, summary, nil, err
+	}
+	allowed := false
+	for _, status := range statuses {
+		allowed = allowed || version.Status == status
+	}
+	if !allowed {
+		return nil, summary, nil, &MenuVersionError{Message: statusMessage}
+	}
+
+	menu, err := VersionMenu(version)
+	if err != nil {
+		return nil, summary, nil, err
+	}
+	if errs := ValidateMenuImport(menu); len(errs) > 0 {
+		return version, summary, errs, nil
+	}
+	applied, errs, err := applyMenu(tx, restaurantID, menu)
+	if err != nil || len(errs) > 0 {
+		return version, applied.summary, errs, err
+	}
+	summary = applied.summary
+
+	// Hide what the version leaves out
+	// Sold out items count as shown, so restocking can't bring them back
+	items := tx.Model(&models.MenuItem{}).Where("restaurant_id = ? AND (is_available = ? OR sold_out_at IS NOT NULL)", restaurantID, true)
+	if len(applied.itemIDs) > 0 {
+		items = items.Where("id NOT IN ?", applied.itemIDs)
+	}
+	result := items.Updates(map[string]interface{}{"is_available": false, "sold_out_at": nil})
+	if result.Error != nil {
+		return nil, summary, nil, result.Error
+	}
+	summary.ItemsHidden = int(result.RowsAffected)
+
+	categories := tx.Model(&models.MenuCategory{}).Where("restaurant_id = ? AND is_active = ?", restaurantID, true)
+	if len(applied.categoryIDs) > 0 {
+		categories = categories.Where("id NOT IN ?", applied.categoryIDs)
+	}
+	result = categories.Update("is_active", false)
+	if result.Error != nil {
+		return nil, summary, nil, result.Error
+	}
+	summary.CategoriesHidden = int(result.RowsAffected)
+	if len(applied.categoryIDs) > 0 {
+		if err := tx.Model(&models.MenuCategory{}).Where("id IN ?", applied.categoryIDs).Update("is_active", true).Error; err != nil {
+			return nil, summary, nil, err
+		}
+	}
+
+	if err := tx.Model(&models.MenuVersion{}).
+		Where("restaurant_id = ? AND status = ?", restaurantID, models.PublishedMenuVersion).
+		Update("status", models.ArchivedMenuVersion).Error; err != nil {
+		return nil, summary, nil, err
+	}
+	now := time.Now()
+	if err := tx.Model(version).Updates(map[string]interface{}{
+		"status":       models.PublishedMenuVersion,
+		"publish_at":   nil,
+		"published_at": now,
+		"last_error":   "",
+	}).Error; err != nil {
+		return nil, summary, nil, err
+	}
+	version.Status = models.PublishedMenuVersion
+	version.PublishAt = nil
+	version.PublishedAt = &now
+	version.LastError = ""
+
+	if err := tx.Model(restaurant).Update("menu_version", version.Number).Error; err != nil {
+		return nil, summary, nil, err
+	}
+	return version, summary, nil, nil
+}
+
+// MenuDiff lists what changes from one menu to another. Categories are
+// matched by name and items as an import matches them.
+type MenuDiff struct {
+	CategoriesAdded   []string       `json:"categoriesAdded"`
+	CategoriesRemoved []string       `json:"categoriesRemoved"`
+	CategoriesChanged []CategoryDiff `json:"categoriesChanged"`
+	ItemsAdded        []ItemDiff     `json:"itemsAdded"`
+	ItemsRemoved      []ItemDiff     `json:"itemsRemoved"`
+	ItemsChanged      []ItemDiff     `json:"itemsChanged"`
+}
+
+type CategoryDiff struct {
+	Name    string        `json:"name"`
+	Changes []FieldChange `json:"changes"`
+}
+
+type ItemDiff struct {
+	Category string        `json:"category"`
+	SKU      string        `json:"sku,omitempty"`
+	Name     string        `json:"name"`
+	Changes  []FieldChange `json:"changes,omitempty"`
+}
+
+// FieldChange is one field that differs, with its old and new values.
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+type fieldChanges []FieldChange
+
+func (c *fieldChanges) compare(field string, from, to interface{}) {
+	if !reflect.DeepEqual(from, to) {
+		*c = append(*c, FieldChange{Field: field, From: from, To: to})
+	}
+}
+
+// diffItem is an item with the category it's listed in.
+type diffItem struct {
+	category string
+	item     *ImportItem
+}
+
+func diffItemKey(category string, item *ImportItem) string {
+	if item.SKU != "" {
+		return "sku:" + item.SKU
+	}
+	return "name:" + category + "/" + item.Name
+}
+
+func isAvailable(item *ImportItem) bool {
+	return item.IsAvailable == nil || *item.IsAvailable
+}
+
+// labelLists returns an item's allergens and dietary tags as they'd be
+// saved, so the diff doesn't report ones implied by others.
+func labelLists(item *ImportItem) (string, string) {
+	labels, err := ParseMenuItemLabels(item.Allergens, item.DietaryTags, item.SpiceLevel)
+	if err != nil {
+		return strings.Join(item.Allergens, ", "), strings.Join(item.DietaryTags, ", ")
+	}
+	allergens := make([]string, len(labels.Allergens))
+	for i, allergen := range labels.Allergens {
+		allergens[i] = string(allergen)
+	}
+	tags := make([]string, len(labels.DietaryTags))
+	for i, tag := range labels.DietaryTags {
+		tags[i] = string(tag)
+	}
+	return strings.Join(allergens, ", "), strings.Join(tags, ", ")
+}
+
+// DiffMenus compares two menus, listing categories and items in the order
+// they appear.
+func DiffMenus(from, to *MenuImport) MenuDiff {
+	diff := MenuDiff{
+		CategoriesAdded:   []string{},
+		CategoriesRemoved: []string{},
+		CategoriesChanged: []CategoryDiff{},
+		ItemsAdded:        []ItemDiff{},
+		ItemsRemoved:      []ItemDiff{},
+		ItemsChanged:      []ItemDiff{},
+	}
+
+	fromCategories := map[string]*ImportCategory{}
+	fromItems := map[string]diffItem{}
+	for ci := range from.Categories {
+		category := &from.Categories[ci]
+		fromCategories[category.Name] = category
+		for ii := range category.Items {
+			item := &category.Items[ii]
+			fromItems[diffItemKey(category.Name, item)] = diffItem{category: category.Name, item: item}
+		}
+	}
+
+	toCategories := map[string]bool{}
+	toItems := map[string]bool{}
+	for ci := range to.Categories {
+		category := &to.Categories[ci]
+		toCategories[category.Name] = true
+
+		if old, ok := fromCategories[category.Name]; !ok {
+			diff.CategoriesAdded = append(diff.CategoriesAdded, category.Name)
+		} else {
+			var changes fieldChanges
+			changes.compare("description", old.Description, category.Description)
+			changes.compare("order", old.Order, category.Order)
+			if len(changes) > 0 {
+				diff.CategoriesChanged = append(diff.CategoriesChanged, CategoryDiff{Name: category.Name, Changes: changes})
+			}
+		}
+
+		for ii := range category.Items {
+			item := &category.Items[ii]
+			key := diffItemKey(category.Name, item)
+			toItems[key] = true
+			entry := ItemDiff{Category: category.Name, SKU: item.SKU, Name: item.Name}
+
+			old, ok := fromItems[key]
+			if !ok {
+				diff.ItemsAdded = append(diff.ItemsAdded, entry)
+				continue
+			}
+			oldAllergens, oldTags := labelLists(old.item)
+			allergens, tags := labelLists(item)
+
+			var changes fieldChanges
+			changes.compare("category", old.category, category.Name)
+			changes.compare("name", old.item.Name, item.Name)
+			changes.compare("description", old.item.Description, item.Description)
+			changes.compare("price", old.item.Price, item.Price)
+			changes.compare("image", old.item.Image, item.Image)
+			changes.compare("preparationTime", old.item.PreparationTime, item.PreparationTime)
+			changes.compare("isAvailable", isAvailable(old.item), isAvailable(item))
+			changes.compare("allergens", oldAllergens, allergens)
+			changes.compare("dietaryTags", oldTags, tags)
+			changes.compare("spiceLevel", old.item.SpiceLevel, item.SpiceLevel)
+			changes.compare("calories", old.item.Calories, item.Calories)
+			changes.compare("protein", old.item.Protein, item.Protein)
+			changes.compare("carbs", old.item.Carbs, item.Carbs)
+			changes.compare("fat", old.item.Fat, item.Fat)
+			changes.compare("fiber", old.item.Fiber, item.Fiber)
+			changes.compare("sodium", old.item.Sodium, item.Sodium)
+			changes.compare("customizations", formatCustomizations(old.item.Customizations), formatCustomizations(item.Customizations))
+			if len(changes) > 0 {
+				entry.Changes = changes
+				diff.ItemsChanged = append(diff.ItemsChanged, entry)
+			}
+		}
+	}
+
+	for ci := range from.Categories {
+		category := &from.Categories[ci]
+		if !toCategories[category.Name] {
+			diff.CategoriesRemoved = append(diff.CategoriesRemoved, category.Name)
+		}
+		for ii := range category.Items {
+			item := &category.Items[ii]
+			if !toItems[diffItemKey(category.Name, item)] {
+				diff.ItemsRemoved = append(diff.ItemsRemoved, ItemDiff{Category: category.Name, SKU: item.SKU, Name: item.Name})
+			}
+		}
+	}
+	return diff
+}
+
+// menuPublishRetryAfter is how long a scheduled version that failed to
+// publish for a reason other than its contents waits before it is tried again.
+const menuPublishRetryAfter = 5 * time.Minute
+
+// MenuPublisher publishes menu versions when their scheduled time comes. A
+// version that can't be published goes back to draft with the reason in
+// LastError.
+type MenuPublisher struct {
+	db  *repository.Database
+	cfg *config.MenuConfig
+}
+
+func NewMenuPublisher(db *repository.Database, cfg *config.Config) *MenuPublisher {
+	return &MenuPublisher{
+		db:  db,
+		cfg: &cfg.Menu,
+	}
+}
+
+// Start publishes due versions until ctx is cancelled.
+func (p *MenuPublisher) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.PublishInterval)
+	defer ticker.Stop()
+
+	for {
+		p.publishDue()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishDue publishes every due version. A version that fails to publish,
+// say because the database gives up on it, is set aside for
+// menuPublishRetryAfter and the publisher carries on with the rest, so it
+// only holds back itself.
+func (p *MenuPublisher) publishDue() {
+	for {
+		version, err := p.publishNext()
+		if err != nil {
+			if version == nil {
+				log.Printf("menu: failed to find scheduled versions: %v", err)
+				return
+			}
+			log.Printf("menu: failed to publish version %d of restaurant %s: %v", version.Number, version.RestaurantID, err)
+			if err := p.db.DB.Model(version).Update("publish_failed_at", time.Now()).Error; err != nil {
+				log.Printf("menu: failed to set aside version %s: %v", version.ID, err)
+				return
+			}
+			continue
+		}
+		if version == nil {
+			return
+		}
+	}
+}
+
+// publishNext publishes the version that has been due longest, one
+// transaction per version. It returns the version it picked, nil when there
+// is none left.
+func (p *MenuPublisher) publishNext() (*models.MenuVersion, error) {
+	var due models.MenuVersion
+	now := time.Now()
+	if err := p.db.DB.Select("id", "restaurant_id", "number").
+		Where("status = ? AND publish_at <= ?", models.ScheduledMenuVersion, now).
+		Where("publish_failed_at IS NULL OR publish_failed_at <= ?", now.Add(-menuPublishRetryAfter)).
+		Order("publish_at").
+		First(&due).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	err := p.db.DB.Transaction(func(tx *gorm.DB) error {
+		// Restaurant first, as publishing locks it; the version may have
+		// been published or unscheduled since it was found
+		if _, err := lockRestaurantMenu(tx, due.RestaurantID); err != nil {
+			return err
+		}
+		var version models.MenuVersion
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ? AND publish_at <= ?", due.ID, models.ScheduledMenuVersion, time.Now()).
+			First(&version).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil
+			}
+			return err
+		}
+
+		var failure string
+		err := tx.Transaction(func(tx *gorm.DB) error {
+			_, _, errs, err := PublishMenuVersion(tx, version.RestaurantID, version.ID)
+			if err != nil {
+				return err
+			}
+			if len(errs) > 0 {
+				failure = errs[0].Message
+				if len(errs) > 1 {
+					failure += fmt.Sprintf(" (and %d more errors)", len(errs)-1)
+				}
+				return &MenuVersionError{Message: failure}
+			}
+			return nil
+		})
+		if err == nil {
+			log.Printf("menu: published version %d of restaurant %s", version.Number, version.RestaurantID)
+			return nil
+		}
+		if failure == "" {
+			return err
+		}
+
+		log.Printf("menu: version %d of restaurant %s could not be published: %s", version.Number, version.RestaurantID, failure)
+		return tx.Model(&version).Updates(map[string]interface{}{
+			"status":     models.DraftMenuVersion,
+			"publish_at": nil,
+			"last_error": failure,
+		}).Error
+	})
+	return &due, err
+}
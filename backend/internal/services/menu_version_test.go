@@ -0,0 +1,67 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffMenus(t *testing.T) {
+	one, two := 1, 2
+	available := true
+	from := &MenuImport{Categories: []ImportCategory{
+		{Name: "Mains", Order: &one, Items: []ImportItem{
+			{SKU: "B1", Name: "Burger", Price: 10},
+			{Name: "Salad", Price: 8, Allergens: []string{"wheat"}},
+			{Name: "Soup", Price: 6},
+		}},
+		{Name: "Drinks", Items: []ImportItem{
+			{SKU: "C1", Name: "Cola", Price: 2.5},
+		}},
+	}}
+	to := &MenuImport{Categories: []ImportCategory{
+		{Name: "Mains", Description: "Hearty", Order: &two, Items: []ImportItem{
+			{SKU: "B1", Name: "Cheeseburger", Price: 11},
+			// wheat already implies gluten, and items are available by default
+			{Name: "Salad", Price: 8, Allergens: []string{"gluten", "wheat"}, IsAvailable: &available},
+		}},
+		{Name: "Sides", Items: []ImportItem{
+			{Name: "Fries", Price: 3},
+			{SKU: "C1", Name: "Cola", Price: 2.5},
+		}},
+	}}
+
+	want := MenuDiff{
+		CategoriesAdded:   []string{"Sides"},
+		CategoriesRemoved: []string{"Drinks"},
+		CategoriesChanged: []CategoryDiff{{Name: "Mains", Changes: []FieldChange{
+			{Field: "description", From: "", To: "Hearty"},
+			{Field: "order", From: &one, To: &two},
+		}}},
+		ItemsAdded:   []ItemDiff{{Category: "Sides", Name: "Fries"}},
+		ItemsRemoved: []ItemDiff{{Category: "Mains", Name: "Soup"}},
+		ItemsChanged: []ItemDiff{
+			{Category: "Mains", SKU: "B1", Name: "Cheeseburger", Changes: []FieldChange{
+				{Field: "name", From: "Burger", To: "Cheeseburger"},
+				{Field: "price", From: 10.0, To: 11.0},
+			}},
+			{Category: "Sides", SKU: "C1", Name: "Cola", Changes: []FieldChange{
+				{Field: "category", From: "Drinks", To: "Sides"},
+			}},
+		},
+	}
+	if got := DiffMenus(from, to); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffMenus =\n%+v\nwant\n%+v", got, want)
+	}
+
+	empty := MenuDiff{
+		CategoriesAdded:   []string{},
+		CategoriesRemoved: []string{},
+		CategoriesChanged: []CategoryDiff{},
+		ItemsAdded:        []ItemDiff{},
+		ItemsRemoved:      []ItemDiff{},
+		ItemsChanged:      []ItemDiff{},
+	}
+	if got := DiffMenus(to, to); !reflect.DeepEqual(got, empty) {
+		t.Errorf("DiffMenus of a menu with itself = %+v, want no changes", got)
+	}
+}
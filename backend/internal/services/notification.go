@@ -0,0 +1,458 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	notificationBaseBackoff = 15 * time.Second
+	notificationMaxBackoff  = time.Hour
+)
+
+// NotificationChannel delivers rendered notifications to one kind of
+// recipient (email address, phone number, device).
+type NotificationChannel interface {
+	Channel() models.NotificationChannel
+	Send(ctx context.Context, notification *models.Notification) error
+}
+
+// LocalSinkChannel appends notifications as JSON lines to a file instead of
+// calling an external provider. It is the default for every channel.
+type LocalSinkChannel struct {
+	channel models.NotificationChannel
+	path    string
+	mu      sync.Mutex
+}
+
+func NewLocalSinkChannel(channel models.NotificationChannel, dir string) *LocalSinkChannel {
+	return &LocalSinkChannel{
+		channel: channel,
+		path:    filepath.Join(dir, string(channel)+".log"),
+	}
+}
+
+func (lc *LocalSinkChannel) Channel() models.NotificationChannel {
+	return lc.channel
+}
+
+func (lc *LocalSinkChannel) Send(ctx context.Context, notification *models.Notification) error {
+	line, err := json.Marshal(map[string]interface{}{
+		"id":        notification.ID,
+		"channel":   notification.Channel,
+		"recipient": notification.Recipient,
+		"subject":   notification.Subject,
+		"body":      notification.Body,
+		"sentAt":    time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(lc.path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(lc.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// NotificationService turns domain events into customer notifications and
+// delivers them through the registered channels with retries.
+type NotificationService struct {
+	db       *repository.Database
+	cfg      *config.NotificationConfig
+	channels map[models.NotificationChannel]NotificationChannel
+}
+
+func NewNotificationService(db *repository.Database, cfg *config.Config, channels ...NotificationChannel) *NotificationService {
+	service := &NotificationService{
+		db:       db,
+		cfg:      &cfg.Notification,
+		channels: make(map[models.NotificationChannel]NotificationChannel),
+	}
+	for _, channel := range channels {
+		service.channels[channel.Channel()] = channel
+	}
+	return service
+}
+
+// DefaultNotificationChannels returns local sink channels for email, SMS and push.
+func DefaultNotificationChannels(cfg *config.Config) []NotificationChannel {
+	return []NotificationChannel{
+		NewLocalSinkChannel(models.EmailChannel, cfg.Notification.SinkDir),
+		NewLocalSinkChannel(models.SMSChannel, cfg.Notification.SinkDir),
+		NewLocalSinkChannel(models.PushChannel, cfg.Notification.SinkDir),
+	}
+}
+
+// Name identifies the notification consumer in the outbox.
+func (s *NotificationService) Name() string {
+	return "notifications"
+}
+
+// Handle queues notifications for the customer of an order or refund event,
+// or for the restaurant owner of acceptance reminders, pauses and low stock.
+func (s *NotificationService) Handle(tx *gorm.DB, event *models.OutboxEvent) error {
+	if event.EventType == models.RefundApprovedEvent || event.EventType == models.RefundDeniedEvent {
+		return s.handleRefundEvent(tx, event)
+	}
+	if event.EventType == models.OrderAcceptanceReminderEvent || event.EventType == models.RestaurantPausedEvent || event.EventType == models.MenuLowStockEvent {
+		return s.handleRestaurantEvent(tx, event)
+	}
+	if event.EventType != models.OrderCreatedEvent && event.EventType != models.OrderStatusChangedEvent {
+		return nil
+	}
+
+	payload, err := decodeOrderEvent(event)
+	if err != nil {
+		return err
+	}
+
+	templateName := "order_placed"
+	if event.EventType == models.OrderStatusChangedEvent {
+		templateName = "order_" + string(payload.Status)
+	}
+	if !hasNotificationTemplate(templateName) {
+		return nil
+	}
+
+	var user models.User
+	if err := tx.Where("id = ?", payload.UserID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+	if !user.IsActive {
+		return nil
+	}
+
+	var restaurant models.Restaurant
+	if err := tx.Select("id", "name").Where("id = ?", payload.RestaurantID).First(&restaurant).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	data := notificationData{
+		FirstName:      user.FirstName,
+		RestaurantName: restaurant.Name,
+		OrderNumber:    "#" + strings.ToUpper(payload.OrderID.String()[:8]),
+		Total:          fmt.Sprintf("$%.2f", payload.TotalAmount+payload.DeliveryFee+payload.Tax+payload.Tip-payload.DiscountAmount),
+		Message:        payload.Message,
+		PickupCode:     payload.PickupCode,
+	}
+
+	return s.enqueue(tx, event.ID, &user, &payload.OrderID, templateName, data)
+}
+
+// handleRefundEvent tells the customer whether their refund was approved.
+func (s *NotificationService) handleRefundEvent(tx *gorm.DB, event *models.OutboxEvent) error {
+	payload, err := decodeRefundEvent(event)
+	if err != nil {
+		return err
+	}
+
+	templateName := "refund_" + string(payload.Status)
+	if !hasNotificationTemplate(templateName) {
+		return nil
+	}
+
+	var user models.User
+	if err := tx.Where("id = ?", payload.UserID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+	if !user.IsActive {
+		return nil
+	}
+
+	var restaurant models.Restaurant
+	if err := tx.Select("id", "name").Where("id = ?", payload.RestaurantID).First(&restaurant).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	data := notificationData{
+		FirstName:      user.FirstName,
+		RestaurantName: restaurant.Name,
+		OrderNumber:    "#" + strings.ToUpper(payload.OrderID.String()[:8]),
+		Total:          fmt.Sprintf("$%.2f", payload.Amount),
+		Message:        payload.Note,
+	}
+
+	return s.enqueue(tx, event.ID, &user, &payload.OrderID, templateName, data)
+}
+
+// handleRestaurantEvent tells the restaurant owner about an order waiting to be
+// accepted, that the restaurant was closed after missing orders, or that an
+// item is running out.
+func (s *NotificationService) handleRestaurantEvent(tx *gorm.DB, event *models.OutboxEvent) error {
+	var restaurantID uuid.UUID
+	var orderID *uuid.UUID
+	var data notificationData
+	templateName := "restaurant_paused"
+
+	if event.EventType == models.OrderAcceptanceReminderEvent {
+		payload, err := decodeOrderEvent(event)
+		if err != nil {
+			return err
+		}
+		templateName = "order_acceptance_reminder"
+		restaurantID = payload.RestaurantID
+		orderID = &payload.OrderID
+		data.OrderNumber = "#" + strings.ToUpper(payload.OrderID.String()[:8])
+		data.Total = fmt.Sprintf("$%.2f", payload.TotalAmount+payload.DeliveryFee+payload.Tax+payload.Tip-payload.DiscountAmount)
+	} else if event.EventType == models.MenuLowStockEvent {
+		payload, err := decodeStockEvent(event)
+		if err != nil {
+			return err
+		}
+		templateName = "menu_low_stock"
+		restaurantID = payload.RestaurantID
+		data.ItemName = payload.Name
+		data.Stock = payload.StockCount
+	} else {
+		payload, err := decodeRestaurantEvent(event)
+		if err != nil {
+			return err
+		}
+		restaurantID = payload.RestaurantID
+	}
+
+	var restaurant models.Restaurant
+	if err := tx.Select("id", "name", "owner_id").Where("id = ?", restaurantID).First(&restaurant).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+
+	var owner models.User
+	if err := tx.Where("id = ?", restaurant.OwnerID).First(&owner).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+	if !owner.IsActive {
+		return nil
+	}
+
+	data.FirstName = owner.FirstName
+	data.RestaurantName = restaurant.Name
+
+	return s.enqueue(tx, event.ID, &owner, orderID, templateName, data)
+}
+
+// enqueue renders a template in the user's locale and queues it on every
+// channel the user has enabled.
+func (s *NotificationService) enqueue(tx *gorm.DB, eventID uuid.UUID, user *models.User, orderID *uuid.UUID, templateName string, data notificationData) error {
+	locale := user.Locale
+	if !IsSupportedLocale(locale) {
+		locale = DefaultLocale
+	}
+
+	subject, body, err := renderNotification(templateName, locale, data)
+	if err != nil {
+		return err
+	}
+
+	for channel := range s.channels {
+		if !user.NotificationPreferences.Allows(channel) {
+			continue
+		}
+
+		recipient := notificationRecipient(user, channel)
+		if recipient == "" {
+			continue
+		}
+
+		notification := models.Notification{
+			UserID:        user.ID,
+			OrderID:       orderID,
+			EventID:       eventID,
+			Channel:       channel,
+			Template:      templateName,
+			Locale:        locale,
+			Recipient:     recipient,
+			Subject:       subject,
+			Body:          body,
+			Status:        models.NotificationPending,
+			NextAttemptAt: time.Now(),
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&notification).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func notificationRecipient(user *models.User, channel models.NotificationChannel) string {
+	switch channel {
+	case models.EmailChannel:
+		return user.Email
+	case models.SMSChannel:
+		return user.Phone
+	case models.PushChannel:
+		return user.ID.String()
+	}
+	return ""
+}
+
+// Start polls the notification queue until ctx is cancelled.
+func (s *NotificationService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.processDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *NotificationService) processDue(ctx context.Context) {
+	lease := uuid.New()
+	notifications, err := s.claimDue(lease)
+	if err != nil {
+		log.Printf("notifications: failed to claim notifications: %v", err)
+		return
+	}
+
+	for i := range notifications {
+		if ctx.Err() != nil {
+			return
+		}
+		s.attempt(ctx, lease, &notifications[i])
+	}
+}
+
+// leaseDuration is how long a worker holds notifications it is about to send:
+// every send of the batch can take up to SendTimeout, with some time to spare
+// for recording them.
+func (s *NotificationService) leaseDuration(notifications int) time.Duration {
+	return time.Duration(notifications)*s.cfg.SendTimeout + notificationBaseBackoff
+}
+
+// claimDue locks a batch of due notifications and leases them to the worker
+// so concurrent workers don't send the same notification twice. The lease
+// lasts until the whole batch could have been sent; a worker only sends and
+// records notifications whose lease is still its own.
+func (s *NotificationService) claimDue(lease uuid.UUID) ([]models.Notification, error) {
+	var notifications []models.Notification
+
+	err := s.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.NotificationPending, time.Now()).
+			Order("next_attempt_at ASC").
+			Limit(s.cfg.BatchSize).
+			Find(&notifications).Error; err != nil {
+			return err
+		}
+
+		if len(notifications) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, len(notifications))
+		for i, notification := range notifications {
+			ids[i] = notification.ID
+		}
+
+		return tx.Model(&models.Notification{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"lease_id":        lease,
+			"next_attempt_at": time.Now().Add(s.leaseDuration(len(notifications))),
+		}).Error
+	})
+
+	return notifications, err
+}
+
+func (s *NotificationService) attempt(ctx context.Context, lease uuid.UUID, notification *models.Notification) {
+	// Renew the lease for this send, and skip the notification if another
+	// worker took it over after the batch's lease ran out
+	owned := s.db.DB.Model(notification).
+		Where("lease_id = ? AND status = ?", lease, models.NotificationPending).
+		Update("next_attempt_at", time.Now().Add(s.leaseDuration(1)))
+	if owned.Error != nil {
+		log.Printf("notifications: failed to renew lease on notification %s: %v", notification.ID, owned.Error)
+		return
+	}
+	if owned.RowsAffected == 0 {
+		return
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"attempts":        notification.Attempts + 1,
+		"last_attempt_at": now,
+		"lease_id":        nil,
+	}
+
+	var err error
+	channel, ok := s.channels[notification.Channel]
+	if !ok {
+		err = fmt.Errorf("no %s channel configured", notification.Channel)
+	} else {
+		sendCtx, cancel := context.WithTimeout(ctx, s.cfg.SendTimeout)
+		err = channel.Send(sendCtx, notification)
+		cancel()
+	}
+
+	switch {
+	case err == nil:
+		updates["status"] = models.NotificationSent
+		updates["sent_at"] = now
+		updates["last_error"] = ""
+	case notification.Attempts+1 >= s.cfg.MaxAttempts:
+		updates["status"] = models.NotificationFailed
+		updates["last_error"] = err.Error()
+	default:
+		updates["next_attempt_at"] = now.Add(notificationBackoff(notification.Attempts + 1))
+		updates["last_error"] = err.Error()
+	}
+
+	if err := s.db.DB.Model(notification).Where("lease_id = ?", lease).Updates(updates).Error; err != nil {
+		log.Printf("notifications: failed to record attempt for notification %s: %v", notification.ID, err)
+	}
+}
+
+func notificationBackoff(attempts int) time.Duration {
+	backoff := notificationBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= notificationMaxBackoff {
+			return notificationMaxBackoff
+		}
+	}
+	return backoff
+}
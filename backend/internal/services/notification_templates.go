@@ -0,0 +1,263 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+const DefaultLocale = "en"
+
+// SupportedLocales lists the locales notification templates are translated into.
+var SupportedLocales = []string{"en", "es", "fr"}
+
+func IsSupportedLocale(locale string) bool {
+	for _, l := range SupportedLocales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// notificationData is the data available to notification templates.
+type notificationData struct {
+	FirstName      string
+	RestaurantName string
+	OrderNumber    string
+	Total          string
+	Message        string
+	PickupCode     string
+	ItemName       string
+	Stock          int
+}
+
+type notificationTemplate struct {
+	Subject *template.Template
+	Body    *template.Template
+}
+
+// notificationTemplates maps template name -> locale -> template. Names are
+// "order_placed", "order_<status>" for status changes and "refund_<status>"
+// for refund decisions.
+var notificationTemplates = map[string]map[string]notificationTemplate{
+	"order_placed": {
+		"en": newNotificationTemplate(
+			"Order {{.OrderNumber}} received",
+			"Hi {{.FirstName}}, {{.RestaurantName}} has received your order {{.OrderNumber}} ({{.Total}}). We'll let you know once it's confirmed.",
+		),
+		"es": newNotificationTemplate(
+			"Pedido {{.OrderNumber}} recibido",
+			"Hola {{.FirstName}}, {{.RestaurantName}} ha recibido tu pedido {{.OrderNumber}} ({{.Total}}). Te avisaremos cuando se confirme.",
+		),
+		"fr": newNotificationTemplate(
+			"Commande {{.OrderNumber}} reçue",
+			"Bonjour {{.FirstName}}, {{.RestaurantName}} a bien reçu votre commande {{.OrderNumber}} ({{.Total}}). Nous vous préviendrons dès sa confirmation.",
+		),
+	},
+	"order_confirmed": {
+		"en": newNotificationTemplate(
+			"Order {{.OrderNumber}} confirmed",
+			"Good news {{.FirstName}}! {{.RestaurantName}} confirmed your order {{.OrderNumber}}.",
+		),
+		"es": newNotificationTemplate(
+			"Pedido {{.OrderNumber}} confirmado",
+			"¡Buenas noticias {{.FirstName}}! {{.RestaurantName}} confirmó tu pedido {{.OrderNumber}}.",
+		),
+		"fr": newNotificationTemplate(
+			"Commande {{.OrderNumber}} confirmée",
+			"Bonne nouvelle {{.FirstName}} ! {{.RestaurantName}} a confirmé votre commande {{.OrderNumber}}.",
+		),
+	},
+	"order_preparing": {
+		"en": newNotificationTemplate(
+			"Your order is being prepared",
+			"{{.RestaurantName}} is now preparing your order {{.OrderNumber}}.",
+		),
+		"es": newNotificationTemplate(
+			"Tu pedido se está preparando",
+			"{{.RestaurantName}} está preparando tu pedido {{.OrderNumber}}.",
+		),
+		"fr": newNotificationTemplate(
+			"Votre commande est en préparation",
+			"{{.RestaurantName}} prépare votre commande {{.OrderNumber}}.",
+		),
+	},
+	"order_ready_for_pickup": {
+		"en": newNotificationTemplate(
+			"Order {{.OrderNumber}} is ready",
+			"Your order {{.OrderNumber}} from {{.RestaurantName}} is ready for pickup.{{if .PickupCode}} Show code {{.PickupCode}} at the counter.{{end}}",
+		),
+		"es": newNotificationTemplate(
+			"El pedido {{.OrderNumber}} está listo",
+			"Tu pedido {{.OrderNumber}} de {{.RestaurantName}} está listo para recoger.{{if .PickupCode}} Muestra el código {{.PickupCode}} en el mostrador.{{end}}",
+		),
+		"fr": newNotificationTemplate(
+			"La commande {{.OrderNumber}} est prête",
+			"Votre commande {{.OrderNumber}} de {{.RestaurantName}} est prête à être récupérée.{{if .PickupCode}} Présentez le code {{.PickupCode}} au comptoir.{{end}}",
+		),
+	},
+	"order_on_the_way": {
+		"en": newNotificationTemplate(
+			"Your order is on the way",
+			"Your order {{.OrderNumber}} from {{.RestaurantName}} is on its way to you.",
+		),
+		"es": newNotificationTemplate(
+			"Tu pedido está en camino",
+			"Tu pedido {{.OrderNumber}} de {{.RestaurantName}} está en camino.",
+		),
+		"fr": newNotificationTemplate(
+			"Votre commande est en route",
+			"Votre commande {{.OrderNumber}} de {{.RestaurantName}} est en route.",
+		),
+	},
+	"order_delivered": {
+		"en": newNotificationTemplate(
+			"Order {{.OrderNumber}} delivered",
+			"Enjoy your meal {{.FirstName}}! Your order from {{.RestaurantName}} has been delivered.",
+		),
+		"es": newNotificationTemplate(
+			"Pedido {{.OrderNumber}} entregado",
+			"¡Buen provecho {{.FirstName}}! Tu pedido de {{.RestaurantName}} ha sido entregado.",
+		),
+		"fr": newNotificationTemplate(
+			"Commande {{.OrderNumber}} livrée",
+			"Bon appétit {{.FirstName}} ! Votre commande de {{.RestaurantName}} a été livrée.",
+		),
+	},
+	"order_collected": {
+		"en": newNotificationTemplate(
+			"Order {{.OrderNumber}} collected",
+			"Enjoy your meal {{.FirstName}}! You collected your order from {{.RestaurantName}}.",
+		),
+		"es": newNotificationTemplate(
+			"Pedido {{.OrderNumber}} recogido",
+			"¡Buen provecho {{.FirstName}}! Has recogido tu pedido de {{.RestaurantName}}.",
+		),
+		"fr": newNotificationTemplate(
+			"Commande {{.OrderNumber}} récupérée",
+			"Bon appétit {{.FirstName}} ! Vous avez récupéré votre commande de {{.RestaurantName}}.",
+		),
+	},
+	"order_acceptance_reminder": {
+		"en": newNotificationTemplate(
+			"Order {{.OrderNumber}} is waiting for you",
+			"{{.RestaurantName}} has a new order {{.OrderNumber}} ({{.Total}}) waiting to be accepted. It will be cancelled if nobody accepts it soon.",
+		),
+		"es": newNotificationTemplate(
+			"El pedido {{.OrderNumber}} te está esperando",
+			"{{.RestaurantName}} tiene un pedido nuevo {{.OrderNumber}} ({{.Total}}) pendiente de aceptar. Se cancelará si nadie lo acepta pronto.",
+		),
+		"fr": newNotificationTemplate(
+			"La commande {{.OrderNumber}} vous attend",
+			"{{.RestaurantName}} a une nouvelle commande {{.OrderNumber}} ({{.Total}}) en attente d'acceptation. Elle sera annulée si personne ne l'accepte rapidement.",
+		),
+	},
+	"restaurant_paused": {
+		"en": newNotificationTemplate(
+			"{{.RestaurantName}} has been closed",
+			"{{.RestaurantName}} stopped taking orders because orders were not accepted in time. Open it again from your dashboard when you're ready.",
+		),
+		"es": newNotificationTemplate(
+			"{{.RestaurantName}} se ha cerrado",
+			"{{.RestaurantName}} ha dejado de aceptar pedidos porque no se aceptaron pedidos a tiempo. Vuelve a abrirlo desde tu panel cuando estés listo.",
+		),
+		"fr": newNotificationTemplate(
+			"{{.RestaurantName}} a été fermé",
+			"{{.RestaurantName}} n'accepte plus de commandes car des commandes n'ont pas été acceptées à temps. Rouvrez-le depuis votre tableau de bord quand vous serez prêt.",
+		),
+	},
+	"menu_low_stock": {
+		"en": newNotificationTemplate(
+			"{{.ItemName}} is {{if .Stock}}running low{{else}}sold out{{end}}",
+			"{{if .Stock}}{{.RestaurantName}} has only {{.Stock}} left of {{.ItemName}}.{{else}}{{.RestaurantName}} has sold out of {{.ItemName}}. It is unavailable until it's restocked.{{end}}",
+		),
+		"es": newNotificationTemplate(
+			"{{.ItemName}} {{if .Stock}}se está agotando{{else}}está agotado{{end}}",
+			"{{if .Stock}}A {{.RestaurantName}} solo le quedan {{.Stock}} de {{.ItemName}}.{{else}}A {{.RestaurantName}} se le ha agotado {{.ItemName}}. No estará disponible hasta que se reponga.{{end}}",
+		),
+		"fr": newNotificationTemplate(
+			"{{.ItemName}} {{if .Stock}}est bientôt épuisé{{else}}est épuisé{{end}}",
+			"{{if .Stock}}Il ne reste que {{.Stock}} {{.ItemName}} chez {{.RestaurantName}}.{{else}}{{.RestaurantName}} n'a plus de {{.ItemName}}. Il reste indisponible jusqu'au réapprovisionnement.{{end}}",
+		),
+	},
+	"order_cancelled": {
+		"en": newNotificationTemplate(
+			"Order {{.OrderNumber}} cancelled",
+			"Your order {{.OrderNumber}} from {{.RestaurantName}} was cancelled.{{if .Message}} {{.Message}}{{end}}",
+		),
+		"es": newNotificationTemplate(
+			"Pedido {{.OrderNumber}} cancelado",
+			"Tu pedido {{.OrderNumber}} de {{.RestaurantName}} fue cancelado.{{if .Message}} {{.Message}}{{end}}",
+		),
+		"fr": newNotificationTemplate(
+			"Commande {{.OrderNumber}} annulée",
+			"Votre commande {{.OrderNumber}} de {{.RestaurantName}} a été annulée.{{if .Message}} {{.Message}}{{end}}",
+		),
+	},
+	"refund_approved": {
+		"en": newNotificationTemplate(
+			"Refund approved for order {{.OrderNumber}}",
+			"Hi {{.FirstName}}, {{.RestaurantName}} approved a refund of {{.Total}} for order {{.OrderNumber}}.{{if .Message}} {{.Message}}{{end}}",
+		),
+		"es": newNotificationTemplate(
+			"Reembolso aprobado para el pedido {{.OrderNumber}}",
+			"Hola {{.FirstName}}, {{.RestaurantName}} aprobó un reembolso de {{.Total}} para el pedido {{.OrderNumber}}.{{if .Message}} {{.Message}}{{end}}",
+		),
+		"fr": newNotificationTemplate(
+			"Remboursement accepté pour la commande {{.OrderNumber}}",
+			"Bonjour {{.FirstName}}, {{.RestaurantName}} a accepté un remboursement de {{.Total}} pour la commande {{.OrderNumber}}.{{if .Message}} {{.Message}}{{end}}",
+		),
+	},
+	"refund_denied": {
+		"en": newNotificationTemplate(
+			"Refund request for order {{.OrderNumber}} declined",
+			"Hi {{.FirstName}}, your refund request for order {{.OrderNumber}} from {{.RestaurantName}} was declined.{{if .Message}} {{.Message}}{{end}}",
+		),
+		"es": newNotificationTemplate(
+			"Solicitud de reembolso del pedido {{.OrderNumber}} rechazada",
+			"Hola {{.FirstName}}, tu solicitud de reembolso del pedido {{.OrderNumber}} de {{.RestaurantName}} fue rechazada.{{if .Message}} {{.Message}}{{end}}",
+		),
+		"fr": newNotificationTemplate(
+			"Demande de remboursement refusée pour la commande {{.OrderNumber}}",
+			"Bonjour {{.FirstName}}, votre demande de remboursement pour la commande {{.OrderNumber}} de {{.RestaurantName}} a été refusée.{{if .Message}} {{.Message}}{{end}}",
+		),
+	},
+}
+
+func newNotificationTemplate(subject, body string) notificationTemplate {
+	return notificationTemplate{
+		Subject: template.Must(template.New("subject").Parse(subject)),
+		Body:    template.Must(template.New("body").Parse(body)),
+	}
+}
+
+// hasNotificationTemplate reports whether a template exists for name.
+func hasNotificationTemplate(name string) bool {
+	_, ok := notificationTemplates[name]
+	return ok
+}
+
+// renderNotification renders a template in the given locale, falling back to
+// DefaultLocale when it hasn't been translated.
+func renderNotification(name, locale string, data notificationData) (string, string, error) {
+	translations, ok := notificationTemplates[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown notification template %q", name)
+	}
+
+	tmpl, ok := translations[locale]
+	if !ok {
+		tmpl = translations[DefaultLocale]
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.Subject.Execute(&subject, data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.Body.Execute(&body, data); err != nil {
+		return "", "", err
+	}
+
+	return subject.String(), body.String(), nil
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	outboxBaseBackoff = 5 * time.Second
+	outboxMaxBackoff  = 10 * time.Minute
+)
+
+// EventConsumer handles outbox events. Events are delivered at least once;
+// Handle runs in the same transaction that records the consumption, so an
+// event is only handed to a consumer again if that transaction rolled back.
+type EventConsumer interface {
+	Name() string
+	Handle(tx *gorm.DB, event *models.OutboxEvent) error
+}
+
+// PublishEvent writes a domain event to the outbox using the caller's
+// transaction, so the event exists if and only if the change commits.
+func PublishEvent(tx *gorm.DB, aggregateType string, aggregateID uuid.UUID, eventType models.EventType, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event payload: %v", err)
+	}
+
+	event := models.OutboxEvent{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       string(body),
+		NextAttemptAt: time.Now(),
+	}
+	return tx.Create(&event).Error
+}
+
+type OutboxRelay struct {
+	db        *repository.Database
+	cfg       *config.OutboxConfig
+	consumers []EventConsumer
+}
+
+func NewOutboxRelay(db *repository.Database, cfg *config.Config, consumers ...EventConsumer) *OutboxRelay {
+	return &OutboxRelay{
+		db:        db,
+		cfg:       &cfg.Outbox,
+		consumers: consumers,
+	}
+}
+
+// Start polls the outbox until ctx is cancelled.
+func (r *OutboxRelay) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.processPending(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *OutboxRelay) processPending(ctx context.Context) {
+	events, err := r.claimPending()
+	if err != nil {
+		log.Printf("outbox: failed to claim events: %v", err)
+		return
+	}
+
+	for i := range events {
+		if ctx.Err() != nil {
+			return
+		}
+		r.dispatch(&events[i])
+	}
+}
+
+// claimPending locks a batch of unprocessed events in creation order and leases
+// them so other relay instances skip them while they are being dispatched.
+func (r *OutboxRelay) claimPending() ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+
+	err := r.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("processed_at IS NULL AND next_attempt_at <= ?", time.Now()).
+			Order("created_at ASC").
+			Limit(r.cfg.BatchSize).
+			Find(&events).Error; err != nil {
+			return err
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, len(events))
+		for i, event := range events {
+			ids[i] = event.ID
+		}
+
+		lease := time.Now().Add(r.cfg.LeaseDuration)
+		return tx.Model(&models.OutboxEvent{}).Where("id IN ?", ids).Update("next_attempt_at", lease).Error
+	})
+
+	return events, err
+}
+
+func (r *OutboxRelay) dispatch(event *models.OutboxEvent) {
+	var failures []string
+	for _, consumer := range r.consumers {
+		if err := r.consume(consumer, event); err != nil {
+			failures = append(failures, consumer.Name()+": "+err.Error())
+		}
+	}
+
+	updates := map[string]interface{}{
+		"attempts": event.Attempts + 1,
+	}
+	if len(failures) == 0 {
+		updates["processed_at"] = time.Now()
+		updates["last_error"] = ""
+	} else {
+		updates["next_attempt_at"] = time.Now().Add(outboxBackoff(event.Attempts + 1))
+		updates["last_error"] = strings.Join(failures, "; ")
+		log.Printf("outbox: event %s (%s) failed: %s", event.ID, event.EventType, updates["last_error"])
+	}
+
+	if err := r.db.DB.Model(event).Updates(updates).Error; err != nil {
+		log.Printf("outbox: failed to update event %s: %v", event.ID, err)
+	}
+}
+
+// consume runs one consumer in its own transaction, skipping events it has
+// already recorded as consumed.
+func (r *OutboxRelay) consume(consumer EventConsumer, event *models.OutboxEvent) error {
+	return r.db.DB.Transaction(func(tx *gorm.DB) error {
+		consumption := models.OutboxConsumption{
+			EventID:    event.ID,
+			Consumer:   consumer.Name(),
+			ConsumedAt: time.Now(),
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&consumption)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		return consumer.Handle(tx, event)
+	})
+}
+
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// decodeOrderEvent unmarshals the payload of an order.* event.
+func decodeOrderEvent(event *models.OutboxEvent) (*models.OrderEventPayload, error) {
+	var payload models.OrderEventPayload
+	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+		return nil, fmt.Errorf("invalid order event payload: %v", err)
+	}
+	return &payload, nil
+}
+
+// decodeRestaurantEvent unmarshals the payload of a restaurant.* event.
+func decodeRestaurantEvent(event *models.OutboxEvent) (*models.RestaurantEventPayload, error) {
+	var payload models.RestaurantEventPayload
+	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+		return nil, fmt.Errorf("invalid restaurant event payload: %v", err)
+	}
+	return &payload, nil
+}
+
+// decodeStockEvent unmarshals the payload of a menu.low_stock event.
+func decodeStockEvent(event *models.OutboxEvent) (*models.StockEventPayload, error) {
+	var payload models.StockEventPayload
+	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+		return nil, fmt.Errorf("invalid stock event payload: %v", err)
+	}
+	return &payload, nil
+}
+
+// decodeRefundEvent unmarshals the payload of a refund.* event.
+func decodeRefundEvent(event *models.OutboxEvent) (*models.RefundEventPayload, error) {
+	var payload models.RefundEventPayload
+	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+		return nil, fmt.Errorf("invalid refund event payload: %v", err)
+	}
+	return &payload, nil
+}
+
+// decodeReviewEvent unmarshals the payload of a review.* event.
+func decodeReviewEvent(event *models.OutboxEvent) (*models.ReviewEventPayload, error) {
+	var payload models.ReviewEventPayload
+	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+		return nil, fmt.Errorf("invalid review event payload: %v", err)
+	}
+	return &payload, nil
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/models"
+)
+
+// ErrPaymentNotRefundable is returned for orders whose payment can't be sent
+// back through the gateway, such as cash on delivery.
+var ErrPaymentNotRefundable = errors.New("payment method can't be refunded")
+
+// PaymentGateway moves money for orders paid by card or digital wallet.
+// Reference is an idempotency key: calling again with the same reference must
+// not pay out twice.
+type PaymentGateway interface {
+	Refund(ctx context.Context, order *models.Order, amount float64, reference string) (string, error)
+	Void(ctx context.Context, order *models.Order, reference string) (string, error)
+}
+
+// IsGatewayPayment reports whether an order's payment went through the gateway.
+func IsGatewayPayment(method models.PaymentMethodType) bool {
+	return method != models.CashPayment
+}
+
+// LocalPaymentGateway records payment operations as JSON lines in a file
+// instead of calling a payment provider.
+type LocalPaymentGateway struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewLocalPaymentGateway(cfg *config.Config) *LocalPaymentGateway {
+	return &LocalPaymentGateway{
+		path: filepath.Join(cfg.Payment.SinkDir, "payments.log"),
+	}
+}
+
+func (g *LocalPaymentGateway) Refund(ctx context.Context, order *models.Order, amount float64, reference string) (string, error) {
+	if !IsGatewayPayment(order.PaymentMethodType) {
+		return "", ErrPaymentNotRefundable
+	}
+
+	transactionID := "local_refund_" + reference
+	return transactionID, g.record(map[string]interface{}{
+		"operation":     "refund",
+		"transactionId": transactionID,
+		"orderId":       order.ID,
+		"paymentMethod": order.PaymentMethodType,
+		"amount":        amount,
+		"recordedAt":    time.Now().UTC(),
+	})
+}
+
+// Void releases the authorization of an order that was never fulfilled.
+func (g *LocalPaymentGateway) Void(ctx context.Context, order *models.Order, reference string) (string, error) {
+	if !IsGatewayPayment(order.PaymentMethodType) {
+		return "", ErrPaymentNotRefundable
+	}
+
+	transactionID := "local_void_" + reference
+	return transactionID, g.record(map[string]interface{}{
+		"operation":     "void",
+		"transactionId": transactionID,
+		"orderId":       order.ID,
+		"paymentMethod": order.PaymentMethodType,
+		"amount":        order.AmountDue(),
+		"recordedAt":    time.Now().UTC(),
+	})
+}
+
+func (g *LocalPaymentGateway) record(entry map[string]interface{}) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(g.path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(g.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
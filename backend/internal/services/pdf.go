@@ -0,0 +1,159 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// PDF page size (A4) and margins, in points.
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 50.0
+)
+
+// pdfFont is one of the standard Type 1 fonts every PDF reader has, so
+// documents don't need to embed any.
+type pdfFont int
+
+const (
+	pdfRegular pdfFont = iota
+	pdfBold
+	pdfMono
+)
+
+var pdfFontNames = []string{"Helvetica", "Helvetica-Bold", "Courier"}
+
+// pdfDocument is a minimal writer for text-only PDF documents: lines of text
+// in the standard fonts, rules, and automatic page breaks.
+type pdfDocument struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+func newPDFDocument() *pdfDocument {
+	doc := &pdfDocument{}
+	doc.addPage()
+	return doc
+}
+
+func (d *pdfDocument) addPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pdfPageHeight - pdfMargin
+}
+
+func (d *pdfDocument) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// advance moves down by height, starting a new page when there's no room.
+func (d *pdfDocument) advance(height float64) {
+	if d.y-height < pdfMargin {
+		d.addPage()
+	}
+	d.y -= height
+}
+
+// text writes s with its left edge at x on the current line.
+func (d *pdfDocument) text(x float64, font pdfFont, size float64, s string) {
+	fmt.Fprintf(d.page(), "BT /F%d %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font+1, size, x, d.y, pdfEscape(s))
+}
+
+// monoRight writes s in the monospaced font with its right edge at x.
+func (d *pdfDocument) monoRight(x float64, size float64, s string) {
+	width := float64(len(encodeWindows1252(s))) * size * 0.6
+	d.text(x-width, pdfMono, size, s)
+}
+
+// rule draws a horizontal line across the page just below the current line.
+func (d *pdfDocument) rule() {
+	y := d.y - 4
+	fmt.Fprintf(d.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfMargin, y, pdfPageWidth-pdfMargin, y)
+}
+
+// bytes lays out the document objects, cross-reference table and trailer.
+func (d *pdfDocument) bytes() []byte {
+	var out bytes.Buffer
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1: catalog, 2: page tree, 3..5: fonts, then a page and its contents per page
+	firstPage := 3 + len(pdfFontNames)
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	fonts := make([]string, len(pdfFontNames))
+	for i, name := range pdfFontNames {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+		fonts[i] = fmt.Sprintf("/F%d %d 0 R", i+1, 3+i)
+	}
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, strings.Join(fonts, " "), firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+// pdfEscape encodes s for a PDF string literal in WinAnsiEncoding.
+func pdfEscape(s string) string {
+	var out strings.Builder
+	for _, b := range encodeWindows1252(s) {
+		switch b {
+		case '(', ')', '\\':
+			out.WriteByte('\\')
+			out.WriteByte(b)
+		default:
+			out.WriteByte(b)
+		}
+	}
+	return out.String()
+}
+
+// windows1252Extras maps the characters Windows-1252 places in 0x80-0x9F.
+var windows1252Extras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91,
+	'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+	'™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encodeWindows1252 converts s to Windows-1252, the encoding of both the PDF
+// standard fonts and the thermal printer code page. Characters it can't
+// represent become '?'; control characters become spaces.
+func encodeWindows1252(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x20 || r == 0x7F:
+			out = append(out, ' ')
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		default:
+			if b, ok := windows1252Extras[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
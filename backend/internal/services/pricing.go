@@ -0,0 +1,276 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"restaurantapp/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrMenuItemUnavailable is returned when a menu item doesn't exist for the
+// restaurant or can't currently be ordered.
+var ErrMenuItemUnavailable = errors.New("menu item not found or unavailable")
+
+// SelectionError reports customization selections that don't fit the menu
+// item. Its message is safe to show to the customer.
+type SelectionError struct {
+	Message string
+}
+
+func (e *SelectionError) Error() string {
+	return e.Message
+}
+
+type SelectedOption struct {
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	PriceModifier float64   `json:"priceModifier"`
+}
+
+type SelectedCustomization struct {
+	CustomizationID uuid.UUID        `json:"customizationId"`
+	Name            string           `json:"name"`
+	Options         []SelectedOption `json:"options"`
+}
+
+// PricedItem is a menu item with validated selections and its current unit
+// price, base price plus option modifiers. For a combo that includes the
+// items chosen for its slots.
+type PricedItem struct {
+	MenuItem       models.MenuItem
+	Customizations []SelectedCustomization
+	Components     []PricedComponent
+	UnitPrice      float64
+}
+
+// PricedComponent is the menu item chosen for one slot of a combo.
+type PricedComponent struct {
+	SlotID     uuid.UUID
+	Slot       string
+	PriceDelta float64
+	Item       *PricedItem
+}
+
+// OrderComponents describes the combo's components as they are stored on
+// the order item.
+func (p *PricedItem) OrderComponents() []models.OrderItemComponent {
+	if len(p.Components) == 0 {
+		return nil
+	}
+	components := make([]models.OrderItemComponent, len(p.Components))
+	for i, component := range p.Components {
+		stored := models.OrderItemComponent{
+			SlotID:     component.SlotID,
+			Slot:       component.Slot,
+			MenuItemID: component.Item.MenuItem.ID,
+			Name:       component.Item.MenuItem.Name,
+			PriceDelta: component.PriceDelta,
+		}
+		for _, customization := range component.Item.Customizations {
+			selection := models.CustomizationSelection{CustomizationID: customization.CustomizationID}
+			names := make([]string, len(customization.Options))
+			for j, option := range customization.Options {
+				selection.OptionIDs = append(selection.OptionIDs, option.ID)
+				names[j] = option.Name
+			}
+			stored.Selections = append(stored.Selections, selection)
+			stored.Options = append(stored.Options, customization.Name+": "+strings.Join(names, ", "))
+		}
+		components[i] = stored
+	}
+	return components
+}
+
+// PriceOrderItem loads a menu item of the restaurant, validates the
+// customization selections against it and prices it from current data,
+// filling the slots of a combo with components. A combo costs its own bundle price plus the price
+// delta of each chosen item and that item's option modifiers; the chosen
+// items' own prices don't count.
+func PriceOrderItem(tx *gorm.DB, restaurantID, menuItemID uuid.UUID, selections []models.CustomizationSelection, components []models.ComboSelection) (*PricedItem, error) {
+	priced, err := priceMenuItem(tx, restaurantID, menuItemID, selections)
+	if err != nil {
+		return nil, err
+	}
+	if !priced.MenuItem.IsCombo {
+		if len(components) > 0 {
+			return nil, &SelectionError{Message: priced.MenuItem.Name + " is not a combo"}
+		}
+		return priced, nil
+	}
+	if err := priceComponents(tx, restaurantID, priced, components); err != nil {
+		return nil, err
+	}
+	return priced, nil
+}
+
+func priceComponents(tx *gorm.DB, restaurantID uuid.UUID, priced *PricedItem, components []models.ComboSelection) error {
+	combo := priced.MenuItem.Name
+	var slots []models.ComboSlot
+	if err := tx.Preload("Options").
+		Where("combo_id = ?", priced.MenuItem.ID).
+		Order("position, id").
+		Find(&slots).Error; err != nil {
+		return err
+	}
+
+	chosen := make(map[uuid.UUID]models.ComboSelection, len(components))
+	for _, component := range components {
+		if _, duplicate := chosen[component.SlotID]; duplicate {
+			return &SelectionError{Message: combo + ": slot chosen more than once"}
+		}
+		chosen[component.SlotID] = component
+	}
+
+	for _, slot := range slots {
+		component, ok := chosen[slot.ID]
+		delete(chosen, slot.ID)
+		if !ok {
+			if slot.Optional {
+				continue
+			}
+			return &SelectionError{Message: fmt.Sprintf("%s: choose a %s", combo, slot.Name)}
+		}
+
+		var option *models.ComboSlotOption
+		for i := range slot.Options {
+			if slot.Options[i].MenuItemID == component.MenuItemID {
+				option = &slot.Options[i]
+			}
+		}
+		if option == nil {
+			return &SelectionError{Message: fmt.Sprintf("%s: that item can't be chosen for %s", combo, slot.Name)}
+		}
+
+		item, err := priceMenuItem(tx, restaurantID, component.MenuItemID, component.Selections)
+		if err != nil {
+			if err == ErrMenuItemUnavailable {
+				return &SelectionError{Message: fmt.Sprintf("%s: the %s chosen is unavailable", combo, slot.Name)}
+			}
+			if selectionErr, ok := err.(*SelectionError); ok {
+				return &SelectionError{Message: fmt.Sprintf("%s, %s: %s", combo, slot.Name, selectionErr.Message)}
+			}
+			return err
+		}
+
+		priced.Components = append(priced.Components, PricedComponent{
+			SlotID:     slot.ID,
+			Slot:       slot.Name,
+			PriceDelta: option.PriceDelta,
+			Item:       item,
+		})
+		priced.UnitPrice += option.PriceDelta + item.UnitPrice - item.MenuItem.Price
+	}
+
+	if len(chosen) > 0 {
+		return &SelectionError{Message: combo + ": unknown slot"}
+	}
+	return nil
+}
+
+func priceMenuItem(tx *gorm.DB, restaurantID, menuItemID uuid.UUID, selections []models.CustomizationSelection) (*PricedItem, error) {
+	var menuItem models.MenuItem
+	if err := tx.Preload("Customizations.Options").
+		Where("id = ? AND restaurant_id = ?", menuItemID, restaurantID).
+		First(&menuItem).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrMenuItemUnavailable
+		}
+		return nil, err
+	}
+	if !menuItem.IsAvailable || menuItem.IsPaused(time.Now()) {
+		return nil, ErrMenuItemUnavailable
+	}
+
+	// A paused category pauses all of its items
+	var pausedCategories int64
+	if err := tx.Model(&models.MenuCategory{}).
+		Where("id = ? AND paused_until > ?", menuItem.CategoryID, time.Now()).
+		Count(&pausedCategories).Error; err != nil {
+		return nil, err
+	}
+	if pausedCategories > 0 {
+		return nil, ErrMenuItemUnavailable
+	}
+
+	selected := make(map[uuid.UUID][]uuid.UUID, len(selections))
+	for _, selection := range selections {
+		if _, duplicate := selected[selection.CustomizationID]; duplicate {
+			return nil, &SelectionError{Message: "Customization selected more than once"}
+		}
+		selected[selection.CustomizationID] = selection.OptionIDs
+	}
+
+	priced := &PricedItem{
+		MenuItem:       menuItem,
+		Customizations: []SelectedCustomization{},
+		UnitPrice:      menuItem.Price,
+	}
+
+	for _, customization := range menuItem.Customizations {
+		optionIDs := selected[customization.ID]
+		delete(selected, customization.ID)
+
+		if len(optionIDs) == 0 {
+			if customization.Required {
+				return nil, &SelectionError{Message: fmt.Sprintf("%s: a selection is required", customization.Name)}
+			}
+			continue
+		}
+
+		maxSelections := customization.MaxSelections
+		if maxSelections < 1 {
+			maxSelections = 1
+		}
+		if len(optionIDs) > maxSelections {
+			return nil, &SelectionError{Message: fmt.Sprintf("%s: at most %d selections allowed", customization.Name, maxSelections)}
+		}
+
+		chosen := SelectedCustomization{
+			CustomizationID: customization.ID,
+			Name:            customization.Name,
+		}
+		seen := make(map[uuid.UUID]bool, len(optionIDs))
+		for _, optionID := range optionIDs {
+			if seen[optionID] {
+				return nil, &SelectionError{Message: fmt.Sprintf("%s: option selected more than once", customization.Name)}
+			}
+			seen[optionID] = true
+
+			option := findCustomizationOption(customization.Options, optionID)
+			if option == nil {
+				return nil, &SelectionError{Message: fmt.Sprintf("%s: unknown option", customization.Name)}
+			}
+			if !option.IsAvailable {
+				return nil, &SelectionError{Message: fmt.Sprintf("%s: %s is no longer available", customization.Name, option.Name)}
+			}
+
+			chosen.Options = append(chosen.Options, SelectedOption{
+				ID:            option.ID,
+				Name:          option.Name,
+				PriceModifier: option.PriceModifier,
+			})
+			priced.UnitPrice += option.PriceModifier
+		}
+		priced.Customizations = append(priced.Customizations, chosen)
+	}
+
+	if len(selected) > 0 {
+		return nil, &SelectionError{Message: "Unknown customization for this menu item"}
+	}
+
+	return priced, nil
+}
+
+func findCustomizationOption(options []models.CustomizationOption, id uuid.UUID) *models.CustomizationOption {
+	for i := range options {
+		if options[i].ID == id {
+			return &options[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"restaurantapp/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PromotionError explains why a promo code can't be applied. Its message is
+// safe to show to the customer.
+type PromotionError struct {
+	Message string
+}
+
+func (e *PromotionError) Error() string {
+	return e.Message
+}
+
+// NormalizePromoCode returns the canonical, upper-case form of a code.
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ApplyPromotion checks a promo code against an order being placed and returns
+// the promotion with the discount it gives. The promotion row stays locked for
+// the rest of the transaction so usage limits hold under concurrent checkouts;
+// call RedeemPromotion in the same transaction once the order exists.
+func ApplyPromotion(tx *gorm.DB, code string, userID, restaurantID uuid.UUID, subtotal, deliveryFee float64) (*models.Promotion, float64, error) {
+	var promotion models.Promotion
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("code = ?", NormalizePromoCode(code)).
+		First(&promotion).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, 0, &PromotionError{Message: "Promo code not found"}
+		}
+		return nil, 0, err
+	}
+
+	if promotion.RestaurantID != nil && *promotion.RestaurantID != restaurantID {
+		return nil, 0, &PromotionError{Message: "Promo code is not valid for this restaurant"}
+	}
+	if !promotion.IsRunning(time.Now()) {
+		return nil, 0, &PromotionError{Message: "Promo code is not active"}
+	}
+	if subtotal < promotion.MinOrderAmount {
+		return nil, 0, &PromotionError{Message: fmt.Sprintf("Promo code requires a minimum order of %.2f", promotion.MinOrderAmount)}
+	}
+	if promotion.MaxRedemptions != nil && promotion.RedemptionCount >= *promotion.MaxRedemptions {
+		return nil, 0, &PromotionError{Message: "Promo code has reached its usage limit"}
+	}
+
+	if promotion.MaxRedemptionsPerUser != nil {
+		var used int64
+		if err := tx.Model(&models.PromotionRedemption{}).
+			Where("promotion_id = ? AND user_id = ? AND voided_at IS NULL", promotion.ID, userID).
+			Count(&used).Error; err != nil {
+			return nil, 0, err
+		}
+		if used >= int64(*promotion.MaxRedemptionsPerUser) {
+			return nil, 0, &PromotionError{Message: "You have already used this promo code"}
+		}
+	}
+
+	if promotion.FirstOrderOnly {
+		var previousOrders int64
+		if err := tx.Model(&models.Order{}).
+			Where("user_id = ? AND status <> ?", userID, models.CancelledStatus).
+			Count(&previousOrders).Error; err != nil {
+			return nil, 0, err
+		}
+		if previousOrders > 0 {
+			return nil, 0, &PromotionError{Message: "Promo code is only valid on your first order"}
+		}
+	}
+
+	discount := promotion.Discount(subtotal, deliveryFee)
+	if discount <= 0 {
+		return nil, 0, &PromotionError{Message: "Promo code does not apply to this order"}
+	}
+
+	return &promotion, discount, nil
+}
+
+// RedeemPromotion records the use of a promotion on a newly created order.
+func RedeemPromotion(tx *gorm.DB, promotion *models.Promotion, order *models.Order) error {
+	redemption := models.PromotionRedemption{
+		PromotionID:    promotion.ID,
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		RestaurantID:   order.RestaurantID,
+		Code:           promotion.Code,
+		DiscountAmount: order.DiscountAmount,
+		OrderSubtotal:  order.TotalAmount,
+	}
+	if err := tx.Create(&redemption).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(promotion).Update("redemption_count", gorm.Expr("redemption_count + 1")).Error
+}
+
+// PromotionConsumer voids redemptions of cancelled orders so they no longer
+// count towards usage limits.
+type PromotionConsumer struct{}
+
+func NewPromotionConsumer() *PromotionConsumer {
+	return &PromotionConsumer{}
+}
+
+func (pc *PromotionConsumer) Name() string {
+	return "promotions"
+}
+
+func (pc *PromotionConsumer) Handle(tx *gorm.DB, event *models.OutboxEvent) error {
+	if event.EventType != models.OrderStatusChangedEvent {
+		return nil
+	}
+
+	payload, err := decodeOrderEvent(event)
+	if err != nil {
+		return err
+	}
+	if payload.Status != models.CancelledStatus {
+		return nil
+	}
+
+	var redemption models.PromotionRedemption
+	if err := tx.Where("order_id = ? AND voided_at IS NULL", payload.OrderID).First(&redemption).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+
+	if err := tx.Model(&redemption).Update("voided_at", time.Now()).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&models.Promotion{}).
+		Where("id = ? AND redemption_count > 0", redemption.PromotionID).
+		Update("redemption_count", gorm.Expr("redemption_count - 1")).Error
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"restaurantapp/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RatingConsumer keeps Restaurant.Rating and ReviewCount in line with its reviews.
+type RatingConsumer struct{}
+
+func NewRatingConsumer() *RatingConsumer {
+	return &RatingConsumer{}
+}
+
+func (rc *RatingConsumer) Name() string {
+	return "ratings"
+}
+
+func (rc *RatingConsumer) Handle(tx *gorm.DB, event *models.OutboxEvent) error {
+	switch event.EventType {
+	case models.ReviewCreatedEvent, models.ReviewUpdatedEvent, models.ReviewDeletedEvent:
+	default:
+		return nil
+	}
+
+	payload, err := decodeReviewEvent(event)
+	if err != nil {
+		return err
+	}
+
+	return RecalculateRestaurantRating(tx, payload.RestaurantID)
+}
+
+// RecalculateRestaurantRating recomputes the rating aggregates from scratch,
+// which makes it safe to run any number of times for the same event.
+func RecalculateRestaurantRating(tx *gorm.DB, restaurantID uuid.UUID) error {
+	var aggregate struct {
+		AvgRating   float64
+		ReviewCount int64
+	}
+
+	if err := tx.Model(&models.Review{}).
+		Select("COALESCE(AVG(rating), 0) AS avg_rating, COUNT(*) AS review_count").
+		Where("restaurant_id = ?", restaurantID).
+		Scan(&aggregate).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&models.Restaurant{}).Where("id = ?", restaurantID).Updates(map[string]interface{}{
+		"rating":       aggregate.AvgRating,
+		"review_count": aggregate.ReviewCount,
+	}).Error
+}
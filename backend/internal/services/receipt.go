@@ -0,0 +1,342 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"restaurantapp/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrOrderNotInvoiceable is returned for orders that were never invoiced and
+// are still pending or were cancelled.
+var ErrOrderNotInvoiceable = errors.New("pending and cancelled orders can't be invoiced")
+
+// IssueInvoice returns the order's invoice, issuing it with the restaurant's
+// next sequence number the first time. Orders are only invoiced once the
+// restaurant has accepted them, as pending orders can still be cancelled. The
+// order row is locked and its status checked under the lock, and the
+// restaurant row is locked while the number is taken, so numbers stay
+// sequential without gaps.
+func IssueInvoice(tx *gorm.DB, order *models.Order) (*models.Invoice, error) {
+	var locked models.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "status").
+		Where("id = ?", order.ID).
+		First(&locked).Error; err != nil {
+		return nil, err
+	}
+
+	var invoice models.Invoice
+	err := tx.Where("order_id = ?", order.ID).First(&invoice).Error
+	if err == nil {
+		return &invoice, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if locked.Status == models.PendingStatus || locked.Status == models.CancelledStatus {
+		return nil, ErrOrderNotInvoiceable
+	}
+
+	var restaurant models.Restaurant
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "last_invoice_sequence").
+		Where("id = ?", order.RestaurantID).
+		First(&restaurant).Error; err != nil {
+		return nil, err
+	}
+
+	sequence := restaurant.LastInvoiceSequence + 1
+	if err := tx.Model(&restaurant).Update("last_invoice_sequence", sequence).Error; err != nil {
+		return nil, err
+	}
+
+	invoice = models.Invoice{
+		RestaurantID: order.RestaurantID,
+		OrderID:      order.ID,
+		Sequence:     sequence,
+		Number:       fmt.Sprintf("INV-%06d", sequence),
+		IssuedAt:     time.Now().UTC(),
+	}
+	if err := tx.Create(&invoice).Error; err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+func formatMoney(amount float64) string {
+	if amount < 0 {
+		return fmt.Sprintf("-$%.2f", -amount)
+	}
+	return fmt.Sprintf("$%.2f", amount)
+}
+
+// orderNumber is the short order reference shown to customers and staff.
+func orderNumber(order *models.Order) string {
+	return "#" + strings.ToUpper(order.ID.String()[:8])
+}
+
+// customizationLines describes an order item's selected options, one line
+// per customization.
+func customizationLines(item *models.OrderItem) []string {
+	var lines []string
+	for _, customization := range decodeCustomizations(item.CustomizationsData) {
+		names := make([]string, len(customization.Options))
+		for i, option := range customization.Options {
+			names[i] = option.Name
+		}
+		lines = append(lines, customization.Name+": "+strings.Join(names, ", "))
+	}
+	return lines
+}
+
+// BuildInvoicePDF renders the customer invoice for an order. The order needs
+// its Restaurant, User, DeliveryAddress and Items loaded.
+func BuildInvoicePDF(order *models.Order, invoice *models.Invoice) []byte {
+	doc := newPDFDocument()
+	right := pdfPageWidth - pdfMargin
+
+	doc.advance(18)
+	doc.text(pdfMargin, pdfBold, 18, order.Restaurant.Name)
+	doc.monoRight(right, 12, "INVOICE")
+	doc.advance(14)
+	doc.text(pdfMargin, pdfRegular, 10, order.Restaurant.Address)
+	doc.monoRight(right, 10, invoice.Number)
+	doc.advance(14)
+	doc.text(pdfMargin, pdfRegular, 10, strings.TrimSpace(order.Restaurant.Phone+"  "+order.Restaurant.Email))
+	doc.monoRight(right, 10, invoice.IssuedAt.Format("2006-01-02"))
+
+	doc.advance(30)
+	doc.text(pdfMargin, pdfBold, 10, "Billed to")
+	doc.text(320, pdfBold, 10, "Order")
+	doc.advance(14)
+	doc.text(pdfMargin, pdfRegular, 10, strings.TrimSpace(order.User.FirstName+" "+order.User.LastName))
+	doc.text(320, pdfRegular, 10, orderNumber(order)+" placed "+order.CreatedAt.UTC().Format("2006-01-02 15:04")+" UTC")
+	doc.advance(14)
+	if order.DeliveryAddress != nil {
+		doc.text(pdfMargin, pdfRegular, 10, order.DeliveryAddress.Street)
+	} else {
+		doc.text(pdfMargin, pdfRegular, 10, order.User.Email)
+	}
+	fulfilment := strings.ReplaceAll(string(order.FulfilmentType), "_", "-")
+	if order.TableNumber != "" {
+		fulfilment += ", table " + order.TableNumber
+	}
+	doc.text(320, pdfRegular, 10, "Fulfilment: "+fulfilment)
+	if order.DeliveryAddress != nil {
+		doc.advance(14)
+		address := order.DeliveryAddress
+		doc.text(pdfMargin, pdfRegular, 10, fmt.Sprintf("%s, %s %s, %s", address.City, address.State, address.ZipCode, address.Country))
+	}
+
+	doc.advance(30)
+	doc.text(pdfMargin, pdfBold, 10, "Item")
+	doc.monoRight(400, 10, "Qty")
+	doc.monoRight(470, 10, "Price")
+	doc.monoRight(right, 10, "Amount")
+	doc.rule()
+	doc.advance(8)
+
+	for i := range order.Items {
+		item := &order.Items[i]
+		doc.advance(14)
+		doc.text(pdfMargin, pdfRegular, 10, item.Name)
+		doc.monoRight(400, 10, fmt.Sprintf("%d", item.Quantity))
+		doc.monoRight(470, 10, formatMoney(item.Price))
+		doc.monoRight(right, 10, formatMoney(item.Price*float64(item.Quantity)))
+		for _, line := range customizationLines(item) {
+			doc.advance(12)
+			doc.text(pdfMargin+12, pdfRegular, 8, line)
+		}
+		// A combo stays one priced line; its components are listed under it
+		if len(item.Components) > 0 {
+			names := make([]string, len(item.Components))
+			for j, component := range item.Components {
+				names[j] = component.Name
+			}
+			doc.advance(12)
+			doc.text(pdfMargin+12, pdfRegular, 8, "With: "+strings.Join(names, ", "))
+		}
+		if item.SpecialInstructions != "" {
+			doc.advance(12)
+			doc.text(pdfMargin+12, pdfRegular, 8, "Note: "+item.SpecialInstructions)
+		}
+	}
+	doc.rule()
+	doc.advance(8)
+
+	totals := []struct {
+		label  string
+		amount float64
+		always bool
+	}{
+		{"Subtotal", order.TotalAmount, true},
+		{"Discount" + promoSuffix(order), -order.DiscountAmount, false},
+		{"Delivery fee", order.DeliveryFee, order.FulfilmentType == models.DeliveryFulfilment},
+		{"Tax", order.Tax, true},
+		{"Tip", order.Tip, false},
+	}
+	for _, total := range totals {
+		if !total.always && total.amount == 0 {
+			continue
+		}
+		doc.advance(14)
+		doc.text(340, pdfRegular, 10, total.label)
+		doc.monoRight(right, 10, formatMoney(total.amount))
+	}
+	doc.advance(18)
+	doc.text(340, pdfBold, 12, "Total")
+	doc.monoRight(right, 12, formatMoney(order.GrandTotal()))
+
+	if order.PointsAmount > 0 {
+		doc.advance(14)
+		doc.text(340, pdfRegular, 10, fmt.Sprintf("Paid with %d points", order.PointsRedeemed))
+		doc.monoRight(right, 10, formatMoney(-order.PointsAmount))
+	}
+	doc.advance(14)
+	doc.text(340, pdfRegular, 10, "Paid by "+strings.ReplaceAll(string(order.PaymentMethodType), "_", " "))
+	doc.monoRight(right, 10, formatMoney(order.AmountDue()))
+	if order.RefundedAmount > 0 {
+		doc.advance(14)
+		doc.text(340, pdfRegular, 10, "Refunded")
+		doc.monoRight(right, 10, formatMoney(-order.RefundedAmount))
+	}
+
+	if order.SpecialInstructions != "" {
+		doc.advance(30)
+		doc.text(pdfMargin, pdfRegular, 9, "Instructions: "+order.SpecialInstructions)
+	}
+	doc.advance(30)
+	doc.text(pdfMargin, pdfRegular, 9, "Thank you for your order!")
+
+	return doc.bytes()
+}
+
+func promoSuffix(order *models.Order) string {
+	if order.PromoCode == "" {
+		return ""
+	}
+	return " (" + order.PromoCode + ")"
+}
+
+// ESC/POS commands for the kitchen printer.
+var (
+	escposInit       = []byte{0x1B, 0x40}
+	escposCodePage   = []byte{0x1B, 0x74, 16} // WPC1252
+	escposAlignLeft  = []byte{0x1B, 0x61, 0}
+	escposAlignMid   = []byte{0x1B, 0x61, 1}
+	escposBoldOn     = []byte{0x1B, 0x45, 1}
+	escposBoldOff    = []byte{0x1B, 0x45, 0}
+	escposSizeDouble = []byte{0x1D, 0x21, 0x11}
+	escposSizeTall   = []byte{0x1D, 0x21, 0x01}
+	escposSizeNormal = []byte{0x1D, 0x21, 0x00}
+	escposFeedCut    = []byte{0x1D, 0x56, 0x42, 3}
+)
+
+// escposLineWidth is the number of Font A characters on an 80mm roll.
+const escposLineWidth = 48
+
+// BuildKitchenTicket renders an order as a raw ESC/POS job for an 80mm
+// thermal printer: what to cook, without prices. The order needs its Items
+// loaded.
+func BuildKitchenTicket(order *models.Order, now time.Time) []byte {
+	var out bytes.Buffer
+	write := func(parts ...[]byte) {
+		for _, part := range parts {
+			out.Write(part)
+		}
+	}
+	line := func(s string) {
+		for _, wrapped := range wrapText(s, escposLineWidth) {
+			out.Write(encodeWindows1252(wrapped))
+			out.WriteByte('\n')
+		}
+	}
+
+	write(escposInit, escposCodePage, escposAlignMid, escposSizeDouble, escposBoldOn)
+	line(orderNumber(order))
+	write(escposSizeTall)
+	heading := strings.ToUpper(strings.ReplaceAll(string(order.FulfilmentType), "_", "-"))
+	if order.TableNumber != "" {
+		heading += " - TABLE " + order.TableNumber
+	}
+	line(heading)
+	write(escposSizeNormal, escposBoldOff)
+	line("Placed " + order.CreatedAt.UTC().Format("15:04") + " UTC")
+	if order.EstimatedDeliveryTime != nil {
+		line("Due " + order.EstimatedDeliveryTime.UTC().Format("15:04") + " UTC")
+	}
+	write(escposAlignLeft)
+	line(strings.Repeat("-", escposLineWidth))
+
+	for i := range order.Items {
+		item := &order.Items[i]
+		write(escposSizeTall, escposBoldOn)
+		line(fmt.Sprintf("%d x %s", item.Quantity, item.Name))
+		write(escposSizeNormal, escposBoldOff)
+		for _, customization := range customizationLines(item) {
+			line("   " + customization)
+		}
+		// The kitchen cooks a combo's components, each on its own line
+		for _, component := range item.Components {
+			write(escposBoldOn)
+			line(fmt.Sprintf("   > %d x %s", item.Quantity, component.Name))
+			write(escposBoldOff)
+			for _, option := range component.Options {
+				line("       " + option)
+			}
+		}
+		if item.SpecialInstructions != "" {
+			line("   ! " + item.SpecialInstructions)
+		}
+	}
+
+	line(strings.Repeat("-", escposLineWidth))
+	if order.SpecialInstructions != "" {
+		write(escposBoldOn)
+		line("NOTE: " + order.SpecialInstructions)
+		write(escposBoldOff)
+	}
+	line("Printed " + now.UTC().Format("2006-01-02 15:04") + " UTC")
+	write(escposFeedCut)
+
+	return out.Bytes()
+}
+
+// wrapText breaks s into lines of at most width characters, at spaces where
+// it can. Continuation lines keep the indentation of the first.
+func wrapText(s string, width int) []string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return []string{s}
+	}
+
+	indent := 0
+	for indent < len(runes) && runes[indent] == ' ' {
+		indent++
+	}
+	if indent > width/2 {
+		indent = 0
+	}
+
+	var lines []string
+	for len(runes) > width {
+		cut := width
+		for i := width; i > indent; i-- {
+			if runes[i] == ' ' {
+				cut = i
+				break
+			}
+		}
+		lines = append(lines, strings.TrimRight(string(runes[:cut]), " "))
+		rest := strings.TrimLeft(string(runes[cut:]), " ")
+		runes = []rune(strings.Repeat(" ", indent) + rest)
+	}
+	return append(lines, string(runes))
+}
@@ -0,0 +1,399 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RefundError explains why a refund can't be requested or decided. Its
+// message is safe to show to the caller.
+type RefundError struct {
+	Message string
+}
+
+func (e *RefundError) Error() string {
+	return e.Message
+}
+
+// RefundItemRequest is a quantity of one order item a customer wants refunded.
+type RefundItemRequest struct {
+	OrderItemID uuid.UUID `json:"orderItemId" binding:"required"`
+	Quantity    int       `json:"quantity" binding:"required,min=1"`
+}
+
+// RefundDecision is what the restaurant or an admin decides on a refund.
+// Amount and Method are optional overrides when approving.
+type RefundDecision struct {
+	DeciderID uuid.UUID
+	Amount    *float64
+	Method    models.RefundMethod
+	Note      string
+}
+
+// RequestRefund opens a refund for items of a completed order. The order row
+// is locked so concurrent requests can't claim the same quantities twice.
+func RequestRefund(tx *gorm.DB, cfg *config.Config, userID, orderID uuid.UUID, items []RefundItemRequest, reason string, photos []string, method models.RefundMethod) (*models.Refund, error) {
+	var order models.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ?", orderID, userID).
+		First(&order).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &RefundError{Message: "Order not found"}
+		}
+		return nil, err
+	}
+	if err := tx.Where("order_id = ?", order.ID).Find(&order.Items).Error; err != nil {
+		return nil, err
+	}
+
+	if !order.IsCompleted() {
+		return nil, &RefundError{Message: "Only completed orders can be refunded"}
+	}
+
+	var completed models.TrackingUpdate
+	if err := tx.Where("order_id = ? AND status IN ?", order.ID, models.CompletedStatuses).
+		Order("created_at DESC").
+		First(&completed).Error; err == nil {
+		if time.Since(completed.CreatedAt) > cfg.Refund.Window {
+			return nil, &RefundError{Message: "The refund window for this order has closed"}
+		}
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	if method == "" {
+		method = models.RefundToOriginalPayment
+		if !IsGatewayPayment(order.PaymentMethodType) {
+			method = models.RefundToWallet
+		}
+	}
+	if !models.IsValidRefundMethod(method) {
+		return nil, &RefundError{Message: "Invalid refund method"}
+	}
+	if method == models.RefundToOriginalPayment && !IsGatewayPayment(order.PaymentMethodType) {
+		return nil, &RefundError{Message: "Cash orders can only be refunded as wallet credit"}
+	}
+
+	claimed, err := claimedRefundQuantities(tx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ratio, err := refundRatio(tx, &order)
+	if err != nil {
+		return nil, err
+	}
+
+	orderItems := make(map[uuid.UUID]models.OrderItem, len(order.Items))
+	for _, item := range order.Items {
+		orderItems[item.ID] = item
+	}
+
+	requested := make(map[uuid.UUID]int)
+	var itemOrder []uuid.UUID
+	for _, item := range items {
+		if _, seen := requested[item.OrderItemID]; !seen {
+			itemOrder = append(itemOrder, item.OrderItemID)
+		}
+		requested[item.OrderItemID] += item.Quantity
+	}
+
+	refund := models.Refund{
+		OrderID:      order.ID,
+		UserID:       order.UserID,
+		RestaurantID: order.RestaurantID,
+		Status:       models.RefundRequested,
+		Reason:       reason,
+		Photos:       photos,
+		Method:       method,
+	}
+
+	for _, orderItemID := range itemOrder {
+		orderItem, ok := orderItems[orderItemID]
+		if !ok {
+			return nil, &RefundError{Message: "Item is not part of this order"}
+		}
+
+		quantity := requested[orderItemID]
+		if remaining := orderItem.Quantity - claimed[orderItemID]; quantity > remaining {
+			return nil, &RefundError{Message: fmt.Sprintf("Only %d of %s can still be refunded", remaining, orderItem.Name)}
+		}
+
+		amount := roundAmount(orderItem.Price * float64(quantity) * ratio)
+		refund.Items = append(refund.Items, models.RefundItem{
+			OrderItemID: orderItem.ID,
+			Name:        orderItem.Name,
+			Quantity:    quantity,
+			Amount:      amount,
+		})
+		refund.RequestedAmount += amount
+	}
+	refund.RequestedAmount = roundAmount(refund.RequestedAmount)
+
+	if err := tx.Create(&refund).Error; err != nil {
+		return nil, err
+	}
+
+	if err := PublishEvent(tx, "refund", refund.ID, models.RefundRequestedEvent, models.NewRefundEventPayload(&refund, &order)); err != nil {
+		return nil, err
+	}
+
+	return &refund, nil
+}
+
+// ApproveRefund approves a requested refund and records it against the order.
+// Wallet credit is added at once; a payout to the original payment is left to
+// RefundPayoutConsumer, so the gateway is only called once the approval has
+// committed.
+func ApproveRefund(tx *gorm.DB, cfg *config.Config, refundID uuid.UUID, restaurantID *uuid.UUID, decision RefundDecision) (*models.Refund, error) {
+	refund, order, err := lockRequestedRefund(tx, refundID, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := refund.RequestedAmount
+	if decision.Amount != nil {
+		amount = roundAmount(*decision.Amount)
+	}
+	if amount <= 0 || amount > refund.RequestedAmount {
+		return nil, &RefundError{Message: fmt.Sprintf("Refund amount must be between 0 and %.2f", refund.RequestedAmount)}
+	}
+	if order.RefundedAmount+amount > order.GrandTotal()+0.005 {
+		return nil, &RefundError{Message: "Refund would exceed the order total"}
+	}
+
+	method := refund.Method
+	if decision.Method != "" {
+		method = decision.Method
+	}
+	if !models.IsValidRefundMethod(method) {
+		return nil, &RefundError{Message: "Invalid refund method"}
+	}
+
+	now := time.Now()
+	refund.Status = models.RefundApproved
+	refund.Amount = amount
+	refund.Method = method
+	refund.DecisionNote = decision.Note
+	refund.DecidedByID = &decision.DeciderID
+	refund.DecidedAt = &now
+
+	switch method {
+	case models.RefundToWallet:
+		refund.PointsCredited = int(math.Ceil(amount / cfg.Loyalty.PointValue))
+		if err := appendOrderEntry(tx, models.WalletEntry{
+			UserID:   refund.UserID,
+			Type:     models.WalletRefundCredit,
+			Points:   refund.PointsCredited,
+			OrderID:  &order.ID,
+			RefundID: &refund.ID,
+			Reason:   "Refund credit",
+		}); err != nil {
+			return nil, err
+		}
+
+	case models.RefundToOriginalPayment:
+		if !IsGatewayPayment(order.PaymentMethodType) {
+			return nil, &RefundError{Message: "Cash orders can only be refunded as wallet credit"}
+		}
+
+		var paidBack float64
+		if err := tx.Model(&models.Refund{}).
+			Select("COALESCE(SUM(amount), 0)").
+			Where("order_id = ? AND status = ? AND method = ?", order.ID, models.RefundApproved, models.RefundToOriginalPayment).
+			Scan(&paidBack).Error; err != nil {
+			return nil, err
+		}
+		if paidBack+amount > order.AmountDue()+0.005 {
+			return nil, &RefundError{Message: "Refund exceeds what was paid by card; refund the rest as wallet credit"}
+		}
+	}
+
+	if err := tx.Model(refund).Updates(map[string]interface{}{
+		"status":          refund.Status,
+		"amount":          refund.Amount,
+		"method":          refund.Method,
+		"points_credited": refund.PointsCredited,
+		"decision_note":   refund.DecisionNote,
+		"decided_by_id":   refund.DecidedByID,
+		"decided_at":      refund.DecidedAt,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Model(order).Update("refunded_amount", gorm.Expr("refunded_amount + ?", amount)).Error; err != nil {
+		return nil, err
+	}
+
+	if err := PublishEvent(tx, "refund", refund.ID, models.RefundApprovedEvent, models.NewRefundEventPayload(refund, order)); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// RefundPayoutConsumer pays approved refunds back to the original payment
+// through the gateway. The refund ID is the gateway reference, so a payout
+// retried after its transaction rolled back isn't paid twice.
+type RefundPayoutConsumer struct {
+	payments PaymentGateway
+}
+
+func NewRefundPayoutConsumer(payments PaymentGateway) *RefundPayoutConsumer {
+	return &RefundPayoutConsumer{payments: payments}
+}
+
+func (rc *RefundPayoutConsumer) Name() string {
+	return "refund_payout"
+}
+
+func (rc *RefundPayoutConsumer) Handle(tx *gorm.DB, event *models.OutboxEvent) error {
+	if event.EventType != models.RefundApprovedEvent {
+		return nil
+	}
+	payload, err := decodeRefundEvent(event)
+	if err != nil {
+		return err
+	}
+	if payload.Method != models.RefundToOriginalPayment {
+		return nil
+	}
+
+	var refund models.Refund
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", payload.RefundID).
+		First(&refund).Error; err != nil {
+		return err
+	}
+	if refund.PaymentReference != "" {
+		return nil
+	}
+	var order models.Order
+	if err := tx.Where("id = ?", refund.OrderID).First(&order).Error; err != nil {
+		return err
+	}
+
+	reference, err := rc.payments.Refund(context.Background(), &order, refund.Amount, refund.ID.String())
+	if err != nil {
+		return fmt.Errorf("payment refund failed: %v", err)
+	}
+	return tx.Model(&refund).Update("payment_reference", reference).Error
+}
+
+// DenyRefund closes a requested refund without paying anything.
+func DenyRefund(tx *gorm.DB, refundID uuid.UUID, restaurantID *uuid.UUID, decision RefundDecision) (*models.Refund, error) {
+	if strings.TrimSpace(decision.Note) == "" {
+		return nil, &RefundError{Message: "A note explaining the denial is required"}
+	}
+
+	refund, order, err := lockRequestedRefund(tx, refundID, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	refund.Status = models.RefundDenied
+	refund.DecisionNote = decision.Note
+	refund.DecidedByID = &decision.DeciderID
+	refund.DecidedAt = &now
+
+	if err := tx.Model(refund).Updates(map[string]interface{}{
+		"status":        refund.Status,
+		"decision_note": refund.DecisionNote,
+		"decided_by_id": refund.DecidedByID,
+		"decided_at":    refund.DecidedAt,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	if err := PublishEvent(tx, "refund", refund.ID, models.RefundDeniedEvent, models.NewRefundEventPayload(refund, order)); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// lockRequestedRefund loads a refund that is still awaiting a decision, with
+// its order, locking both. A non-nil restaurantID limits it to that restaurant.
+func lockRequestedRefund(tx *gorm.DB, refundID uuid.UUID, restaurantID *uuid.UUID) (*models.Refund, *models.Order, error) {
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", refundID)
+	if restaurantID != nil {
+		query = query.Where("restaurant_id = ?", *restaurantID)
+	}
+
+	var refund models.Refund
+	if err := query.First(&refund).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, &RefundError{Message: "Refund not found"}
+		}
+		return nil, nil, err
+	}
+	if refund.Status != models.RefundRequested {
+		return nil, nil, &RefundError{Message: "Refund has already been " + string(refund.Status)}
+	}
+
+	var order models.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", refund.OrderID).First(&order).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return &refund, &order, nil
+}
+
+// claimedRefundQuantities returns, per order item, the quantity already in
+// refunds that are pending or approved.
+func claimedRefundQuantities(tx *gorm.DB, orderID uuid.UUID) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		OrderItemID uuid.UUID
+		Quantity    int
+	}
+	if err := tx.Model(&models.RefundItem{}).
+		Select("refund_items.order_item_id, SUM(refund_items.quantity) AS quantity").
+		Joins("JOIN refunds ON refunds.id = refund_items.refund_id").
+		Where("refunds.order_id = ? AND refunds.status IN ?", orderID, []models.RefundStatus{models.RefundRequested, models.RefundApproved}).
+		Group("refund_items.order_item_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	claimed := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		claimed[row.OrderItemID] = row.Quantity
+	}
+	return claimed, nil
+}
+
+// refundRatio scales item prices to what the customer actually paid for them:
+// less the share of an item discount, plus the share of tax.
+func refundRatio(tx *gorm.DB, order *models.Order) (float64, error) {
+	if order.TotalAmount <= 0 {
+		return 0, nil
+	}
+
+	itemDiscount := order.DiscountAmount
+	if order.PromotionID != nil && itemDiscount > 0 {
+		var promotion models.Promotion
+		if err := tx.Select("id", "type").Where("id = ?", *order.PromotionID).First(&promotion).Error; err != nil && err != gorm.ErrRecordNotFound {
+			return 0, err
+		}
+		if promotion.Type == models.FreeDeliveryPromotion {
+			itemDiscount = 0
+		}
+	}
+
+	return (order.TotalAmount - itemDiscount + order.Tax) / order.TotalAmount, nil
+}
+
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
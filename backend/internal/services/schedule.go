@@ -0,0 +1,148 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"restaurantapp/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ScheduleError reports a menu schedule that doesn't parse, or an item
+// ordered outside its schedule. Its message is safe to show.
+type ScheduleError struct {
+	Message string
+}
+
+func (e *ScheduleError) Error() string {
+	return e.Message
+}
+
+// MaxMenuSchedules is the most schedules a category or item can have.
+const MaxMenuSchedules = 10
+
+// ParseMenuSchedules validates schedules, normalizing day names to lower
+// case and dropping repeated days.
+func ParseMenuSchedules(schedules []models.MenuSchedule) ([]models.MenuSchedule, error) {
+	if len(schedules) > MaxMenuSchedules {
+		return nil, &ScheduleError{Message: fmt.Sprintf("At most %d schedules are allowed", MaxMenuSchedules)}
+	}
+
+	parsed := make([]models.MenuSchedule, len(schedules))
+	for i, schedule := range schedules {
+		label := fmt.Sprintf("Schedule %d", i+1)
+		window := models.MenuSchedule{
+			StartTime: strings.TrimSpace(schedule.StartTime),
+			EndTime:   strings.TrimSpace(schedule.EndTime),
+			StartDate: strings.TrimSpace(schedule.StartDate),
+			EndDate:   strings.TrimSpace(schedule.EndDate),
+		}
+
+		seen := map[string]bool{}
+		for _, day := range schedule.Days {
+			day = strings.ToLower(strings.TrimSpace(day))
+			if !models.IsValidWeekday(day) {
+				return nil, &ScheduleError{Message: fmt.Sprintf("%s: unknown day %q", label, day)}
+			}
+			if !seen[day] {
+				window.Days = append(window.Days, day)
+			}
+			seen[day] = true
+		}
+
+		for _, value := range []string{window.StartTime, window.EndTime} {
+			if _, err := time.Parse("15:04", value); value != "" && err != nil {
+				return nil, &ScheduleError{Message: fmt.Sprintf("%s: times are HH:MM, not %q", label, value)}
+			}
+		}
+		// Equal times would read as an overnight window open all day
+		start := window.StartTime
+		if start == "" {
+			start = "00:00"
+		}
+		if start == window.EndTime {
+			return nil, &ScheduleError{Message: label + ": start and end times are the same"}
+		}
+		for _, value := range []string{window.StartDate, window.EndDate} {
+			if _, err := time.Parse("2006-01-02", value); value != "" && err != nil {
+				return nil, &ScheduleError{Message: fmt.Sprintf("%s: dates are YYYY-MM-DD, not %q", label, value)}
+			}
+		}
+		if window.StartDate != "" && window.EndDate != "" && window.EndDate < window.StartDate {
+			return nil, &ScheduleError{Message: label + ": end date is before the start date"}
+		}
+		parsed[i] = window
+	}
+	return parsed, nil
+}
+
+// SetCategorySchedules replaces a category's schedules.
+func SetCategorySchedules(tx *gorm.DB, categoryID uuid.UUID, schedules []models.MenuSchedule) error {
+	if err := tx.Where("category_id = ?", categoryID).Delete(&models.MenuSchedule{}).Error; err != nil {
+		return err
+	}
+	for i := range schedules {
+		schedules[i].ID = uuid.Nil
+		schedules[i].CategoryID = &categoryID
+		schedules[i].MenuItemID = nil
+	}
+	if len(schedules) == 0 {
+		return nil
+	}
+	return tx.Create(&schedules).Error
+}
+
+// SetMenuItemSchedules replaces a menu item's schedules.
+func SetMenuItemSchedules(tx *gorm.DB, menuItemID uuid.UUID, schedules []models.MenuSchedule) error {
+	if err := tx.Where("menu_item_id = ?", menuItemID).Delete(&models.MenuSchedule{}).Error; err != nil {
+		return err
+	}
+	for i := range schedules {
+		schedules[i].ID = uuid.Nil
+		schedules[i].CategoryID = nil
+		schedules[i].MenuItemID = &menuItemID
+	}
+	if len(schedules) == 0 {
+		return nil
+	}
+	return tx.Create(&schedules).Error
+}
+
+func describeSchedules(schedules []models.MenuSchedule) string {
+	windows := make([]string, len(schedules))
+	for i := range schedules {
+		windows[i] = schedules[i].String()
+	}
+	return strings.Join(windows, "; ")
+}
+
+// CheckMenuSchedule returns a ScheduleError if the menu item, or its
+// category, isn't served at t in the restaurant's time zone.
+func CheckMenuSchedule(tx *gorm.DB, restaurant *models.Restaurant, menuItem *models.MenuItem, t time.Time) error {
+	var schedules []models.MenuSchedule
+	if err := tx.Where("menu_item_id = ? OR category_id = ?", menuItem.ID, menuItem.CategoryID).
+		Order("created_at, id").
+		Find(&schedules).Error; err != nil {
+		return err
+	}
+
+	var categorySchedules, itemSchedules []models.MenuSchedule
+	for _, schedule := range schedules {
+		if schedule.MenuItemID != nil {
+			itemSchedules = append(itemSchedules, schedule)
+		} else {
+			categorySchedules = append(categorySchedules, schedule)
+		}
+	}
+
+	local := t.In(restaurant.Location())
+	for _, windows := range [][]models.MenuSchedule{categorySchedules, itemSchedules} {
+		if !models.IsOnSchedule(windows, local) {
+			return &ScheduleError{Message: fmt.Sprintf("%s is only served %s (%s time)", menuItem.Name, describeSchedules(windows), restaurant.Location())}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"restaurantapp/internal/models"
+)
+
+func TestParseMenuSchedules(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []models.MenuSchedule
+		want    []models.MenuSchedule
+		wantErr bool
+	}{
+		{name: "none", input: nil, want: []models.MenuSchedule{}},
+		{
+			name: "normalized",
+			input: []models.MenuSchedule{{
+				Days:      []string{" Monday", "friday", "MONDAY"},
+				StartTime: " 07:00 ",
+				EndTime:   "11:00",
+				StartDate: "2026-12-01",
+				EndDate:   " 2026-12-31",
+			}},
+			want: []models.MenuSchedule{{
+				Days:      []string{"monday", "friday"},
+				StartTime: "07:00",
+				EndTime:   "11:00",
+				StartDate: "2026-12-01",
+				EndDate:   "2026-12-31",
+			}},
+		},
+		{
+			name:  "overnight",
+			input: []models.MenuSchedule{{StartTime: "22:00", EndTime: "02:00"}},
+			want:  []models.MenuSchedule{{StartTime: "22:00", EndTime: "02:00"}},
+		},
+		{
+			name:  "single day",
+			input: []models.MenuSchedule{{StartDate: "2026-12-24", EndDate: "2026-12-24"}},
+			want:  []models.MenuSchedule{{StartDate: "2026-12-24", EndDate: "2026-12-24"}},
+		},
+		{name: "unknown day", input: []models.MenuSchedule{{Days: []string{"funday"}}}, wantErr: true},
+		{name: "time out of range", input: []models.MenuSchedule{{StartTime: "25:00"}}, wantErr: true},
+		{name: "time not HH:MM", input: []models.MenuSchedule{{EndTime: "7pm"}}, wantErr: true},
+		{name: "equal times", input: []models.MenuSchedule{{StartTime: "09:00", EndTime: "09:00"}}, wantErr: true},
+		{name: "end at midnight with no start", input: []models.MenuSchedule{{EndTime: "00:00"}}, wantErr: true},
+		{name: "date not YYYY-MM-DD", input: []models.MenuSchedule{{StartDate: "01/12/2026"}}, wantErr: true},
+		{name: "impossible date", input: []models.MenuSchedule{{EndDate: "2026-02-30"}}, wantErr: true},
+		{name: "end date before start date", input: []models.MenuSchedule{{StartDate: "2026-12-02", EndDate: "2026-12-01"}}, wantErr: true},
+		{name: "too many", input: make([]models.MenuSchedule, MaxMenuSchedules+1), wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMenuSchedules(tt.input)
+		if tt.wantErr {
+			var scheduleErr *ScheduleError
+			if !errors.As(err, &scheduleErr) {
+				t.Errorf("%s: err = %v, want a ScheduleError", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Search runs on the search_vector columns of restaurants and menu items,
+// which Postgres keeps up to date on every write. Weights put restaurant names
+// first (A), then cuisines and dish names (B), restaurant descriptions (C) and
+// dish descriptions (D). Trigram matching on names catches typos full-text
+// search misses.
+const (
+	searchQuerySQL = "websearch_to_tsquery('english', @q)"
+
+	restaurantMatchSQL = "restaurants.search_vector @@ " + searchQuerySQL +
+		" OR @q <% restaurants.name" +
+		" OR EXISTS (SELECT 1 FROM menu_items mi WHERE mi.restaurant_id = restaurants.id AND mi.is_available AND mi.search_vector @@ " + searchQuerySQL + ")"
+
+	restaurantRankSQL = "GREATEST(" +
+		"ts_rank(restaurants.search_vector, " + searchQuerySQL + "), " +
+		"COALESCE((SELECT MAX(ts_rank(mi.search_vector, " + searchQuerySQL + ")) FROM menu_items mi" +
+		" WHERE mi.restaurant_id = restaurants.id AND mi.is_available AND mi.search_vector @@ " + searchQuerySQL + "), 0), " +
+		"word_similarity(@q, restaurants.name) * 0.5)"
+)
+
+// MatchedDish is a menu item that matched a search query.
+type MatchedDish struct {
+	ID           uuid.UUID `json:"id"`
+	RestaurantID uuid.UUID `json:"-"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	Price        float64   `json:"price"`
+	Image        string    `json:"image"`
+}
+
+// NormalizeSearchQuery trims q and collapses its whitespace.
+func NormalizeSearchQuery(q string) string {
+	return strings.Join(strings.Fields(q), " ")
+}
+
+// MatchRestaurants limits a restaurants query to those whose name, cuisine or
+// description matches q, whose name is a near miss for it, or that serve an
+// available dish matching it.
+func MatchRestaurants(db *gorm.DB, q string) *gorm.DB {
+	return db.Where(restaurantMatchSQL, map[string]interface{}{"q": q})
+}
+
+// RelevanceRank is the SQL expression ranking a restaurant by how well it, or
+// its best matching dish, matches the named parameter @q.
+const RelevanceRank = restaurantRankSQL
+
+// RelevanceScores works out the RelevanceRank of each of restaurantIDs.
+func RelevanceScores(tx *gorm.DB, restaurantIDs []uuid.UUID, q string) (map[uuid.UUID]float64, error) {
+	scores := make(map[uuid.UUID]float64, len(restaurantIDs))
+	if len(restaurantIDs) == 0 {
+		return scores, nil
+	}
+
+	var rows []struct {
+		ID    uuid.UUID
+		Score float64
+	}
+	if err := tx.Table("restaurants").
+		Select("restaurants.id, "+RelevanceRank+" AS score", map[string]interface{}{"q": q}).
+		Where("restaurants.id IN ?", restaurantIDs).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		scores[row.ID] = row.Score
+	}
+	return scores, nil
+}
+
+// MatchedDishes finds up to perRestaurant available dishes matching q for each
+// restaurant, best match first.
+func MatchedDishes(tx *gorm.DB, restaurantIDs []uuid.UUID, q string, perRestaurant int) (map[uuid.UUID][]MatchedDish, error) {
+	matches := make(map[uuid.UUID][]MatchedDish, len(restaurantIDs))
+	if len(restaurantIDs) == 0 || q == "" {
+		return matches, nil
+	}
+
+	var dishes []MatchedDish
+	if err := tx.Raw(`SELECT id, restaurant_id, name, description, price, image FROM (
+			SELECT mi.id, mi.restaurant_id, mi.name, mi.description, mi.price, mi.image,
+				ROW_NUMBER() OVER (
+					PARTITION BY mi.restaurant_id
+					ORDER BY GREATEST(ts_rank(mi.search_vector, `+searchQuerySQL+`), word_similarity(@q, mi.name) * 0.5) DESC, mi.name
+				) AS position
+			FROM menu_items mi
+			WHERE mi.restaurant_id IN @ids AND mi.is_available
+				AND (mi.search_vector @@ `+searchQuerySQL+` OR @q <% mi.name)
+		) ranked
+		WHERE position <= @limit
+		ORDER BY restaurant_id, position`,
+		map[string]interface{}{"q": q, "ids": restaurantIDs, "limit": perRestaurant},
+	).Scan(&dishes).Error; err != nil {
+		return nil, err
+	}
+
+	for _, dish := range dishes {
+		matches[dish.RestaurantID] = append(matches[dish.RestaurantID], dish)
+	}
+	return matches, nil
+}
+
+// SearchSuggestions returns restaurant names, cuisines and dish names that
+// are close to q, for "did you mean" hints and autocomplete. Close means a
+// trigram word similarity of at least pg_trgm's threshold (0.6 by default),
+// so misspellings like "piza" still find "Pizza".
+func SearchSuggestions(tx *gorm.DB, q string, limit int) ([]string, error) {
+	suggestions := []string{}
+	if q == "" {
+		return suggestions, nil
+	}
+
+	if err := tx.Raw(`SELECT term FROM (
+			SELECT r.name AS term, word_similarity(@q, r.name) AS score
+			FROM restaurants r
+			WHERE r.is_active AND @q <% r.name
+			UNION ALL
+			SELECT r.cuisine_type, word_similarity(@q, r.cuisine_type)
+			FROM restaurants r
+			WHERE r.is_active AND @q <% r.cuisine_type
+			UNION ALL
+			SELECT mi.name, word_similarity(@q, mi.name)
+			FROM menu_items mi
+			JOIN restaurants r ON r.id = mi.restaurant_id
+			WHERE r.is_active AND mi.is_available AND @q <% mi.name
+		) terms
+		WHERE lower(term) <> lower(@q)
+		GROUP BY term
+		ORDER BY MAX(score) DESC, term
+		LIMIT @limit`,
+		map[string]interface{}{"q": q, "limit": limit},
+	).Scan(&suggestions).Error; err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
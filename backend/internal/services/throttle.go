@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"restaurantapp/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrRestaurantAtCapacity is returned when a busy restaurant already has as
+// many orders in the kitchen as it allows.
+var ErrRestaurantAtCapacity = errors.New("restaurant is too busy to take more orders")
+
+// Throttle is what customers see of a restaurant's busy mode.
+type Throttle struct {
+	Busy             bool       `json:"busy"`
+	ExtraPrepMinutes int        `json:"extraPrepMinutes"`
+	MaxActiveOrders  int        `json:"maxActiveOrders,omitempty"`
+	ActiveOrders     int64      `json:"activeOrders"`
+	AcceptingOrders  bool       `json:"acceptingOrders"`
+	BusyUntil        *time.Time `json:"busyUntil,omitempty"`
+}
+
+// RestaurantThrottles works out the throttle state of several restaurants
+// with one count of the orders in their kitchens.
+func RestaurantThrottles(tx *gorm.DB, restaurants []models.Restaurant) (map[uuid.UUID]Throttle, error) {
+	ids := make([]uuid.UUID, len(restaurants))
+	for i, restaurant := range restaurants {
+		ids[i] = restaurant.ID
+	}
+
+	var counts []struct {
+		RestaurantID uuid.UUID
+		Count        int64
+	}
+	if len(ids) > 0 {
+		if err := tx.Model(&models.Order{}).
+			Select("restaurant_id, COUNT(*) AS count").
+			Where("restaurant_id IN ? AND status IN ?", ids, models.KitchenStatuses).
+			Group("restaurant_id").
+			Scan(&counts).Error; err != nil {
+			return nil, err
+		}
+	}
+	active := make(map[uuid.UUID]int64, len(counts))
+	for _, count := range counts {
+		active[count.RestaurantID] = count.Count
+	}
+
+	now := time.Now()
+	throttles := make(map[uuid.UUID]Throttle, len(restaurants))
+	for i := range restaurants {
+		throttles[restaurants[i].ID] = newThrottle(&restaurants[i], active[restaurants[i].ID], now)
+	}
+	return throttles, nil
+}
+
+// RestaurantThrottle works out the throttle state of one restaurant.
+func RestaurantThrottle(tx *gorm.DB, restaurant *models.Restaurant) (Throttle, error) {
+	throttles, err := RestaurantThrottles(tx, []models.Restaurant{*restaurant})
+	if err != nil {
+		return Throttle{}, err
+	}
+	return throttles[restaurant.ID], nil
+}
+
+func newThrottle(restaurant *models.Restaurant, activeOrders int64, now time.Time) Throttle {
+	throttle := Throttle{
+		ActiveOrders:    activeOrders,
+		AcceptingOrders: restaurant.IsActive && restaurant.IsOpen,
+	}
+	if !restaurant.IsBusy(now) {
+		return throttle
+	}
+
+	throttle.Busy = true
+	throttle.ExtraPrepMinutes = restaurant.BusyExtraPrepMinutes
+	throttle.MaxActiveOrders = restaurant.BusyMaxActiveOrders
+	throttle.BusyUntil = restaurant.BusyUntil
+	if throttle.MaxActiveOrders > 0 && activeOrders >= int64(throttle.MaxActiveOrders) {
+		throttle.AcceptingOrders = false
+	}
+	return throttle
+}
+
+// ReserveKitchenCapacity checks that a busy restaurant with an order cap has
+// room for one more order. The restaurant row stays locked until the
+// transaction ends so concurrent checkouts can't overshoot the cap.
+func ReserveKitchenCapacity(tx *gorm.DB, restaurant *models.Restaurant) error {
+	if !restaurant.IsBusy(time.Now()) || restaurant.BusyMaxActiveOrders <= 0 {
+		return nil
+	}
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", restaurant.ID).
+		First(&models.Restaurant{}).Error; err != nil {
+		return err
+	}
+
+	var active int64
+	if err := tx.Model(&models.Order{}).
+		Where("restaurant_id = ? AND status IN ?", restaurant.ID, models.KitchenStatuses).
+		Count(&active).Error; err != nil {
+		return err
+	}
+	if active >= int64(restaurant.BusyMaxActiveOrders) {
+		return ErrRestaurantAtCapacity
+	}
+	return nil
+}
+
+// EstimateReadyAt is when an order placed at t should reach the customer:
+// the restaurant's delivery time for delivery orders, otherwise the longest
+// preparation time of its items, plus any busy-mode extra minutes.
+func EstimateReadyAt(restaurant *models.Restaurant, fulfilmentType models.FulfilmentType, prepMinutes int, t time.Time) time.Time {
+	minutes := prepMinutes
+	if fulfilmentType == models.DeliveryFulfilment && restaurant.MaxDeliveryTime > minutes {
+		minutes = restaurant.MaxDeliveryTime
+	}
+	if restaurant.IsBusy(t) {
+		minutes += restaurant.BusyExtraPrepMinutes
+	}
+	return t.Add(time.Duration(minutes) * time.Minute)
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"math"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WalletError explains why points can't be spent. Its message is safe to show
+// to the customer.
+type WalletError struct {
+	Message string
+}
+
+func (e *WalletError) Error() string {
+	return e.Message
+}
+
+// LockWallet serializes ledger writes for a user until the transaction ends.
+// Every write that can lower a balance takes it before reading the balance.
+func LockWallet(tx *gorm.DB, userID uuid.UUID) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", "wallet:"+userID.String()).Error
+}
+
+// WalletBalance sums the user's ledger.
+func WalletBalance(tx *gorm.DB, userID uuid.UUID) (int, error) {
+	var balance int
+	err := tx.Model(&models.WalletEntry{}).
+		Select("COALESCE(SUM(points), 0)").
+		Where("user_id = ?", userID).
+		Scan(&balance).Error
+	return balance, err
+}
+
+// RedeemPoints spends points on an order. The wallet is locked first so two
+// concurrent checkouts can't both spend the same balance.
+func RedeemPoints(tx *gorm.DB, userID, orderID uuid.UUID, points int) error {
+	if err := LockWallet(tx, userID); err != nil {
+		return err
+	}
+
+	balance, err := WalletBalance(tx, userID)
+	if err != nil {
+		return err
+	}
+	if points > balance {
+		return &WalletError{Message: "Not enough loyalty points"}
+	}
+
+	return tx.Create(&models.WalletEntry{
+		UserID:  userID,
+		Type:    models.WalletRedeem,
+		Points:  -points,
+		OrderID: &orderID,
+		Reason:  "Redeemed on order",
+	}).Error
+}
+
+// AdjustWallet appends a manual adjustment. Debits can't take the balance
+// below zero.
+func AdjustWallet(tx *gorm.DB, userID, adminID uuid.UUID, points int, reason string) (*models.WalletEntry, error) {
+	if err := LockWallet(tx, userID); err != nil {
+		return nil, err
+	}
+
+	if points < 0 {
+		balance, err := WalletBalance(tx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if balance+points < 0 {
+			return nil, &WalletError{Message: "Adjustment would make the balance negative"}
+		}
+	}
+
+	entry := models.WalletEntry{
+		UserID:      userID,
+		Type:        models.WalletAdjustment,
+		Points:      points,
+		Reason:      reason,
+		CreatedByID: &adminID,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// appendOrderEntry writes an order-linked entry once; repeats are ignored.
+func appendOrderEntry(tx *gorm.DB, entry models.WalletEntry) error {
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entry).Error
+}
+
+// PointsValue converts points to currency.
+func PointsValue(cfg *config.LoyaltyConfig, points int) float64 {
+	return math.Round(float64(points)*cfg.PointValue*100) / 100
+}
+
+// LoyaltyConsumer credits points for completed orders, pays back points spent
+// on orders that are cancelled and takes back the share of earned points
+// covered by approved refunds.
+type LoyaltyConsumer struct {
+	cfg *config.LoyaltyConfig
+}
+
+func NewLoyaltyConsumer(cfg *config.Config) *LoyaltyConsumer {
+	return &LoyaltyConsumer{cfg: &cfg.Loyalty}
+}
+
+func (lc *LoyaltyConsumer) Name() string {
+	return "loyalty"
+}
+
+func (lc *LoyaltyConsumer) Handle(tx *gorm.DB, event *models.OutboxEvent) error {
+	if event.EventType == models.RefundApprovedEvent {
+		payload, err := decodeRefundEvent(event)
+		if err != nil {
+			return err
+		}
+		return reverseEarnedPoints(tx, payload)
+	}
+	if event.EventType != models.OrderStatusChangedEvent {
+		return nil
+	}
+
+	payload, err := decodeOrderEvent(event)
+	if err != nil {
+		return err
+	}
+
+	switch payload.Status {
+	case models.DeliveredStatus, models.CollectedStatus:
+		points := int(math.Floor((payload.TotalAmount - payload.DiscountAmount) * lc.cfg.PointsPerUnit))
+		if points <= 0 {
+			return nil
+		}
+		return appendOrderEntry(tx, models.WalletEntry{
+			UserID:  payload.UserID,
+			Type:    models.WalletEarn,
+			Points:  points,
+			OrderID: &payload.OrderID,
+			Reason:  "Earned on completed order",
+		})
+
+	case models.CancelledStatus:
+		return payBackRedeemedPoints(tx, payload.UserID, payload.OrderID, "Order cancelled")
+	}
+
+	return nil
+}
+
+// payBackRedeemedPoints returns the points spent on an order, if any.
+func payBackRedeemedPoints(tx *gorm.DB, userID, orderID uuid.UUID, reason string) error {
+	var redeemed models.WalletEntry
+	if err := tx.Where("order_id = ? AND type = ?", orderID, models.WalletRedeem).First(&redeemed).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+
+	return appendOrderEntry(tx, models.WalletEntry{
+		UserID:  userID,
+		Type:    models.WalletRedeemReversal,
+		Points:  -redeemed.Points,
+		OrderID: &orderID,
+		Reason:  reason,
+	})
+}
+
+// reverseEarnedPoints takes back the points earned on the refunded share of an
+// order, never more than what is left of the original credit.
+func reverseEarnedPoints(tx *gorm.DB, payload *models.RefundEventPayload) error {
+	if payload.OrderTotal <= 0 {
+		return nil
+	}
+
+	var earned models.WalletEntry
+	if err := tx.Where("order_id = ? AND type = ? AND refund_id IS NULL", payload.OrderID, models.WalletEarn).First(&earned).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+
+	var reversed int
+	if err := tx.Model(&models.WalletEntry{}).
+		Select("COALESCE(SUM(-points), 0)").
+		Where("order_id = ? AND type = ?", payload.OrderID, models.WalletEarnReversal).
+		Scan(&reversed).Error; err != nil {
+		return err
+	}
+
+	points := int(math.Ceil(float64(earned.Points) * payload.Amount / payload.OrderTotal))
+	if remaining := earned.Points - reversed; points > remaining {
+		points = remaining
+	}
+	if points <= 0 {
+		return nil
+	}
+
+	return appendOrderEntry(tx, models.WalletEntry{
+		UserID:   payload.UserID,
+		Type:     models.WalletEarnReversal,
+		Points:   -points,
+		OrderID:  &payload.OrderID,
+		RefundID: &payload.RefundID,
+		Reason:   "Order refunded",
+	})
+}
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/models"
+	"restaurantapp/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+)
+
+// WebhookEnvelope is the JSON body POSTed to subscribers.
+type WebhookEnvelope struct {
+	ID        uuid.UUID        `json:"id"`
+	Type      models.EventType `json:"type"`
+	CreatedAt time.Time        `json:"createdAt"`
+	Data      interface{}      `json:"data"`
+}
+
+// ErrWebhookAddressNotAllowed is returned for webhook hosts that resolve to
+// loopback, private, link-local or other non-public addresses.
+var ErrWebhookAddressNotAllowed = errors.New("webhook address is not public")
+
+// sharedAddressSpace is carrier-grade NAT space, which some clouds use for
+// internal services.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// isPublicIP reports whether webhooks may connect to ip.
+func isPublicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !sharedAddressSpace.Contains(ip)
+}
+
+// CheckWebhookHost resolves a webhook URL's host and makes sure every address
+// it resolves to is public, so owners can't point webhooks at the server's
+// own network.
+func CheckWebhookHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !isPublicIP(addr.IP) {
+			return ErrWebhookAddressNotAllowed
+		}
+	}
+	return nil
+}
+
+// newWebhookClient returns a client that only connects to public addresses,
+// checked on every dial so a host re-pointed after registration can't reach
+// internal ones, and that doesn't follow redirects.
+func newWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return ErrWebhookAddressNotAllowed
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+type WebhookService struct {
+	db     *repository.Database
+	cfg    *config.WebhookConfig
+	client *http.Client
+}
+
+func NewWebhookService(db *repository.Database, cfg *config.Config) *WebhookService {
+	return &WebhookService{
+		db:     db,
+		cfg:    &cfg.Webhook,
+		client: newWebhookClient(cfg.Webhook.Timeout),
+	}
+}
+
+// GenerateWebhookSecret returns a random secret used to sign deliveries.
+func GenerateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// SignWebhookPayload computes the signature header value for a delivery body.
+// Receivers recompute HMAC-SHA256 over "<timestamp>.<body>" with their secret
+// and compare it against v1.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// Enqueue queues a delivery for every active subscription of the restaurant
+// that listens to eventType. The event ID is sent as the envelope ID so
+// receivers can deduplicate repeated deliveries of the same event.
+func (s *WebhookService) Enqueue(tx *gorm.DB, eventID uuid.UUID, restaurantID uuid.UUID, eventType models.EventType, data interface{}) error {
+	var subscriptions []models.WebhookSubscription
+	if err := tx.Where("restaurant_id = ? AND is_active = ?", restaurantID, true).Find(&subscriptions).Error; err != nil {
+		return err
+	}
+
+	envelope := WebhookEnvelope{
+		ID:        eventID,
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %v", err)
+	}
+
+	for _, subscription := range subscriptions {
+		if !subscription.Subscribes(eventType) {
+			continue
+		}
+
+		delivery := models.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			RestaurantID:   restaurantID,
+			EventID:        envelope.ID,
+			EventType:      eventType,
+			Payload:        string(body),
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  time.Now(),
+		}
+		if err := tx.Create(&delivery).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Name identifies the webhook consumer in the outbox.
+func (s *WebhookService) Name() string {
+	return "webhooks"
+}
+
+// Handle turns order and stock events from the outbox into queued deliveries.
+func (s *WebhookService) Handle(tx *gorm.DB, event *models.OutboxEvent) error {
+	if !models.IsWebhookEventType(event.EventType) {
+		return nil
+	}
+
+	if event.EventType == models.MenuLowStockEvent {
+		payload, err := decodeStockEvent(event)
+		if err != nil {
+			return err
+		}
+		return s.Enqueue(tx, event.ID, payload.RestaurantID, event.EventType, json.RawMessage(event.Payload))
+	}
+
+	payload, err := decodeOrderEvent(event)
+	if err != nil {
+		return err
+	}
+
+	return s.Enqueue(tx, event.ID, payload.RestaurantID, event.EventType, json.RawMessage(event.Payload))
+}
+
+// Redeliver resets a delivery so the worker picks it up again on its next poll.
+func (s *WebhookService) Redeliver(delivery *models.WebhookDelivery) error {
+	return s.db.DB.Model(delivery).Updates(map[string]interface{}{
+		"status":          models.WebhookDeliveryPending,
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+		"last_error":      "",
+	}).Error
+}
+
+// Start polls the delivery queue until ctx is cancelled.
+func (s *WebhookService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.processDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *WebhookService) processDue(ctx context.Context) {
+	deliveries, err := s.claimDue()
+	if err != nil {
+		log.Printf("webhooks: failed to claim deliveries: %v", err)
+		return
+	}
+
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			return
+		}
+		s.attempt(ctx, &deliveries[i])
+	}
+}
+
+// claimDue locks a batch of due deliveries and pushes their next attempt past
+// the request timeout, so concurrent workers don't send the same delivery.
+func (s *WebhookService) claimDue() ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+
+	err := s.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Preload("Subscription").
+			Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, time.Now()).
+			Order("next_attempt_at ASC").
+			Limit(s.cfg.BatchSize).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+		}
+
+		lease := time.Now().Add(2 * s.cfg.Timeout)
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", lease).Error
+	})
+
+	return deliveries, err
+}
+
+func (s *WebhookService) attempt(ctx context.Context, delivery *models.WebhookDelivery) {
+	updates := s.deliver(ctx, delivery, time.Now())
+	if err := s.db.DB.Model(delivery).Updates(updates).Error; err != nil {
+		log.Printf("webhooks: failed to record attempt for delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// deliver sends a delivery once and returns what to record on it: success,
+// a retry after backoff, or dead once MaxAttempts is used up.
+func (s *WebhookService) deliver(ctx context.Context, delivery *models.WebhookDelivery, now time.Time) map[string]interface{} {
+	updates := map[string]interface{}{
+		"attempts":        delivery.Attempts + 1,
+		"last_attempt_at": now,
+	}
+
+	statusCode, err := s.send(ctx, delivery)
+	updates["last_response_code"] = statusCode
+
+	switch {
+	case err == nil:
+		updates["status"] = models.WebhookDeliverySucceeded
+		updates["delivered_at"] = now
+		updates["last_error"] = ""
+	case delivery.Attempts+1 >= s.cfg.MaxAttempts:
+		updates["status"] = models.WebhookDeliveryDead
+		updates["last_error"] = err.Error()
+	default:
+		updates["next_attempt_at"] = now.Add(webhookBackoff(delivery.Attempts + 1))
+		updates["last_error"] = err.Error()
+	}
+	return updates
+}
+
+func (s *WebhookService) send(ctx context.Context, delivery *models.WebhookDelivery) (int, error) {
+	if !delivery.Subscription.IsActive {
+		return 0, fmt.Errorf("subscription is disabled")
+	}
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "RestaurantApp-Webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, string(delivery.EventType))
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID.String())
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(delivery.Subscription.Secret, time.Now().Unix(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	// Redirects aren't followed, so they count as failures too
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// webhookBackoff doubles the wait after every failed attempt, capped at webhookMaxBackoff.
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"restaurantapp/config"
+	"restaurantapp/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const testWebhookSecret = "whsec_test"
+
+// newTestReceiver starts a receiver that checks every delivery's signature
+// and answers with the status codes given, then 200.
+func newTestReceiver(t *testing.T, statuses ...int) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading body: %v", err)
+		}
+		if err := checkTestSignature(r.Header.Get(WebhookSignatureHeader), body); err != nil {
+			t.Errorf("signature: %v", err)
+		}
+		if r.Header.Get(WebhookEventHeader) != string(models.OrderCreatedEvent) {
+			t.Errorf("event header = %q", r.Header.Get(WebhookEventHeader))
+		}
+
+		call := int(atomic.AddInt32(&calls, 1))
+		if call <= len(statuses) {
+			w.WriteHeader(statuses[call-1])
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+// checkTestSignature verifies a "t=<unix>,v1=<hex hmac>" header the way a
+// receiver would.
+func checkTestSignature(header string, body []byte) error {
+	var timestamp, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+	if timestamp == "" || signature == "" {
+		return errors.New("malformed header " + header)
+	}
+
+	mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	if !hmac.Equal([]byte(signature), []byte(hex.EncodeToString(mac.Sum(nil)))) {
+		return errors.New("v1 doesn't match the body")
+	}
+	return nil
+}
+
+func newTestDelivery(url string) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:        uuid.New(),
+		EventType: models.OrderCreatedEvent,
+		Payload:   `{"type":"order.created"}`,
+		Status:    models.WebhookDeliveryPending,
+		Subscription: models.WebhookSubscription{
+			URL:      url,
+			Secret:   testWebhookSecret,
+			IsActive: true,
+		},
+	}
+}
+
+func TestWebhookDeliveryRetriesUntilSuccess(t *testing.T) {
+	server, calls := newTestReceiver(t, http.StatusInternalServerError, http.StatusServiceUnavailable)
+	service := &WebhookService{
+		cfg:    &config.WebhookConfig{MaxAttempts: 5, Timeout: time.Second},
+		client: server.Client(),
+	}
+	delivery := newTestDelivery(server.URL)
+	now := time.Now()
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		updates := service.deliver(context.Background(), delivery, now)
+		if updates["attempts"] != attempt {
+			t.Fatalf("attempt %d: attempts = %v", attempt, updates["attempts"])
+		}
+
+		if attempt < 3 {
+			if _, done := updates["status"]; done {
+				t.Fatalf("attempt %d: status = %v, want a retry", attempt, updates["status"])
+			}
+			if want := now.Add(webhookBackoff(attempt)); updates["next_attempt_at"] != want {
+				t.Fatalf("attempt %d: next attempt at %v, want %v", attempt, updates["next_attempt_at"], want)
+			}
+		} else if updates["status"] != models.WebhookDeliverySucceeded {
+			t.Fatalf("attempt %d: status = %v, want succeeded", attempt, updates["status"])
+		}
+		delivery.Attempts = attempt
+	}
+
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Fatalf("receiver got %d requests, want 3", got)
+	}
+}
+
+func TestWebhookDeliveryGoesDead(t *testing.T) {
+	server, _ := newTestReceiver(t, http.StatusInternalServerError, http.StatusInternalServerError)
+	service := &WebhookService{
+		cfg:    &config.WebhookConfig{MaxAttempts: 2, Timeout: time.Second},
+		client: server.Client(),
+	}
+	delivery := newTestDelivery(server.URL)
+
+	updates := service.deliver(context.Background(), delivery, time.Now())
+	if _, done := updates["status"]; done {
+		t.Fatalf("first attempt: status = %v, want a retry", updates["status"])
+	}
+	delivery.Attempts = 1
+
+	updates = service.deliver(context.Background(), delivery, time.Now())
+	if updates["status"] != models.WebhookDeliveryDead {
+		t.Fatalf("last attempt: status = %v, want dead", updates["status"])
+	}
+	if updates["last_response_code"] != http.StatusInternalServerError {
+		t.Fatalf("last response code = %v", updates["last_response_code"])
+	}
+}
+
+func TestWebhookClientRefusesInternalAddresses(t *testing.T) {
+	server, calls := newTestReceiver(t)
+	client := newWebhookClient(time.Second)
+
+	_, err := client.Post(server.URL, "application/json", strings.NewReader("{}"))
+	if !errors.Is(err, ErrWebhookAddressNotAllowed) {
+		t.Fatalf("posting to %s: err = %v, want ErrWebhookAddressNotAllowed", server.URL, err)
+	}
+	if got := atomic.LoadInt32(calls); got != 0 {
+		t.Fatalf("receiver got %d requests, want none", got)
+	}
+
+	redirect, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	if err := client.CheckRedirect(redirect, nil); err != http.ErrUseLastResponse {
+		t.Fatalf("CheckRedirect = %v, want http.ErrUseLastResponse", err)
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for address, public := range map[string]bool{
+		"93.184.216.34":   true,
+		"2606:4700::1111": true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"100.100.100.200": false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"fd00::1":         false,
+		"fe80::1":         false,
+		"::ffff:10.0.0.1": false,
+	} {
+		if got := isPublicIP(net.ParseIP(address)); got != public {
+			t.Errorf("isPublicIP(%s) = %v, want %v", address, got, public)
+		}
+	}
+
+	if err := CheckWebhookHost(context.Background(), "169.254.169.254"); err != ErrWebhookAddressNotAllowed {
+		t.Errorf("CheckWebhookHost(metadata address) = %v", err)
+	}
+}